@@ -0,0 +1,79 @@
+"""
+Analytics over Chainlink round history
+Realized volatility and related statistics computed from round answers
+"""
+
+import math
+from typing import Callable, Dict, List, Optional, Tuple, Any, Final
+
+SECONDS_PER_YEAR: Final[int] = 365 * 24 * 60 * 60
+
+# (roundId, answer, startedAt, updatedAt, answeredInRound) as returned by getRoundData
+RoundFields = Tuple[int, int, int, int, int]
+
+
+class InsufficientHistoryError(ValueError):
+    """Raised when a feed has too few usable rounds for the requested window"""
+    def __init__(self, symbol: str, window: int) -> None:
+        super().__init__(
+            f"Not enough round history to compute volatility for '{symbol}' over {window} rounds"
+        )
+
+
+def completed_rounds(
+    results: List[Tuple[bool, bytes]], decode: Callable[[bytes], RoundFields]
+) -> List[RoundFields]:
+    """
+    Decode getRoundData() results read through aggregate3 with allowFailure.
+    A round missing from the history, common across phase boundaries, either
+    reverts or reads as zeros; it is skipped rather than losing the whole series.
+    """
+    rounds: List[RoundFields] = []
+    for success, data in results:
+        if not success:
+            continue
+        try:
+            fields = decode(data)
+        except Exception:
+            continue
+        if fields[3] == 0:
+            continue
+        rounds.append(fields)
+    return rounds
+
+
+def compute_realized_volatility(points: List[Tuple[float, int]]) -> Optional[Dict[str, Any]]:
+    """
+    Compute realized volatility from (price, unix timestamp) points.
+    Rounds report at irregular intervals, so the per-round standard deviation is
+    annualized using the mean time between rounds. Returns None when fewer than
+    two usable log returns are available.
+    """
+    ordered = sorted(points, key=lambda point: point[1])
+
+    returns: List[float] = []
+    for (prev_price, _), (curr_price, _) in zip(ordered, ordered[1:]):
+        # Log returns are undefined for non-positive answers
+        if prev_price <= 0 or curr_price <= 0:
+            continue
+        returns.append(math.log(curr_price / prev_price))
+
+    if len(returns) < 2:
+        return None
+
+    first_ts = ordered[0][1]
+    last_ts = ordered[-1][1]
+    if last_ts <= first_ts:
+        return None
+
+    mean = sum(returns) / len(returns)
+    variance = sum((r - mean) ** 2 for r in returns) / (len(returns) - 1)
+    std_dev = math.sqrt(variance)
+    mean_interval = (last_ts - first_ts) / (len(ordered) - 1)
+
+    return {
+        "observations": len(returns),
+        "meanIntervalSeconds": mean_interval,
+        "stdDev": std_dev,
+        "realizedVolatility": std_dev * math.sqrt(SECONDS_PER_YEAR / mean_interval)
+    }
@@ -0,0 +1,122 @@
+"""
+Availability tracking for feeds and RPC endpoints
+Keeps a rolling record of successful and failed reads so gaps can be
+attributed to the provider or the feed causing them
+"""
+
+import os
+import json
+import time
+from collections import deque
+from urllib.parse import urlsplit
+from datetime import datetime, timezone
+from typing import Deque, Dict, List, Optional, Tuple, Any, Final
+
+# 24 hours of 5-minute refreshes
+DEFAULT_AVAILABILITY_WINDOW: Final[int] = 288
+
+# (ok, unix milliseconds)
+Sample = Tuple[bool, int]
+
+
+DEFAULT_PORTS: Final[Dict[str, int]] = {'http': 80, 'https': 443, 'ws': 80, 'wss': 443}
+
+
+def _iso(ms: int) -> str:
+    return datetime.fromtimestamp(ms / 1000, tz=timezone.utc).isoformat()
+
+
+def rpc_key(url: str) -> str:
+    """RPC endpoints are reported by origin only: API keys often live in the URL path or query"""
+    try:
+        parts = urlsplit(url)
+        port = parts.port
+    except ValueError:
+        return 'invalid-url'
+    if not parts.scheme or not parts.hostname:
+        return 'invalid-url'
+    host = f"[{parts.hostname}]" if ':' in parts.hostname else parts.hostname
+    suffix = f":{port}" if port is not None and port != DEFAULT_PORTS.get(parts.scheme) else ''
+    return f"{parts.scheme}://{host}{suffix}"
+
+
+class AvailabilityTracker:
+    """Rolling success/failure samples per feed symbol and per RPC origin"""
+    
+    def __init__(self, window_size: int = DEFAULT_AVAILABILITY_WINDOW,
+                 persist_path: Optional[str] = None) -> None:
+        self.window_size = window_size
+        self.persist_path = persist_path if persist_path is not None else os.environ.get('AVAILABILITY_FILE')
+        self.feeds: Dict[str, Deque[Sample]] = {}
+        self.rpcs: Dict[str, Deque[Sample]] = {}
+        self._load()
+    
+    def record_feed(self, symbol: str, ok: bool, at: Optional[int] = None) -> None:
+        self._record(self.feeds, symbol, ok, at)
+    
+    def record_rpc(self, url: str, ok: bool, at: Optional[int] = None) -> None:
+        self._record(self.rpcs, rpc_key(url), ok, at)
+    
+    def get_report(self) -> Dict[str, Any]:
+        return {
+            "window": self.window_size,
+            "rpcs": self._summarize(self.rpcs),
+            "feeds": self._summarize(self.feeds)
+        }
+    
+    def save(self) -> None:
+        """Write the current window to disk so availability survives restarts"""
+        if not self.persist_path:
+            return
+        snapshot = {
+            "feeds": {key: [{"ok": ok, "at": at} for ok, at in samples] for key, samples in self.feeds.items()},
+            "rpcs": {key: [{"ok": ok, "at": at} for ok, at in samples] for key, samples in self.rpcs.items()}
+        }
+        try:
+            with open(self.persist_path, 'w') as f:
+                json.dump(snapshot, f)
+        except OSError as e:
+            print(f"Warning: Could not persist availability to {self.persist_path}: {e}")
+    
+    def _load(self) -> None:
+        if not self.persist_path or not os.path.exists(self.persist_path):
+            return
+        try:
+            with open(self.persist_path, 'r') as f:
+                snapshot = json.load(f)
+            for store, name in ((self.feeds, "feeds"), (self.rpcs, "rpcs")):
+                for key, samples in snapshot.get(name, {}).items():
+                    store[key] = deque(
+                        ((bool(s["ok"]), int(s["at"])) for s in samples),
+                        maxlen=self.window_size
+                    )
+        except (OSError, ValueError, KeyError, TypeError) as e:
+            print(f"Warning: Ignoring unreadable availability file {self.persist_path}: {e}")
+    
+    def _record(self, store: Dict[str, Deque[Sample]], key: str, ok: bool, at: Optional[int]) -> None:
+        samples = store.setdefault(key, deque(maxlen=self.window_size))
+        samples.append((ok, at if at is not None else int(time.time() * 1000)))
+    
+    def _summarize(self, store: Dict[str, Deque[Sample]]) -> List[Dict[str, Any]]:
+        """Least available first; failureShare attributes each key's portion of all failures"""
+        total_failures = sum(1 for samples in store.values() for ok, _ in samples if not ok)
+        
+        records: List[Dict[str, Any]] = []
+        for key, samples in store.items():
+            successes = sum(1 for ok, _ in samples if ok)
+            failures = len(samples) - successes
+            last_success = next((at for ok, at in reversed(samples) if ok), None)
+            last_failure = next((at for ok, at in reversed(samples) if not ok), None)
+            records.append({
+                "key": key,
+                "successes": successes,
+                "failures": failures,
+                "availability": successes / len(samples) if samples else 1.0,
+                "failureShare": failures / total_failures if total_failures else 0.0,
+                "since": _iso(samples[0][1]) if samples else _iso(int(time.time() * 1000)),
+                "lastSuccess": _iso(last_success) if last_success is not None else None,
+                "lastFailure": _iso(last_failure) if last_failure is not None else None
+            })
+        
+        records.sort(key=lambda r: (r["availability"], -r["failures"]))
+        return records
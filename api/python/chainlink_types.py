@@ -11,10 +11,10 @@ from decimal import Decimal
 
 # Blockchain Types
 class ChainId(int):
-    """Type-safe chain ID (43114 for Avalanche C-Chain)"""
+    """Type-safe chain ID (43114 for Avalanche C-Chain, 43113 for Fuji)"""
     def __new__(cls, value: int) -> ChainId:
-        if value != 43114:
-            raise ValueError(f"Invalid chain ID {value}, expected 43114 for Avalanche C-Chain")
+        if value not in (43114, 43113):
+            raise ValueError(f"Invalid chain ID {value}, expected 43114 (C-Chain) or 43113 (Fuji)")
         return super().__new__(cls, value)
 
 class Address(str):
@@ -92,6 +92,23 @@ class RawRoundDataDict(TypedDict):
     updatedAt: str
     answeredInRound: str
 
+class ExchangeRateFeedDict(TypedDict):
+    """ERC-4626 or LST exchange-rate feed from custom_feeds.json"""
+    name: str
+    symbol: str
+    type: str
+    address: str
+    method: str
+    decimals: int
+
+class GenericOracleFeedDict(TypedDict):
+    """ERC-2362 oracle feed from custom_feeds.json, read with valueFor(id)"""
+    name: str
+    symbol: str
+    address: str
+    id: bytes  # bytes32
+    decimals: int
+
 class MulticallResult(TypedDict):
     """Multicall3 aggregation result"""
     blockNumber: int
@@ -144,6 +161,7 @@ class ErrorCode(str, Enum):
     PRICE_NOT_FOUND = "PRICE_NOT_FOUND"
     ROUND_NOT_FOUND = "ROUND_NOT_FOUND"
     RESERVE_NOT_FOUND = "RESERVE_NOT_FOUND"
+    INSUFFICIENT_HISTORY = "INSUFFICIENT_HISTORY"
     BLOCKCHAIN_ERROR = "BLOCKCHAIN_ERROR"
     VALIDATION_ERROR = "VALIDATION_ERROR"
     INTERNAL_ERROR = "INTERNAL_ERROR"
@@ -221,7 +239,7 @@ def is_valid_address(value: Any) -> bool:
 
 def is_valid_chain_id(value: Any) -> bool:
     """Type guard for valid chain ID"""
-    return isinstance(value, int) and value == 43114
+    return isinstance(value, int) and value in (43114, 43113)
 
 def is_price_value(value: Any) -> bool:
     """Type guard for valid price value"""
@@ -233,8 +251,10 @@ def is_price_value(value: Any) -> bool:
 
 # Constants with proper typing
 AVALANCHE_CHAIN_ID: ChainId = ChainId(43114)
+FUJI_CHAIN_ID: ChainId = ChainId(43113)
 MULTICALL3_ADDRESS: Address = Address("0xcA11bde05977b3631167028862bE2a173976CA11")
 AVALANCHE_RPC_URL: str = "https://api.avax.network/ext/bc/C/rpc"
+FUJI_RPC_URL: str = "https://api.avax-test.network/ext/bc/C/rpc"
 
 # Type mapping for CSV field conversion
 CSV_FIELD_TYPES: Dict[str, type] = {
@@ -253,7 +273,7 @@ __all__ = [
     'AssetClass', 'ProductType', 'ApiStatus', 'ErrorCode',
     
     # TypedDict definitions
-    'FeedMetadataDict', 'RawRoundDataDict', 'MulticallResult', 'NetworkInfo',
+    'FeedMetadataDict', 'RawRoundDataDict', 'ExchangeRateFeedDict', 'MulticallResult', 'NetworkInfo',
     'ErrorDetail', 'RefreshResult', 'ApiResponseDict',
     
     # Protocols
@@ -270,6 +290,6 @@ __all__ = [
     'is_valid_address', 'is_valid_chain_id', 'is_price_value',
     
     # Constants
-    'AVALANCHE_CHAIN_ID', 'MULTICALL3_ADDRESS', 'AVALANCHE_RPC_URL',
+    'AVALANCHE_CHAIN_ID', 'FUJI_CHAIN_ID', 'MULTICALL3_ADDRESS', 'AVALANCHE_RPC_URL', 'FUJI_RPC_URL',
     'CSV_FIELD_TYPES',
 ]
\ No newline at end of file
@@ -0,0 +1,50 @@
+"""
+Chain registry
+RPC and Multicall3 per chain, selected with CHAIN (default avalanche).
+Testnets read their own feed file and are flagged in /health so their
+numbers are never taken for mainnet ones.
+"""
+
+import os
+from typing import Dict, Final, Optional, TypedDict
+
+from chainlink_types import (
+    AVALANCHE_CHAIN_ID, FUJI_CHAIN_ID, MULTICALL3_ADDRESS, AVALANCHE_RPC_URL, FUJI_RPC_URL
+)
+
+
+class ChainConfigDict(TypedDict):
+    name: str
+    chainId: int
+    testnet: bool
+    rpcUrl: str
+    multicall3: str
+    feedsFile: str
+
+
+CHAINS: Final[Dict[str, ChainConfigDict]] = {
+    'avalanche': {
+        'name': 'avalanche',
+        'chainId': AVALANCHE_CHAIN_ID,
+        'testnet': False,
+        'rpcUrl': AVALANCHE_RPC_URL,
+        'multicall3': MULTICALL3_ADDRESS,
+        'feedsFile': 'avalanche_chainlink_feeds.csv',
+    },
+    # Public endpoint without an API key; reads are eth_calls, so no funded account either
+    'fuji': {
+        'name': 'fuji',
+        'chainId': FUJI_CHAIN_ID,
+        'testnet': True,
+        'rpcUrl': FUJI_RPC_URL,
+        'multicall3': MULTICALL3_ADDRESS,
+        'feedsFile': 'fuji_chainlink_feeds.csv',
+    },
+}
+
+
+def resolve_chain(name: Optional[str] = None) -> ChainConfigDict:
+    chain = name or os.environ.get('CHAIN') or 'avalanche'
+    if chain not in CHAINS:
+        raise ValueError(f'Unknown chain "{chain}", expected one of {", ".join(CHAINS)}')
+    return CHAINS[chain]
@@ -0,0 +1,135 @@
+"""
+Fault Injection
+Chaos/testing mode that injects RPC timeouts, partial multicall failures,
+corrupted return data and storage outages on a schedule of refresh steps.
+
+CHAOS="timeout@2,partial@3-4,corrupt/5,storage@6"
+`@N` fires on refresh N, `@N-M` on refreshes N through M, `/K` on every Kth refresh.
+CHAOS, CHAOS_SEED and CHAOS_RATE are read the same way as in the TypeScript API.
+"""
+
+import os
+import re
+import math
+from typing import Callable, Dict, Final, List, Literal, Mapping, Optional, Sequence, Set, Tuple, TypeVar, Union, cast
+
+FaultType = Literal['timeout', 'partial', 'corrupt', 'storage']
+
+FAULT_TYPES: Final[Tuple[str, ...]] = ('timeout', 'partial', 'corrupt', 'storage')
+
+_FAULT: Final[re.Pattern[str]] = re.compile(r'^([a-z]+)(?:@(\d+)(?:-(\d+))?|/(\d+))$')
+_MASK: Final[int] = 0xFFFFFFFF
+
+T = TypeVar('T')
+
+# {'type', 'from', 'to'} for a step range, {'type', 'every'} for a repeating fault
+ScheduledFault = Dict[str, Union[str, int]]
+
+
+def parse_fault_schedule(spec: Optional[str]) -> List[ScheduledFault]:
+    if not spec:
+        return []
+
+    schedule: List[ScheduledFault] = []
+    for part in (p.strip() for p in spec.split(',')):
+        if not part:
+            continue
+        match = _FAULT.match(part)
+        if not match or match.group(1) not in FAULT_TYPES:
+            raise ValueError(
+                f'Invalid fault "{part}": expected one of {", ".join(FAULT_TYPES)} followed by @N, @N-M or /K'
+            )
+
+        fault_type, start, end, every = match.groups()
+        if every:
+            schedule.append({'type': fault_type, 'every': int(every)})
+        else:
+            schedule.append({'type': fault_type, 'from': int(start), 'to': int(end or start)})
+    return schedule
+
+
+def _imul(a: int, b: int) -> int:
+    return (a * b) & _MASK
+
+
+def _seeded_random(seed: int) -> Callable[[], float]:
+    """Small seeded generator so a schedule hits the same entries on every run (mulberry32, as in the TS API)"""
+    state = seed & _MASK
+
+    def next_random() -> float:
+        nonlocal state
+        state = (state + 0x6D2B79F5) & _MASK
+        t = _imul(state ^ (state >> 15), state | 1)
+        t ^= (t + _imul(t ^ (t >> 7), t | 61)) & _MASK
+        return ((t ^ (t >> 14)) & _MASK) / 4294967296
+
+    return next_random
+
+
+class FaultInjector:
+    def __init__(self, spec: Optional[str], seed: int = 1, rate: float = 0.25) -> None:
+        self.schedule: List[ScheduledFault] = parse_fault_schedule(spec)
+        self.rate: float = rate
+        self.step: int = 0
+        self._random: Callable[[], float] = _seeded_random(seed)
+
+    @classmethod
+    def from_env(cls, env: Mapping[str, str] = os.environ) -> 'FaultInjector':
+        """CHAOS enables injection; CHAOS_SEED and CHAOS_RATE tune which entries are hit"""
+        return cls(
+            env.get('CHAOS'),
+            int(env['CHAOS_SEED']) if env.get('CHAOS_SEED') else 1,
+            float(env['CHAOS_RATE']) if env.get('CHAOS_RATE') else 0.25,
+        )
+
+    @property
+    def enabled(self) -> bool:
+        return len(self.schedule) > 0
+
+    def next_step(self) -> int:
+        self.step += 1
+        return self.step
+
+    def active(self, fault_type: FaultType) -> bool:
+        for fault in self.schedule:
+            if fault['type'] != fault_type:
+                continue
+            if 'every' in fault:
+                if self.step % cast(int, fault['every']) == 0:
+                    return True
+            elif cast(int, fault['from']) <= self.step <= cast(int, fault['to']):
+                return True
+        return False
+
+    def call(self, fn: Callable[[], T]) -> T:
+        """Simulated RPC timeout"""
+        if self.active('timeout'):
+            raise TimeoutError(f"Injected RPC timeout (step {self.step})")
+        return fn()
+
+    def mangle_results(
+        self, results: List[Tuple[bool, bytes]], protected_indexes: Sequence[int] = ()
+    ) -> List[Tuple[bool, bytes]]:
+        """Failed calls come back empty; corrupted ones are cut short mid-word. Mangled entries still report success"""
+        entries = list(results)
+        if self.active('partial'):
+            for i in self._pick(len(entries), protected_indexes):
+                entries[i] = (entries[i][0], b'')
+        if self.active('corrupt'):
+            for i in self._pick(len(entries), protected_indexes):
+                success, data = entries[i]
+                entries[i] = (success, data[:math.floor(self._random() * len(data))])
+        return entries
+
+    def write(self, fn: Callable[[], T]) -> T:
+        if self.active('storage'):
+            raise OSError(f"Injected storage outage (step {self.step})")
+        return fn()
+
+    def _pick(self, count: int, protected_indexes: Sequence[int]) -> Set[int]:
+        """Picks at least one entry so a scheduled fault is never a silent no-op"""
+        candidates = [i for i in range(count) if i not in protected_indexes]
+        chosen = [i for i in candidates if self._random() < self.rate]
+        if not chosen and candidates:
+            chosen.append(candidates[math.floor(self._random() * len(candidates))])
+        return set(chosen)
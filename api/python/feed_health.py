@@ -0,0 +1,177 @@
+"""
+Feed health grading
+Folds time-weighted staleness, update regularity, decode failures and
+deviation anomalies into one score and letter grade per feed, refreshed
+every price cycle, so consumers can gate on a single field before trusting
+a price
+"""
+
+import os
+import json
+import time
+from datetime import datetime, timezone
+from typing import Dict, List, Optional, Tuple, Any, Final
+
+FEED_HEALTH_WEIGHTS: Final[Dict[str, float]] = {
+    'staleness': 0.35,
+    'regularity': 0.25,
+    'decoding': 0.25,
+    'deviation': 0.15,
+}
+
+# Minimum score for each grade, best first
+FEED_HEALTH_GRADES: Final[Tuple[Tuple[str, float], ...]] = (
+    ('A', 90),
+    ('B', 75),
+    ('C', 50),
+    ('D', 25),
+    ('F', 0),
+)
+
+# Older staleness samples lose half their weight every hour
+DEFAULT_STALENESS_HALF_LIFE: Final[float] = 3600
+
+# A single move this many times the deviation threshold is anomalous; never below MIN_ANOMALY_MOVE percent
+DEFAULT_ANOMALY_FACTOR: Final[float] = 10
+MIN_ANOMALY_MOVE: Final[float] = 1
+
+MOVE_WINDOW: Final[int] = 50
+
+
+def _iso(seconds: float) -> str:
+    return datetime.fromtimestamp(seconds, tz=timezone.utc).isoformat(timespec='milliseconds').replace('+00:00', 'Z')
+
+
+def _float_from_env(name: str, default: float) -> float:
+    try:
+        return float(os.environ.get(name, '')) or default
+    except ValueError:
+        return default
+
+
+def score_components(components: Dict[str, Optional[float]]) -> float:
+    """Weighted mean of the components that could be measured, as 0-100"""
+    total = 0.0
+    weight = 0.0
+    for key, key_weight in FEED_HEALTH_WEIGHTS.items():
+        value = components.get(key)
+        if value is None:
+            continue
+        total += value * key_weight
+        weight += key_weight
+    return round(total / weight * 100, 1) if weight > 0 else 0.0
+
+
+def grade_for(score: float) -> str:
+    return next((grade for grade, minimum in FEED_HEALTH_GRADES if score >= minimum), 'F')
+
+
+class FeedHealthTracker:
+    """Per-feed staleness history and recent moves, graded once per refresh"""
+
+    def __init__(self, half_life: Optional[float] = None, anomaly_factor: Optional[float] = None,
+                 persist_path: Optional[str] = None) -> None:
+        self.half_life = half_life if half_life is not None else _float_from_env('FEED_HEALTH_HALF_LIFE', DEFAULT_STALENESS_HALF_LIFE)
+        self.anomaly_factor = anomaly_factor if anomaly_factor is not None else _float_from_env('DEVIATION_ANOMALY_FACTOR', DEFAULT_ANOMALY_FACTOR)
+        self.persist_path = persist_path if persist_path is not None else os.environ.get('FEED_HEALTH_FILE')
+        self.feeds: Dict[str, Dict[str, Any]] = {}
+        self.grades: Dict[str, Dict[str, Any]] = {}
+        self._load()
+
+    def observe(self, symbol: str, heartbeat: Optional[int], deviation_threshold: float,
+                answer: float, updated_at: int) -> None:
+        """Record a decoded answer; only a new updatedAt counts as a move"""
+        feed = self.feeds.setdefault(symbol, {
+            "heartbeat": heartbeat,
+            "deviationThreshold": deviation_threshold,
+            "lastAnswer": None,
+            "lastUpdatedAt": None,
+            "staleness": None,
+            "sampledAt": None,
+            "moves": []
+        })
+        feed["heartbeat"] = heartbeat
+        feed["deviationThreshold"] = deviation_threshold
+
+        if feed["lastUpdatedAt"] is None or updated_at > feed["lastUpdatedAt"]:
+            last_answer = feed["lastAnswer"]
+            if last_answer:
+                move_percent = abs(answer - last_answer) / abs(last_answer) * 100
+                moves: List[bool] = feed["moves"]
+                moves.append(move_percent > max(deviation_threshold * self.anomaly_factor, MIN_ANOMALY_MOVE))
+                del moves[:-MOVE_WINDOW]
+            feed["lastAnswer"] = answer
+            feed["lastUpdatedAt"] = updated_at
+
+    def refresh(self, frequency: Dict[str, Any], availability: Dict[str, Any], now: Optional[float] = None) -> None:
+        """Re-grade every known feed once per cycle, after all answers have been observed"""
+        now = now if now is not None else time.time()
+        regularity: Dict[str, Optional[float]] = {}
+        for record in frequency["feeds"]:
+            gaps = [w["maxGap"] for w in record["windows"] if w["maxGap"] is not None]
+            limit = record["heartbeat"] * frequency["tolerance"]
+            regularity[record["symbol"]] = min(1.0, limit / max(gaps)) if gaps else None
+        decoding = {record["key"]: record["availability"] for record in availability["feeds"]}
+
+        for symbol in set(self.feeds) | set(decoding):
+            feed = self.feeds.get(symbol)
+            moves: List[bool] = feed["moves"] if feed else []
+            components = {
+                "staleness": self._advance_staleness(feed, now) if feed else None,
+                "regularity": regularity.get(symbol),
+                "decoding": decoding.get(symbol),
+                "deviation": 1 - sum(moves) / len(moves) if moves else None
+            }
+            score = score_components(components)
+            self.grades[symbol] = {
+                "symbol": symbol,
+                "score": score,
+                "grade": grade_for(score),
+                "components": components,
+                "anomalies": sum(moves),
+                "gradedAt": _iso(now)
+            }
+
+    def get(self, symbol: str) -> Optional[Dict[str, Any]]:
+        return self.grades.get(symbol)
+
+    def get_report(self) -> Dict[str, Any]:
+        return {
+            "weights": dict(FEED_HEALTH_WEIGHTS),
+            "feeds": sorted(self.grades.values(), key=lambda r: r["score"])
+        }
+
+    def save(self) -> None:
+        """Write feed state to disk so staleness history and recent moves survive restarts"""
+        if not self.persist_path:
+            return
+        try:
+            with open(self.persist_path, 'w') as f:
+                json.dump(self.feeds, f)
+        except OSError as e:
+            print(f"Warning: Could not persist feed health to {self.persist_path}: {e}")
+
+    def _load(self) -> None:
+        if not self.persist_path or not os.path.exists(self.persist_path):
+            return
+        try:
+            with open(self.persist_path, 'r') as f:
+                self.feeds = json.load(f)
+        except (OSError, ValueError) as e:
+            print(f"Warning: Ignoring unreadable feed health file {self.persist_path}: {e}")
+
+    def _advance_staleness(self, feed: Dict[str, Any], now: float) -> Optional[float]:
+        """Age within one heartbeat scores 1, falling to 0 at two heartbeats; a sample's
+        weight grows with the time since the last one, so brief blips fade quickly"""
+        if not feed["heartbeat"] or feed["lastUpdatedAt"] is None:
+            return None
+
+        ratio = max(0, now - feed["lastUpdatedAt"]) / feed["heartbeat"]
+        if feed["staleness"] is None or feed["sampledAt"] is None:
+            feed["staleness"] = ratio
+        elif now > feed["sampledAt"]:
+            alpha = 1 - 2 ** (-(now - feed["sampledAt"]) / self.half_life)
+            feed["staleness"] += alpha * (ratio - feed["staleness"])
+        feed["sampledAt"] = max(feed["sampledAt"] if feed["sampledAt"] is not None else now, now)
+
+        return min(1.0, max(0.0, 2 - feed["staleness"]))
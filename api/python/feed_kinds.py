@@ -0,0 +1,70 @@
+"""
+Feed kinds
+Not every Chainlink feed is an asset price: exchange rates and macro rates can
+be zero or negative, indices are unitless levels, and PoR feeds report reserve
+amounts. The kind decides which answers are valid and which checks apply.
+"""
+
+import re
+from typing import Dict, Final, Literal, Optional, TypedDict, cast
+
+FeedKind = Literal['price', 'rate', 'index', 'por']
+
+
+class FeedKindRules(TypedDict):
+    allowNegative: bool
+    allowZero: bool
+    volatility: bool  # log-return volatility is only meaningful for positive, market-driven series
+
+
+FEED_KIND_RULES: Final[Dict[str, FeedKindRules]] = {
+    'price': FeedKindRules(allowNegative=False, allowZero=False, volatility=True),
+    'rate': FeedKindRules(allowNegative=True, allowZero=True, volatility=False),
+    'index': FeedKindRules(allowNegative=False, allowZero=True, volatility=True),
+    'por': FeedKindRules(allowNegative=False, allowZero=True, volatility=False),
+}
+
+_POR = re.compile(r'reserve', re.IGNORECASE)
+_RATE = re.compile(r'exchange[\s-]rate|percent change|\bnav\b', re.IGNORECASE)
+_INDEX = re.compile(r'\bindex\b|\blevel\b|emergency count', re.IGNORECASE)
+
+
+class UnsupportedFeedKindError(ValueError):
+    """Raised when an analysis is requested for a feed kind it does not apply to"""
+    def __init__(self, kind: str, analysis: str) -> None:
+        super().__init__(f"{analysis} is not meaningful for {kind} feeds")
+
+
+def classify_feed(name: str, kind: Optional[str] = None) -> FeedKind:
+    """An explicit kind (the feed list's optional kind column) wins over the name;
+    otherwise order matters: "PCE Price Index — Percent Change" is a rate, not an index"""
+    if kind:
+        if kind not in FEED_KIND_RULES:
+            raise ValueError(f"Unknown kind \"{kind}\" for {name}, expected one of {', '.join(FEED_KIND_RULES)}")
+        return cast(FeedKind, kind)
+    if _POR.search(name):
+        return 'por'
+    if _RATE.search(name):
+        return 'rate'
+    if _INDEX.search(name):
+        return 'index'
+    return 'price'
+
+
+def validate_answer(kind: str, answer: int) -> Optional[str]:
+    """Return why the answer is invalid for this kind, or None if it is acceptable"""
+    rules = FEED_KIND_RULES[kind]
+    if answer < 0 and not rules['allowNegative']:
+        return f"Negative answer {answer} is not valid for a {kind} feed"
+    if answer == 0 and not rules['allowZero']:
+        return f"Zero answer is not valid for a {kind} feed"
+    return None
+
+
+def format_answer(answer: int, decimals: int) -> str:
+    """Exact decimal rendering of an answer; unlike float() it never loses precision"""
+    sign = '-' if answer < 0 else ''
+    digits = str(abs(answer)).rjust(decimals + 1, '0')
+    whole = digits[:len(digits) - decimals]
+    fraction = digits[len(digits) - decimals:].rstrip('0') or '0'
+    return f"{sign}{whole}.{fraction}"
@@ -6,17 +6,33 @@ Provides comprehensive access to all Chainlink price feeds on Avalanche C-Chain
 import os
 import time
 from contextlib import asynccontextmanager
-from fastapi import FastAPI, HTTPException, Request
+from typing import Optional
+from zoneinfo import ZoneInfo
+from fastapi import FastAPI, HTTPException, Request, Query
 from fastapi.middleware.cors import CORSMiddleware
-from fastapi.responses import JSONResponse
+from fastapi.responses import JSONResponse, Response
+from prometheus_client import generate_latest, CONTENT_TYPE_LATEST
 import uvicorn
 
 from price_service import PriceService
 from models import (
     ApiResponse, ErrorResponse, HealthCheck, FeedMetadata, PriceData,
     PriceRefreshResponse, RoundData, FeedDescription, FeedVersion, 
-    FeedDecimals, ProofOfReserveData, ReservesSnapshot
+    FeedDecimals, ProofOfReserveData, ReservesSnapshot, VolatilityData,
+    AvailabilityReport, UpdateFrequencyReport, FeedHealthReport, Alert, SilenceList, Silence,
+    MaintenanceWindow, SilenceInput
 )
+from analytics import InsufficientHistoryError
+from feed_kinds import UnsupportedFeedKindError
+from time_utils import utc_now_iso, resolve_timezone, localize_timestamps
+from silences import SilenceValidationError
+from metrics import METRICS_REGISTRY
+from profiles import apply_profile
+
+# A profile fills in settings before anything reads them
+active_profile = apply_profile()
+if active_profile:
+    print(f"🧭 Using profile {active_profile}")
 
 # Global price service instance
 price_service: PriceService = None
@@ -78,10 +94,27 @@ async def global_exception_handler(request: Request, exc: Exception):
                 "code": "INTERNAL_ERROR",
                 "message": str(exc)
             },
-            timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+            timestamp=utc_now_iso()
         ).dict()
     )
 
+def _report_timezone(tz: Optional[str]) -> Optional[ZoneInfo]:
+    """Resolve the ?tz option of report endpoints, rejecting unknown zones with a 400"""
+    try:
+        return resolve_timezone(tz)
+    except ValueError as e:
+        raise HTTPException(
+            status_code=400,
+            detail={
+                "success": False,
+                "error": {
+                    "code": "VALIDATION_ERROR",
+                    "message": f"Validation error for tz: {e}"
+                },
+                "timestamp": utc_now_iso()
+            }
+        )
+
 # Health check endpoint
 @app.get("/health", response_model=HealthCheck, tags=["Health"])
 async def health_check():
@@ -95,7 +128,9 @@ async def health_check():
         version="1.0.0",
         uptime=time.time() - app_start_time,
         avalanche={
-            "chainId": 43114,
+            "chainId": network_info["chainId"],
+            "chain": network_info["chain"],
+            "testnet": network_info["testnet"],
             "blockNumber": network_info["blockNumber"],
             "connected": True
         },
@@ -106,6 +141,143 @@ async def health_check():
         }
     )
 
+@app.get("/health/availability", response_model=ApiResponse, tags=["Health"])
+async def get_availability(tz: Optional[str] = Query(None)):
+    """Rolling success/failure record per feed and per RPC endpoint, least available first"""
+    zone = _report_timezone(tz)
+    return ApiResponse(
+        success=True,
+        data=localize_timestamps(AvailabilityReport(**price_service.availability.get_report()).dict(), zone),
+        timestamp=utc_now_iso()
+    )
+
+@app.get("/health/update-frequency", response_model=ApiResponse, tags=["Health"])
+async def get_update_frequency(status: Optional[str] = Query(None), tz: Optional[str] = Query(None)):
+    """Observed update cadence per feed versus its documented heartbeat, slow feeds first"""
+    zone = _report_timezone(tz)
+    if status is not None and status not in ('ok', 'slow', 'insufficient-data'):
+        raise HTTPException(
+            status_code=400,
+            detail={
+                "success": False,
+                "error": {
+                    "code": "VALIDATION_ERROR",
+                    "message": "Validation error for status: must be one of ok, slow, insufficient-data"
+                },
+                "timestamp": utc_now_iso()
+            }
+        )
+    
+    report = price_service.update_frequency.get_report()
+    if status is not None:
+        report["feeds"] = [record for record in report["feeds"] if record["status"] == status]
+    
+    return ApiResponse(
+        success=True,
+        data=localize_timestamps(UpdateFrequencyReport(**report).dict(), zone),
+        timestamp=utc_now_iso()
+    )
+
+@app.get("/health/feeds", response_model=ApiResponse, tags=["Health"])
+async def get_feed_health(grade: Optional[str] = Query(None), tz: Optional[str] = Query(None)):
+    """Composite health score and grade per feed, worst first"""
+    zone = _report_timezone(tz)
+    if grade is not None and grade not in ('A', 'B', 'C', 'D', 'F'):
+        raise HTTPException(
+            status_code=400,
+            detail={
+                "success": False,
+                "error": {
+                    "code": "VALIDATION_ERROR",
+                    "message": "Validation error for grade: must be one of A, B, C, D, F"
+                },
+                "timestamp": utc_now_iso()
+            }
+        )
+    
+    report = price_service.feed_health.get_report()
+    if grade is not None:
+        report["feeds"] = [record for record in report["feeds"] if record["grade"] == grade]
+    
+    return ApiResponse(
+        success=True,
+        data=localize_timestamps(FeedHealthReport(**report).dict(), zone),
+        timestamp=utc_now_iso()
+    )
+
+# Prometheus scrape endpoint
+@app.get("/metrics", include_in_schema=False)
+async def metrics():
+    return Response(generate_latest(METRICS_REGISTRY), media_type=CONTENT_TYPE_LATEST)
+
+# Alert endpoints
+def _silence_validation_error(e: SilenceValidationError) -> HTTPException:
+    return HTTPException(
+        status_code=400,
+        detail={
+            "success": False,
+            "error": {
+                "code": "VALIDATION_ERROR",
+                "message": str(e)
+            },
+            "timestamp": utc_now_iso()
+        }
+    )
+
+@app.get("/alerts", response_model=ApiResponse, tags=["Alerts"])
+async def get_alerts(includeSilenced: bool = Query(False)):
+    """Alerts currently firing; silenced alerts are omitted unless includeSilenced=true"""
+    alerts = [Alert(**alert).dict() for alert in price_service.get_alerts()]
+    return ApiResponse(
+        success=True,
+        data=[alert for alert in alerts if includeSilenced or not alert["silenced"]],
+        timestamp=utc_now_iso()
+    )
+
+@app.get("/alerts/silences", response_model=ApiResponse, tags=["Alerts"])
+async def get_silences():
+    """Configured silences and maintenance windows"""
+    return ApiResponse(
+        success=True,
+        data=SilenceList(**price_service.silences.list()).dict(),
+        timestamp=utc_now_iso()
+    )
+
+@app.post("/alerts/silences", response_model=ApiResponse, status_code=201, tags=["Alerts"])
+async def create_silence(body: SilenceInput):
+    """Silence alerts for one feed and/or one alert type until endsAt"""
+    try:
+        silence = price_service.silences.add_silence(body.dict())
+    except SilenceValidationError as e:
+        raise _silence_validation_error(e)
+    return ApiResponse(success=True, data=Silence(**silence).dict(), timestamp=utc_now_iso())
+
+@app.post("/alerts/maintenance", response_model=ApiResponse, status_code=201, tags=["Alerts"])
+async def create_maintenance_window(body: SilenceInput):
+    """Silence every alert between startsAt and endsAt"""
+    try:
+        window = price_service.silences.add_maintenance_window(body.dict())
+    except SilenceValidationError as e:
+        raise _silence_validation_error(e)
+    return ApiResponse(success=True, data=MaintenanceWindow(**window).dict(), timestamp=utc_now_iso())
+
+@app.delete("/alerts/silences/{silence_id}", response_model=ApiResponse, tags=["Alerts"])
+async def delete_silence(silence_id: str):
+    """Remove a silence or maintenance window"""
+    if not price_service.silences.remove(silence_id):
+        raise HTTPException(
+            status_code=404,
+            detail={
+                "success": False,
+                "error": {
+                    "code": "SILENCE_NOT_FOUND",
+                    "message": f"Silence or maintenance window '{silence_id}' not found"
+                },
+                "timestamp": utc_now_iso()
+            }
+        )
+    return ApiResponse(success=True, data={"id": silence_id}, timestamp=utc_now_iso())
+
 # Feed endpoints
 @app.get("/feeds", response_model=ApiResponse, tags=["Feeds"])
 async def get_all_feeds():
@@ -115,7 +287,7 @@ async def get_all_feeds():
     return ApiResponse(
         success=True,
         data=[feed.dict() for feed in feeds],
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+        timestamp=utc_now_iso()
     )
 
 @app.get("/feeds/{symbol}", response_model=ApiResponse, tags=["Feeds"])
@@ -132,14 +304,14 @@ async def get_feed_by_symbol(symbol: str):
                     "code": "FEED_NOT_FOUND",
                     "message": f"Feed with symbol '{symbol}' not found"
                 },
-                "timestamp": time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+                "timestamp": utc_now_iso()
             }
         )
     
     return ApiResponse(
         success=True,
         data=feed.dict(),
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+        timestamp=utc_now_iso()
     )
 
 @app.get("/feeds/{symbol}/description", response_model=ApiResponse, tags=["Feeds"])
@@ -156,14 +328,14 @@ async def get_feed_description(symbol: str):
                     "code": "FEED_NOT_FOUND", 
                     "message": f"Feed with symbol '{symbol}' not found"
                 },
-                "timestamp": time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+                "timestamp": utc_now_iso()
             }
         )
     
     return ApiResponse(
         success=True,
         data=description,
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+        timestamp=utc_now_iso()
     )
 
 @app.get("/feeds/{symbol}/version", response_model=ApiResponse, tags=["Feeds"])
@@ -180,14 +352,14 @@ async def get_feed_version(symbol: str):
                     "code": "FEED_NOT_FOUND",
                     "message": f"Feed with symbol '{symbol}' not found"
                 },
-                "timestamp": time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+                "timestamp": utc_now_iso()
             }
         )
     
     return ApiResponse(
         success=True,
         data=version,
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+        timestamp=utc_now_iso()
     )
 
 @app.get("/feeds/{symbol}/decimals", response_model=ApiResponse, tags=["Feeds"])
@@ -204,14 +376,14 @@ async def get_feed_decimals(symbol: str):
                     "code": "FEED_NOT_FOUND",
                     "message": f"Feed with symbol '{symbol}' not found"
                 },
-                "timestamp": time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+                "timestamp": utc_now_iso()
             }
         )
     
     return ApiResponse(
         success=True,
         data=decimals,
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+        timestamp=utc_now_iso()
     )
 
 # Price endpoints
@@ -229,14 +401,14 @@ async def get_all_prices():
         return ApiResponse(
             success=True,
             data=[price.dict() for price in refreshed_prices],
-            timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ"),
+            timestamp=utc_now_iso(),
             blockNumber=network_info["blockNumber"]
         )
     
     return ApiResponse(
         success=True,
         data=[price.dict() for price in prices],
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ"),
+        timestamp=utc_now_iso(),
         blockNumber=network_info["blockNumber"]
     )
 
@@ -254,14 +426,14 @@ async def get_price_by_symbol(symbol: str):
                     "code": "PRICE_NOT_FOUND",
                     "message": f"Price for symbol '{symbol}' not found"
                 },
-                "timestamp": time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+                "timestamp": utc_now_iso()
             }
         )
     
     return ApiResponse(
         success=True,
         data=price.dict(),
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+        timestamp=utc_now_iso()
     )
 
 @app.post("/prices/refresh", response_model=ApiResponse, tags=["Prices"])
@@ -282,7 +454,7 @@ async def refresh_all_prices():
         return ApiResponse(
             success=True,
             data=refresh_response.dict(),
-            timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ"),
+            timestamp=utc_now_iso(),
             blockNumber=result["blockNumber"]
         )
         
@@ -296,26 +468,28 @@ async def refresh_all_prices():
                         "code": "REFRESH_IN_PROGRESS",
                         "message": "Price refresh already in progress"
                     },
-                    "timestamp": time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+                    "timestamp": utc_now_iso()
                 }
             )
         raise
 
 @app.get("/prices/reserves", response_model=ApiResponse, tags=["Prices"])
-async def get_all_reserves():
+async def get_all_reserves(tz: Optional[str] = Query(None)):
     """Get all Proof of Reserve data"""
+    zone = _report_timezone(tz)
     reserves_snapshot = await price_service.get_reserves_snapshot()
     
     return ApiResponse(
         success=True,
-        data=reserves_snapshot,
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ"),
+        data=localize_timestamps(reserves_snapshot, zone),
+        timestamp=utc_now_iso(),
         blockNumber=reserves_snapshot["blockNumber"]
     )
 
 @app.get("/prices/reserves/{symbol}", response_model=ApiResponse, tags=["Prices"])
-async def get_reserve_by_symbol(symbol: str):
+async def get_reserve_by_symbol(symbol: str, tz: Optional[str] = Query(None)):
     """Get Proof of Reserve data for specific asset"""
+    zone = _report_timezone(tz)
     reserve = await price_service.get_proof_of_reserve_by_symbol(symbol)
     
     if not reserve:
@@ -327,14 +501,14 @@ async def get_reserve_by_symbol(symbol: str):
                     "code": "RESERVE_NOT_FOUND",
                     "message": f"Proof of Reserve data for symbol '{symbol}' not found"
                 },
-                "timestamp": time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+                "timestamp": utc_now_iso()
             }
         )
     
     return ApiResponse(
         success=True,
-        data=reserve,
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+        data=localize_timestamps(reserve, zone),
+        timestamp=utc_now_iso()
     )
 
 # Round data endpoints
@@ -352,14 +526,78 @@ async def get_round_data(symbol: str, round_id: str):
                     "code": "ROUND_NOT_FOUND",
                     "message": f"Round {round_id} not found for symbol '{symbol}'"
                 },
-                "timestamp": time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+                "timestamp": utc_now_iso()
             }
         )
     
     return ApiResponse(
         success=True,
         data=round_data,
-        timestamp=time.strftime("%Y-%m-%dT%H:%M:%S.%fZ")
+        timestamp=utc_now_iso()
+    )
+
+@app.get("/feeds/{symbol}/volatility", response_model=ApiResponse, tags=["Feeds"])
+async def get_feed_volatility(symbol: str, window: int = Query(50), tz: Optional[str] = Query(None)):
+    """Get annualized realized volatility over the trailing rounds"""
+    if not 3 <= window <= 500:
+        raise HTTPException(
+            status_code=400,
+            detail={
+                "success": False,
+                "error": {
+                    "code": "VALIDATION_ERROR",
+                    "message": "Validation error for window: must be an integer between 3 and 500"
+                },
+                "timestamp": utc_now_iso()
+            }
+        )
+    
+    zone = _report_timezone(tz)
+    
+    try:
+        volatility = await price_service.get_volatility(symbol, window)
+    except UnsupportedFeedKindError as e:
+        raise HTTPException(
+            status_code=400,
+            detail={
+                "success": False,
+                "error": {
+                    "code": "VALIDATION_ERROR",
+                    "message": f"Validation error for symbol: {e}"
+                },
+                "timestamp": utc_now_iso()
+            }
+        )
+    except InsufficientHistoryError as e:
+        raise HTTPException(
+            status_code=422,
+            detail={
+                "success": False,
+                "error": {
+                    "code": "INSUFFICIENT_HISTORY",
+                    "message": str(e)
+                },
+                "timestamp": utc_now_iso()
+            }
+        )
+    
+    if not volatility:
+        raise HTTPException(
+            status_code=404,
+            detail={
+                "success": False,
+                "error": {
+                    "code": "FEED_NOT_FOUND",
+                    "message": f"Feed with symbol '{symbol}' not found"
+                },
+                "timestamp": utc_now_iso()
+            }
+        )
+    
+    return ApiResponse(
+        success=True,
+        data=localize_timestamps(VolatilityData(**volatility).dict(), zone),
+        timestamp=utc_now_iso()
     )
 
 if __name__ == "__main__":
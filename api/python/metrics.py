@@ -0,0 +1,56 @@
+"""
+Prometheus metrics
+Histogram of observed time between on-chain answer updates, labelled per
+chain and per feed, so alerts can fire on shifts in a feed's update
+distribution rather than only on point-in-time staleness. The composite
+health score and realized volatility are exported alongside as gauges.
+"""
+
+from typing import Final, Tuple
+from prometheus_client import CollectorRegistry, Gauge, Histogram, ProcessCollector, PlatformCollector
+
+METRICS_REGISTRY: Final[CollectorRegistry] = CollectorRegistry()
+ProcessCollector(registry=METRICS_REGISTRY)
+PlatformCollector(registry=METRICS_REGISTRY)
+
+# From sub-minute deviation updates up to two-day heartbeats
+UPDATE_INTERVAL_BUCKETS: Final[Tuple[float, ...]] = (
+    15, 30, 60, 120, 300, 600, 1800, 3600, 7200, 21600, 43200, 86400, 172800
+)
+
+UPDATE_INTERVAL_HISTOGRAM: Final[Histogram] = Histogram(
+    'chainlink_feed_update_interval_seconds',
+    'Observed seconds between consecutive on-chain answer updates',
+    labelnames=('chain', 'feed'),
+    buckets=UPDATE_INTERVAL_BUCKETS,
+    registry=METRICS_REGISTRY
+)
+
+
+def observe_update_interval(chain: str, feed: str, seconds: float) -> None:
+    UPDATE_INTERVAL_HISTOGRAM.labels(chain=chain, feed=feed).observe(seconds)
+
+
+FEED_HEALTH_GAUGE: Final[Gauge] = Gauge(
+    'chainlink_feed_health_score',
+    'Composite feed health score from 0 (untrustworthy) to 100',
+    labelnames=('chain', 'feed'),
+    registry=METRICS_REGISTRY
+)
+
+
+def set_feed_health_score(chain: str, feed: str, score: float) -> None:
+    FEED_HEALTH_GAUGE.labels(chain=chain, feed=feed).set(score)
+
+
+# Set whenever the volatility endpoint computes it; windows aren't comparable, so each gets its own series
+REALIZED_VOLATILITY_GAUGE: Final[Gauge] = Gauge(
+    'chainlink_feed_realized_volatility',
+    'Annualized realized volatility of log returns over the trailing window of rounds',
+    labelnames=('chain', 'feed', 'window'),
+    registry=METRICS_REGISTRY
+)
+
+
+def set_realized_volatility(chain: str, feed: str, window: int, volatility: float) -> None:
+    REALIZED_VOLATILITY_GAUGE.labels(chain=chain, feed=feed, window=str(window)).set(volatility)
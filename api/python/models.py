@@ -3,7 +3,7 @@ Pydantic models for Avalanche Chainlink API
 These models match the TypeScript types exactly to ensure API compatibility
 """
 
-from typing import List, Optional, Literal, Union
+from typing import Dict, List, Optional, Literal, Union
 from pydantic import BaseModel, Field, validator, ValidationError
 from datetime import datetime
 import re
@@ -12,6 +12,7 @@ import re
 class FeedMetadata(BaseModel):
     name: str
     symbol: str
+    kind: Literal['price', 'rate', 'index', 'por']  # what the answer represents
     contractAddress: str = Field(alias="contract_address")
     proxyAddress: str = Field(alias="proxy_address")
     decimals: int
@@ -67,14 +68,25 @@ class RawPriceData(BaseModel):
     answeredInRound: str
 
 
+class PriceHealth(BaseModel):
+    score: float  # 0-100
+    grade: Literal["A", "B", "C", "D", "F"]
+
+
 class PriceData(BaseModel):
     symbol: str
+    source: Literal['chainlink', 'erc4626', 'lst', 'erc2362']  # Chainlink feed, built-in exchange-rate read or ERC-2362 oracle
+    kind: Literal['price', 'rate', 'index', 'por']
     price: float
+    exactPrice: str  # full-precision decimal string of the answer
     decimals: int
     roundId: str
     updatedAt: str
+    blockTimestamp: str
+    ageAtBlock: int  # seconds between updatedAt and the sampled block, never negative
     proxyAddress: str
     raw: RawPriceData
+    health: Optional[PriceHealth] = None  # gate on grade before trusting the price
 
 
 class ApiResponse(BaseModel):
@@ -105,6 +117,110 @@ class HealthCheck(BaseModel):
     feeds: dict
 
 
+class AvailabilityRecord(BaseModel):
+    key: str  # feed symbol or RPC URL
+    successes: int
+    failures: int
+    availability: float  # 0..1 over the rolling window
+    failureShare: float  # this key's share of all failures in the window
+    since: str
+    lastSuccess: Optional[str]
+    lastFailure: Optional[str]
+
+
+class AvailabilityReport(BaseModel):
+    window: int  # samples retained per key
+    rpcs: List[AvailabilityRecord]
+    feeds: List[AvailabilityRecord]
+
+
+class UpdateFrequencyWindow(BaseModel):
+    window: str  # e.g. "24h"
+    coverage: int  # seconds of the window actually observed
+    updates: int  # distinct on-chain updates seen in the window
+    expectedUpdates: int  # minimum the heartbeat promises over the covered span
+    meanInterval: Optional[int]  # seconds between consecutive updates
+    maxGap: Optional[int]
+
+
+class UpdateFrequencyRecord(BaseModel):
+    symbol: str
+    heartbeat: int
+    deviationThreshold: float
+    lastUpdate: str
+    secondsSinceUpdate: int
+    status: Literal["ok", "slow", "insufficient-data"]
+    reasons: List[str]  # why the feed is considered slow
+    windows: List[UpdateFrequencyWindow]
+
+
+class UpdateFrequencyReport(BaseModel):
+    tolerance: float  # factor a gap may exceed the heartbeat by before alerting
+    windows: List[str]
+    feeds: List[UpdateFrequencyRecord]
+
+
+class FeedHealthComponents(BaseModel):
+    # Each component is 0-1, or None when it could not be measured yet
+    staleness: Optional[float]  # time-weighted age against the heartbeat
+    regularity: Optional[float]  # largest gap between updates against heartbeat × tolerance
+    decoding: Optional[float]  # share of reads that decoded and validated
+    deviation: Optional[float]  # share of recent moves that were not anomalous jumps
+
+
+class FeedHealthRecord(BaseModel):
+    symbol: str
+    score: float  # 0-100, weighted over the measured components
+    grade: Literal["A", "B", "C", "D", "F"]
+    components: FeedHealthComponents
+    anomalies: int
+    gradedAt: str
+
+
+class FeedHealthReport(BaseModel):
+    weights: Dict[str, float]
+    feeds: List[FeedHealthRecord]
+
+
+class Alert(BaseModel):
+    type: Literal["stale", "slow-updates", "rpc-failure"]
+    feed: Optional[str]  # None for alerts not tied to a feed
+    message: str
+    silenced: bool
+    silencedBy: Optional[str]  # id of the matching silence or maintenance window
+
+
+class Silence(BaseModel):
+    id: str
+    feed: Optional[str]  # None matches every feed
+    alertType: Optional[Literal["stale", "slow-updates", "rpc-failure"]]  # None matches every type
+    startsAt: str
+    endsAt: str
+    reason: str
+
+
+class MaintenanceWindow(BaseModel):
+    id: str
+    startsAt: str
+    endsAt: str
+    reason: str
+
+
+class SilenceList(BaseModel):
+    silences: List[Silence]
+    maintenanceWindows: List[MaintenanceWindow]
+    inMaintenance: bool
+
+
+class SilenceInput(BaseModel):
+    feed: Optional[str] = None
+    alertType: Optional[str] = None
+    startsAt: Optional[str] = None
+    endsAt: Optional[str] = None
+    durationMinutes: Optional[float] = None
+    reason: Optional[str] = None
+
+
 class PriceRefreshResponse(BaseModel):
     refreshed: bool
     totalFeeds: int
@@ -161,4 +277,15 @@ class ReservesSnapshot(BaseModel):
     timestamp: str
     blockNumber: str
     totalReserves: List[ProofOfReserveData]
-    summary: dict
\ No newline at end of file
+    summary: dict
+
+
+class VolatilityData(BaseModel):
+    symbol: str
+    window: int
+    observations: int
+    fromTimestamp: str
+    toTimestamp: str
+    meanIntervalSeconds: float
+    stdDev: float
+    realizedVolatility: float
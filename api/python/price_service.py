@@ -6,28 +6,98 @@ Handles all blockchain interactions and data management with comprehensive typin
 import os
 import csv
 import json
+import re
 import time
 import asyncio
-from typing import List, Dict, Optional, Any, Final, cast, Union
+from typing import List, Dict, Optional, Any, Final, Tuple, Set, cast, Union
 from datetime import datetime, timezone
 
 from web3 import Web3
 from web3.contract import Contract
+from web3.exceptions import ContractLogicError
 from web3.types import TxParams, BlockIdentifier
 import pandas as pd
 
 from models import (
-    FeedMetadata, PriceData, RawPriceData, RoundData, FeedDescription,
+    FeedMetadata, PriceData, PriceHealth, RawPriceData, RoundData, FeedDescription,
     FeedVersion, FeedDecimals, ProofOfReserveData, ReservesSnapshot
 )
 from chainlink_types import (
     ChainId, Address, BlockNumber, RoundId, Decimals, Heartbeat,
     PriceValue, TimestampStr, SymbolStr, NetworkInfo, ErrorCode,
-    FeedMetadataDict, RawRoundDataDict, MulticallResult, RefreshResult,
+    FeedMetadataDict, RawRoundDataDict, ExchangeRateFeedDict, GenericOracleFeedDict, MulticallResult, RefreshResult,
     Web3ContractProtocol, MulticallContractProtocol, ContractCall,
     AVALANCHE_CHAIN_ID, MULTICALL3_ADDRESS, AVALANCHE_RPC_URL,
     validate_symbol, validate_round_id, is_valid_address, CSV_FIELD_TYPES
 )
+from analytics import compute_realized_volatility, completed_rounds, InsufficientHistoryError, RoundFields
+from availability import AvailabilityTracker
+from update_frequency import UpdateFrequencyTracker
+from feed_health import FeedHealthTracker
+from fault_injection import FaultInjector
+from silences import SilenceManager
+from chains import ChainConfigDict, resolve_chain
+from contract_abis import AGGREGATOR_V3_INTERFACE_ABI, MULTICALL3_ABI
+from metrics import observe_update_interval, set_feed_health_score, set_realized_volatility
+from feed_kinds import (
+    FEED_KIND_RULES, UnsupportedFeedKindError, classify_feed, validate_answer, format_answer
+)
+
+# Function selectors are fixed, so compute them once rather than hashing per call
+LATEST_ROUND_DATA_SELECTOR: Final[bytes] = bytes(Web3.keccak(text='latestRoundData()')[:4])
+GET_ROUND_DATA_SELECTOR: Final[bytes] = bytes(Web3.keccak(text='getRoundData(uint80)')[:4])
+GET_CURRENT_BLOCK_TIMESTAMP_SELECTOR: Final[bytes] = bytes(Web3.keccak(text='getCurrentBlockTimestamp()')[:4])
+GET_BLOCK_NUMBER_SELECTOR: Final[bytes] = bytes(Web3.keccak(text='getBlockNumber()')[:4])
+ERROR_STRING_SELECTOR: Final[bytes] = bytes(Web3.keccak(text='Error(string)')[:4])
+
+# Exchange-rate getters for ERC-4626 vaults and LSTs, quoted for one whole share.
+# Maps method name to (signature, whether it takes a share amount)
+ONE_SHARE: Final[int] = 10 ** 18
+RATE_METHODS: Final[Dict[str, Tuple[str, bool]]] = {
+    'convertToAssets': ('convertToAssets(uint256)', True),
+    'getPooledAvaxByShares': ('getPooledAvaxByShares(uint256)', True),
+    'getExchangeRate': ('getExchangeRate()', False),
+    'exchangeRate': ('exchangeRate()', False),
+    'getRate': ('getRate()', False),
+}
+DEFAULT_RATE_METHOD: Final[Dict[str, str]] = {'erc4626': 'convertToAssets'}
+
+# ERC-2362 generic oracles serve many values from one contract through
+# valueFor(bytes32 id) -> (int256 value, uint256 timestamp, uint256 statusCode)
+VALUE_FOR_SELECTOR: Final[bytes] = bytes(Web3.keccak(text='valueFor(bytes32)')[:4])
+ERC2362_STATUS_OK: Final[int] = 200
+ORACLE_PAIR_ID: Final[re.Pattern[str]] = re.compile(r'^[a-z0-9]+-[a-z0-9]+-(\d+)$')
+BYTES32_ID: Final[re.Pattern[str]] = re.compile(r'^0x[0-9a-fA-F]{64}$')
+
+# Same suffixes as the TypeScript API, so a feed has one symbol in both
+SYMBOL_SUFFIXES: Final[Tuple[Tuple[str, str, str], ...]] = (
+    ('Proof of Reserves', 'PROOFOFRESERVES', '_POR'),
+    ('Emergency Count', 'EMERGENCYCOUNT', '_EMERGENCY'),
+    ('Exchange Rate', 'EXCHANGERATE', '_RATE'),
+)
+
+
+def extract_symbol(name: str) -> SymbolStr:
+    """Symbol from a feed name, letters only (e.g. "BTC / USD" -> "BTCUSD", "sAVAX / AVAX Exchange Rate" -> "SAVAXAVAX_RATE")"""
+    cleaned = re.sub(r'[^a-zA-Z]', '', name).upper()
+    for marker, letters, suffix in SYMBOL_SUFFIXES:
+        if marker in name:
+            return validate_symbol(cleaned.replace(letters, suffix, 1))
+    return validate_symbol(cleaned)
+
+
+def _gas_limit_from_env() -> Optional[int]:
+    """GAS_LIMIT is shared with the CLI; MULTICALL_GAS_LIMIT is the old API-only name, still read with a warning"""
+    if os.environ.get('MULTICALL_GAS_LIMIT') and not os.environ.get('GAS_LIMIT'):
+        print("Warning: MULTICALL_GAS_LIMIT is deprecated, set GAS_LIMIT instead")
+    name = 'GAS_LIMIT' if os.environ.get('GAS_LIMIT') else 'MULTICALL_GAS_LIMIT'
+    gas_limit = os.environ.get(name)
+    if not gas_limit:
+        return None
+    if not re.fullmatch(r'[0-9]+', gas_limit) or int(gas_limit) == 0:
+        raise ValueError(f'{name} must be a positive integer, got "{gas_limit}"')
+    return int(gas_limit)
+
 
 class PriceService:
     """Service for managing Chainlink price feed data on Avalanche with strict typing"""
@@ -35,6 +105,7 @@ class PriceService:
     # Class constants with proper typing
     CHAIN_ID: Final[ChainId] = AVALANCHE_CHAIN_ID
     RPC_URL: Final[str] = AVALANCHE_RPC_URL
+    CHAIN: Final[str] = 'avalanche'
     MULTICALL_ADDRESS: Final[Address] = MULTICALL3_ADDRESS
     
     def __init__(self) -> None:
@@ -42,18 +113,40 @@ class PriceService:
         self.w3: Optional[Web3] = None
         self.multicall_contract: Optional[MulticallContractProtocol] = None
         self.feeds: List[FeedMetadata] = []
+        self.exchange_rate_feeds: List[ExchangeRateFeedDict] = []
+        self.oracle_feeds: List[GenericOracleFeedDict] = []
         self.prices: List[PriceData] = []
         self.last_refresh_time: Optional[TimestampStr] = None
         self.refresh_in_progress: bool = False
+        self.refresh_calls: Optional[List[Tuple[str, bool, bytes]]] = None
+        self.chainlink_factory: Any = None
+        self.availability: AvailabilityTracker = AvailabilityTracker()
+        self.update_frequency: UpdateFrequencyTracker = UpdateFrequencyTracker()
+        self.feed_health: FeedHealthTracker = FeedHealthTracker()
+        self.faults: FaultInjector = FaultInjector.from_env()
+        self.slow_feeds: Set[str] = set()
+        self.silences: SilenceManager = SilenceManager(os.environ.get('SILENCES_FILE', '/app/silences.json'))
+        # CHAIN picks the registry entry (mainnet or Fuji); RPC_URL overrides its endpoint
+        self.network: ChainConfigDict = resolve_chain()
+        self.rpc_url: str = os.environ.get('RPC_URL', self.network['rpcUrl'])
+        self.chain: str = self.network['name']
+        self.chain_id: ChainId = ChainId(self.network['chainId'])
+        self.multicall_address: str = self.network['multicall3']
+        # GAS_LIMIT lifts the eth_call gas cap on providers whose default is too low for large batches
+        gas_limit = _gas_limit_from_env()
+        self.call_params: Dict[str, Any] = {'gas': gas_limit} if gas_limit else {}
+        # Simulated state would leak into served prices, alerts and history
+        if os.environ.get('STATE_OVERRIDE'):
+            print("Warning: STATE_OVERRIDE is ignored: state overrides are CLI-only")
         
-        # Load ABIs with proper typing
-        self.chainlink_abi: List[Dict[str, Any]] = self._load_chainlink_abi()
-        self.multicall_abi: List[Dict[str, Any]] = self._load_multicall_abi()
+        # ABIs generated from abi/*.json (npm run generate-bindings)
+        self.chainlink_abi: List[Dict[str, Any]] = AGGREGATOR_V3_INTERFACE_ABI
+        self.multicall_abi: List[Dict[str, Any]] = MULTICALL3_ABI
     
     async def initialize(self) -> None:
         """Initialize the service with blockchain connection and feed data"""
         # Initialize Web3 connection with type safety
-        self.w3 = Web3(Web3.HTTPProvider(self.RPC_URL))
+        self.w3 = Web3(Web3.HTTPProvider(self.rpc_url))
         if not self.w3.is_connected():
             raise ConnectionError("Failed to connect to Avalanche C-Chain")
         
@@ -61,11 +154,14 @@ class PriceService:
         self.multicall_contract = cast(
             MulticallContractProtocol,
             self.w3.eth.contract(
-                address=self.w3.to_checksum_address(self.MULTICALL_ADDRESS),
+                address=self.w3.to_checksum_address(self.multicall_address),
                 abi=self.multicall_abi
             )
         )
         
+        # Build the AggregatorV3Interface contract class once; instances only bind an address
+        self.chainlink_factory = self.w3.eth.contract(abi=self.chainlink_abi)
+        
         # Load feed metadata
         await self._load_feeds()
         
@@ -74,51 +170,20 @@ class PriceService:
             print(f"🔗 Connected to Avalanche C-Chain (Block: {block_number})")
             print(f"📊 Loaded {len(self.feeds)} feeds")
     
-    def _load_chainlink_abi(self) -> List[Dict[str, Any]]:
-        """Load Chainlink AggregatorV3Interface ABI with type safety"""
-        abi_path: str = os.path.join('/app', 'chainlink_abi_interface.json')
-        try:
-            with open(abi_path, 'r') as f:
-                abi_data: Any = json.load(f)
-                if not isinstance(abi_data, list):
-                    raise ValueError(f"Invalid ABI format: expected list, got {type(abi_data)}")
-                return cast(List[Dict[str, Any]], abi_data)
-        except FileNotFoundError as e:
-            raise FileNotFoundError(f"Chainlink ABI file not found: {abi_path}") from e
-        except json.JSONDecodeError as e:
-            raise ValueError(f"Invalid JSON in ABI file: {abi_path}") from e
-    
-    def _load_multicall_abi(self) -> List[Dict[str, Any]]:
-        """Load Multicall3 ABI (simplified for our needs) with proper typing"""
-        multicall_abi: List[Dict[str, Any]] = [
-            {
-                "inputs": [
-                    {
-                        "components": [
-                            {"name": "target", "type": "address"},
-                            {"name": "callData", "type": "bytes"}
-                        ],
-                        "name": "calls",
-                        "type": "tuple[]"
-                    }
-                ],
-                "name": "aggregate",
-                "outputs": [
-                    {"name": "blockNumber", "type": "uint256"},
-                    {"name": "returnData", "type": "bytes[]"}
-                ],
-                "stateMutability": "view",
-                "type": "function"
-            }
-        ]
-        return multicall_abi
+    def _chainlink_contract(self, address: str) -> Any:
+        """Bind the shared AggregatorV3Interface contract class to a feed address"""
+        return self.chainlink_factory(address=self.w3.to_checksum_address(address))
     
     async def _load_feeds(self) -> None:
         """Load feed metadata from CSV file with comprehensive validation"""
-        csv_path: str = os.path.join('/app', 'avalanche_chainlink_feeds.csv')
+        csv_path: str = os.environ.get('FEEDS_FILE', os.path.join('/app', self.network['feedsFile']))
+        
+        self.exchange_rate_feeds = self._load_exchange_rate_feeds()
+        self.oracle_feeds = self._load_oracle_feeds()
         
         try:
             self.feeds = []
+            self.refresh_calls = None
             with open(csv_path, 'r') as file:
                 reader = csv.DictReader(file)
                 for row_index, raw_row in enumerate(reader):
@@ -126,10 +191,7 @@ class PriceService:
                         # Validate row data and convert types
                         row: FeedMetadataDict = self._validate_csv_row(raw_row, row_index)
                         
-                        # Create symbol from name (remove spaces and special chars)
-                        symbol: SymbolStr = validate_symbol(
-                            row['name'].replace(' / ', '').replace(' ', '').upper()
-                        )
+                        symbol: SymbolStr = extract_symbol(row['name'])
                         
                         # Validate addresses
                         if not is_valid_address(row['contract_address']):
@@ -141,6 +203,7 @@ class PriceService:
                         feed = FeedMetadata(
                             name=row['name'],
                             symbol=symbol,
+                            kind=classify_feed(row['name'], row.get('kind')),
                             contractAddress=Address(row['contract_address']),
                             proxyAddress=Address(row['proxy_address']), 
                             decimals=Decimals(row['decimals']),
@@ -158,7 +221,83 @@ class PriceService:
                         continue
                         
         except FileNotFoundError as e:
-            raise FileNotFoundError(f"Feed data file not found: {csv_path}") from e
+            # Only the mainnet feed list ships with the repo; testnet lists are generated
+            chain = self.network['name']
+            raise FileNotFoundError(
+                f"No feed list for {chain} at {csv_path}; generate it with "
+                f"scripts/generate-feeds.js --chain {chain} or set FEEDS_FILE"
+            ) from e
+    
+    def _load_custom_feed_entries(self) -> List[Dict[str, Any]]:
+        """Typed entries from custom_feeds.json; raw-signature entries are CLI-only"""
+        config_path: str = os.environ.get('CUSTOM_FEEDS', os.path.join('/app', 'custom_feeds.json'))
+        if not os.path.exists(config_path):
+            return []
+        
+        with open(config_path, 'r') as f:
+            config: Dict[str, Any] = json.load(f)
+        return cast(List[Dict[str, Any]], config.get('feeds', []))
+    
+    def _load_exchange_rate_feeds(self) -> List[ExchangeRateFeedDict]:
+        """Load ERC-4626 and LST entries"""
+        feeds: List[ExchangeRateFeedDict] = []
+        for entry in self._load_custom_feed_entries():
+            feed_type = entry.get('type')
+            if feed_type not in ('erc4626', 'lst'):
+                continue
+            
+            method = entry.get('method') or DEFAULT_RATE_METHOD.get(feed_type)
+            if method not in RATE_METHODS or not is_valid_address(entry.get('target', '')):
+                print(f"Warning: Skipping invalid {feed_type} feed: {entry.get('name')}")
+                continue
+            
+            feeds.append(ExchangeRateFeedDict(
+                name=entry['name'],
+                symbol=extract_symbol(entry['name']),
+                type=feed_type,
+                address=entry['target'],
+                method=method,
+                decimals=int(entry.get('decode', {}).get('decimals', 18))
+            ))
+        return feeds
+    
+    def _load_oracle_feeds(self) -> List[GenericOracleFeedDict]:
+        """Load ERC-2362 entries. Ids are bytes32, or the "<base>-<quote>-<decimals>" string
+        the standard hashes, in which case decimals come from the id unless decode.decimals is set"""
+        feeds: List[GenericOracleFeedDict] = []
+        for entry in self._load_custom_feed_entries():
+            if entry.get('type') != 'erc2362':
+                continue
+            
+            oracle_id = entry.get('id')
+            decimals = entry.get('decode', {}).get('decimals')
+            pair = ORACLE_PAIR_ID.match(oracle_id) if isinstance(oracle_id, str) else None
+            if pair:
+                id_bytes = bytes(Web3.keccak(text=oracle_id))
+                decimals = decimals if decimals is not None else int(pair.group(1))
+            elif isinstance(oracle_id, str) and BYTES32_ID.match(oracle_id) and decimals is not None:
+                id_bytes = bytes.fromhex(oracle_id[2:])
+            else:
+                id_bytes = b''
+            
+            if not id_bytes or not is_valid_address(entry.get('target', '')):
+                print(f"Warning: Skipping invalid erc2362 feed: {entry.get('name')}")
+                continue
+            
+            feeds.append(GenericOracleFeedDict(
+                name=entry['name'],
+                symbol=extract_symbol(entry['name']),
+                address=entry['target'],
+                id=id_bytes,
+                decimals=int(decimals)
+            ))
+        return feeds
+    
+    def _rate_call_data(self, method: str) -> bytes:
+        """Encode a one-share exchange-rate read"""
+        signature, takes_shares = RATE_METHODS[method]
+        selector = bytes(Web3.keccak(text=signature)[:4])
+        return selector + self.w3.codec.encode(['uint256'], [ONE_SHARE]) if takes_shares else selector
     
     def _validate_csv_row(self, raw_row: Dict[str, str], row_index: int) -> FeedMetadataDict:
         """Validate and convert CSV row data with proper typing"""
@@ -218,7 +357,9 @@ class PriceService:
         """Get current network information"""
         block_number = self.w3.eth.block_number
         return {
-            "chainId": self.CHAIN_ID,
+            "chainId": self.chain_id,
+            "chain": self.chain,
+            "testnet": self.network['testnet'],
             "blockNumber": str(block_number),
             "connected": self.w3.is_connected()
         }
@@ -232,41 +373,52 @@ class PriceService:
         start_time = time.time()
         
         try:
-            # Prepare multicall data for latestRoundData
-            calls = []
-            for feed in self.feeds:
-                contract = self.w3.eth.contract(
-                    address=self.w3.to_checksum_address(feed.proxyAddress),
-                    abi=self.chainlink_abi
-                )
-                # Use function selector for latestRoundData() - no parameters
-                call_data = self.w3.keccak(text='latestRoundData()')[:4]
-                calls.append((self.w3.to_checksum_address(feed.proxyAddress), call_data))
+            # The batch only depends on the feed list, so it is built once and reused
+            if self.refresh_calls is None:
+                self.refresh_calls = self._build_refresh_calls()
             
-            # Execute multicall
-            block_number, return_data = self.multicall_contract.functions.aggregate(calls).call()
+            if self.faults.enabled:
+                print(f"🧪 Fault injection active (refresh {self.faults.next_step()})")
+            
+            # Execute multicall; each feed call succeeds or reverts on its own. The block
+            # timestamp is never mangled so answer age stays measurable
+            block_number, results = self._call_rpc(self.refresh_calls)
+            results = self.faults.mangle_results(results, [len(results) - 1])
+            (block_timestamp,) = self.w3.codec.decode(['uint256'], results[-1][1])
             
             # Process results
             new_prices = []
             errors = []
             
-            for i, (feed, data) in enumerate(zip(self.feeds, return_data)):
+            for i, (feed, (success, data)) in enumerate(zip(self.feeds, results[:len(self.feeds)])):
                 try:
+                    if not success:
+                        raise ValueError(self._revert_message(data))
+                    
                     # Decode the returned data using web3 codec for latestRoundData return types
                     output_types = ['uint80', 'int256', 'uint256', 'uint256', 'uint80']
                     decoded = self.w3.codec.decode(output_types, data)
                     round_id, answer, started_at, updated_at, answered_in_round = decoded
                     
+                    invalid = validate_answer(feed.kind, answer)
+                    if invalid:
+                        raise ValueError(invalid)
+                    
                     # Convert to human-readable price
                     price = float(answer) / (10 ** feed.decimals)
                     
                     # Create price data
                     price_data = PriceData(
                         symbol=feed.symbol,
+                        source='chainlink',
+                        kind=feed.kind,
                         price=price,
+                        exactPrice=format_answer(answer, feed.decimals),
                         decimals=feed.decimals,
                         roundId=str(round_id),
                         updatedAt=datetime.fromtimestamp(updated_at, tz=timezone.utc).isoformat(),
+                        blockTimestamp=datetime.fromtimestamp(block_timestamp, tz=timezone.utc).isoformat(),
+                        ageAtBlock=max(0, block_timestamp - updated_at),
                         proxyAddress=feed.proxyAddress,
                         raw=RawPriceData(
                             answer=str(answer),
@@ -276,16 +428,102 @@ class PriceService:
                         )
                     )
                     new_prices.append(price_data)
+                    self.availability.record_feed(feed.symbol, True)
+                    interval = self.update_frequency.record_observation(
+                        feed.symbol, feed.heartbeat, feed.deviationThreshold, updated_at, round_id, block_timestamp
+                    )
+                    if interval is not None:
+                        observe_update_interval(self.chain, feed.symbol, interval)
+                    self.feed_health.observe(feed.symbol, feed.heartbeat, feed.deviationThreshold, price, updated_at)
                     
                 except Exception as e:
+                    self.availability.record_feed(feed.symbol, False)
                     errors.append({
                         "symbol": feed.symbol,
                         "error": str(e)
                     })
             
+            # Exchange-rate reads follow the Chainlink feeds; they are live, so stamp them with the block
+            block_time_iso = datetime.fromtimestamp(block_timestamp, tz=timezone.utc).isoformat()
+            rate_results = results[len(self.feeds):len(self.feeds) + len(self.exchange_rate_feeds)]
+            for rate_feed, (success, data) in zip(self.exchange_rate_feeds, rate_results):
+                try:
+                    if not success:
+                        raise ValueError(self._revert_message(data))
+                    (assets,) = self.w3.codec.decode(['uint256'], data)
+                    rate = float(assets) / (10 ** rate_feed['decimals'])
+                    new_prices.append(PriceData(
+                        symbol=rate_feed['symbol'],
+                        source=rate_feed['type'],
+                        kind='rate',
+                        price=rate,
+                        exactPrice=format_answer(assets, rate_feed['decimals']),
+                        decimals=rate_feed['decimals'],
+                        roundId='0',
+                        updatedAt=block_time_iso,
+                        blockTimestamp=block_time_iso,
+                        ageAtBlock=0,
+                        proxyAddress=rate_feed['address'],
+                        raw=RawPriceData(
+                            answer=str(assets),
+                            startedAt=str(block_timestamp),
+                            updatedAt=str(block_timestamp),
+                            answeredInRound='0'
+                        )
+                    ))
+                    self.availability.record_feed(rate_feed['symbol'], True)
+                    self.feed_health.observe(rate_feed['symbol'], None, 0, rate, block_timestamp)
+                except Exception as e:
+                    self.availability.record_feed(rate_feed['symbol'], False)
+                    errors.append({
+                        "symbol": rate_feed['symbol'],
+                        "error": str(e)
+                    })
+            
+            # Oracle reads follow the exchange rates; each value carries its own timestamp
+            oracle_start = len(self.feeds) + len(self.exchange_rate_feeds)
+            oracle_results = results[oracle_start:oracle_start + len(self.oracle_feeds)]
+            for oracle_feed, (success, data) in zip(self.oracle_feeds, oracle_results):
+                try:
+                    if not success:
+                        raise ValueError(self._revert_message(data))
+                    value, oracle_updated_at, status = self.w3.codec.decode(['int256', 'uint256', 'uint256'], data)
+                    if status != ERC2362_STATUS_OK:
+                        raise ValueError(f"Oracle returned status {status}")
+                    oracle_price = float(value) / (10 ** oracle_feed['decimals'])
+                    new_prices.append(PriceData(
+                        symbol=oracle_feed['symbol'],
+                        source='erc2362',
+                        kind='price',
+                        price=oracle_price,
+                        exactPrice=format_answer(value, oracle_feed['decimals']),
+                        decimals=oracle_feed['decimals'],
+                        roundId='0',
+                        updatedAt=datetime.fromtimestamp(oracle_updated_at, tz=timezone.utc).isoformat(),
+                        blockTimestamp=block_time_iso,
+                        ageAtBlock=max(0, block_timestamp - oracle_updated_at),
+                        proxyAddress=oracle_feed['address'],
+                        raw=RawPriceData(
+                            answer=str(value),
+                            startedAt=str(oracle_updated_at),
+                            updatedAt=str(oracle_updated_at),
+                            answeredInRound='0'
+                        )
+                    ))
+                    self.availability.record_feed(oracle_feed['symbol'], True)
+                    self.feed_health.observe(oracle_feed['symbol'], None, 0, oracle_price, oracle_updated_at)
+                except Exception as e:
+                    self.availability.record_feed(oracle_feed['symbol'], False)
+                    errors.append({
+                        "symbol": oracle_feed['symbol'],
+                        "error": str(e)
+                    })
+            
             # Update prices and refresh time
             self.prices = new_prices
             self.last_refresh_time = datetime.now(tz=timezone.utc).isoformat()
+            self._report_slow_feeds(block_timestamp)
+            self._grade_feeds(block_timestamp)
             
             duration = (time.time() - start_time) * 1000  # Convert to milliseconds
             
@@ -298,6 +536,117 @@ class PriceService:
             
         finally:
             self.refresh_in_progress = False
+            self._persist_trackers()
+    
+    def _persist_trackers(self) -> None:
+        def save() -> None:
+            self.availability.save()
+            self.update_frequency.save()
+            self.feed_health.save()
+        
+        try:
+            self.faults.write(save)
+        except OSError as e:
+            print(f"Warning: Skipping tracker persistence: {e}")
+    
+    def _grade_feeds(self, now: int) -> None:
+        """Attach this cycle's health score and grade to each price and export it"""
+        self.feed_health.refresh(self.update_frequency.get_report(now), self.availability.get_report(), now)
+        for price_data in self.prices:
+            health = self.feed_health.get(price_data.symbol)
+            if health is None:
+                continue
+            price_data.health = PriceHealth(score=health["score"], grade=health["grade"])
+            set_feed_health_score(self.chain, price_data.symbol, health["score"])
+    
+    def _report_slow_feeds(self, now: int) -> None:
+        """Warn once when a feed starts updating less often than its heartbeat promises, unless silenced"""
+        alerts = self._update_frequency_alerts(now)
+        for alert in alerts:
+            if not alert["silenced"] and alert["feed"] not in self.slow_feeds:
+                print(f"Warning: {alert['message']}")
+        self.slow_feeds = {alert["feed"] for alert in alerts}
+    
+    def _update_frequency_alerts(self, now: float) -> List[Dict[str, Any]]:
+        """A slow feed past heartbeat × tolerance with no update is stale; otherwise its cadence is just too low"""
+        report = self.update_frequency.get_report(now)
+        return [
+            self.silences.apply({
+                "type": 'stale' if record["secondsSinceUpdate"] > record["heartbeat"] * report["tolerance"] else 'slow-updates',
+                "feed": record["symbol"],
+                "message": f"{record['symbol']} is updating slower than its heartbeat: {'; '.join(record['reasons'])}"
+            })
+            for record in report["feeds"] if record["status"] == 'slow'
+        ]
+    
+    def get_alerts(self) -> List[Dict[str, Any]]:
+        """RPC endpoints whose last call failed, then stale or slow feeds"""
+        rpc_alerts = [
+            self.silences.apply({
+                "type": 'rpc-failure',
+                "feed": None,
+                "message": f"Last call to {record['key']} failed at {record['lastFailure']}"
+            })
+            for record in self.availability.get_report()["rpcs"]
+            if record["lastFailure"] is not None
+            and (record["lastSuccess"] is None or record["lastFailure"] > record["lastSuccess"])
+        ]
+        return rpc_alerts + self._update_frequency_alerts(time.time())
+    
+    def _call_rpc(self, calls: List[Tuple[str, bool, bytes]]) -> Tuple[int, List[Tuple[bool, bytes]]]:
+        """Run the aggregate3 batch and split off its leading getBlockNumber read; returns (block, [(success, data)]).
+        Transport failures count against the RPC. A reverted batch was answered but read nothing, so it counts
+        against every feed in it rather than for the RPC; feeds that revert on their own are recorded while decoding"""
+        try:
+            results = self.faults.call(lambda: self.multicall_contract.functions.aggregate3(calls).call(self.call_params))
+        except ContractLogicError:
+            for symbol in self._batch_symbols():
+                self.availability.record_feed(symbol, False)
+            raise
+        except Exception:
+            self.availability.record_rpc(self.rpc_url, False)
+            raise
+        self.availability.record_rpc(self.rpc_url, True)
+        (block_number,) = self.w3.codec.decode(['uint256'], results[0][1])
+        return block_number, [(success, data) for success, data in results[1:]]
+    
+    def _batch_symbols(self) -> List[str]:
+        return ([feed.symbol for feed in self.feeds]
+                + [feed['symbol'] for feed in self.exchange_rate_feeds]
+                + [feed['symbol'] for feed in self.oracle_feeds])
+    
+    def _revert_message(self, data: bytes) -> str:
+        """A call that reverted inside aggregate3; Error(string) reverts keep their reason"""
+        if data[:4] == ERROR_STRING_SELECTOR:
+            try:
+                (reason,) = self.w3.codec.decode(['string'], data[4:])
+                return f"Call reverted: {reason}"
+            except Exception:
+                pass
+        return "Call reverted"
+    
+    def _build_refresh_calls(self) -> List[Tuple[str, bool, bytes]]:
+        """Build the aggregate3 batch: the block number, latestRoundData() for all feeds, exchange-rate and
+        oracle reads, then the block timestamp. Feed reads allow failure so one revert can't sink the batch"""
+        multicall = self.w3.to_checksum_address(self.multicall_address)
+        # aggregate3 reports no block number, so read it as the first call
+        calls: List[Tuple[str, bool, bytes]] = [(multicall, False, GET_BLOCK_NUMBER_SELECTOR)]
+        calls.extend(
+            (self.w3.to_checksum_address(feed.proxyAddress), True, LATEST_ROUND_DATA_SELECTOR)
+            for feed in self.feeds
+        )
+        calls.extend(
+            (self.w3.to_checksum_address(feed['address']), True, self._rate_call_data(feed['method']))
+            for feed in self.exchange_rate_feeds
+        )
+        calls.extend(
+            (self.w3.to_checksum_address(feed['address']), True, VALUE_FOR_SELECTOR + feed['id'])
+            for feed in self.oracle_feeds
+        )
+        
+        # Read the block timestamp in the same call so answer age is measured at the sampled block
+        calls.append((multicall, False, GET_CURRENT_BLOCK_TIMESTAMP_SELECTOR))
+        return calls
     
     async def get_round_data(self, symbol: str, round_id: str) -> Optional[Dict[str, Any]]:
         """Get historical round data for specific feed"""
@@ -306,10 +655,7 @@ class PriceService:
             return None
         
         try:
-            contract = self.w3.eth.contract(
-                address=self.w3.to_checksum_address(feed.proxyAddress),
-                abi=self.chainlink_abi
-            )
+            contract = self._chainlink_contract(feed.proxyAddress)
             
             round_data = contract.functions.getRoundData(int(round_id)).call()
             round_id_ret, answer, started_at, updated_at, answered_in_round = round_data
@@ -330,7 +676,76 @@ class PriceService:
             
         except Exception:
             return None
-    
+
+    async def get_round_history(self, symbol: str, count: int) -> Optional[List[Dict[str, Any]]]:
+        """Walk back from the latest round, fetching up to count rounds in one multicall"""
+        feed = self.get_feed(symbol)
+        if not feed:
+            return None
+
+        proxy_address = self.w3.to_checksum_address(feed.proxyAddress)
+        contract = self._chainlink_contract(proxy_address)
+        latest_round_id: int = contract.functions.latestRoundData().call()[0]
+
+        # Proxy round IDs carry the phase in the upper bits; stay within the current phase
+        aggregator_round = latest_round_id & ((1 << 64) - 1)
+        available = min(count, aggregator_round)
+        round_ids = [latest_round_id - (available - 1 - i) for i in range(available)]
+
+        calls = [
+            (proxy_address, True, GET_ROUND_DATA_SELECTOR + self.w3.codec.encode(['uint80'], [round_id]))
+            for round_id in round_ids
+        ]
+
+        results = self.multicall_contract.functions.aggregate3(calls).call(self.call_params)
+
+        output_types = ['uint80', 'int256', 'uint256', 'uint256', 'uint80']
+        history = []
+        for round_id, answer, started_at, updated_at, answered_in_round in completed_rounds(
+            results, lambda data: cast(RoundFields, self.w3.codec.decode(output_types, data))
+        ):
+            history.append({
+                "roundId": str(round_id),
+                "answer": str(answer),
+                "startedAt": str(started_at),
+                "updatedAt": str(updated_at),
+                "answeredInRound": str(answered_in_round),
+                "price": float(answer) / (10 ** feed.decimals),
+                "decimals": feed.decimals,
+                "symbol": feed.symbol,
+                "timestamp": datetime.fromtimestamp(updated_at, tz=timezone.utc).isoformat()
+            })
+
+        return history
+
+    async def get_volatility(self, symbol: str, window: int) -> Optional[Dict[str, Any]]:
+        """Get realized volatility over the trailing window rounds"""
+        feed = self.get_feed(symbol)
+        if feed and not FEED_KIND_RULES[feed.kind]['volatility']:
+            raise UnsupportedFeedKindError(feed.kind, 'volatility')
+        
+        rounds = await self.get_round_history(symbol, window)
+        if rounds is None:
+            return None
+
+        result = compute_realized_volatility(
+            [(entry["price"], int(entry["updatedAt"])) for entry in rounds]
+        )
+        if result is None:
+            raise InsufficientHistoryError(symbol, window)
+        set_realized_volatility(self.chain, rounds[0]["symbol"], window, result["realizedVolatility"])
+
+        return {
+            "symbol": rounds[0]["symbol"],
+            "window": window,
+            "observations": result["observations"],
+            "fromTimestamp": rounds[0]["timestamp"],
+            "toTimestamp": rounds[-1]["timestamp"],
+            "meanIntervalSeconds": result["meanIntervalSeconds"],
+            "stdDev": result["stdDev"],
+            "realizedVolatility": result["realizedVolatility"]
+        }
+
     async def get_feed_description(self, symbol: str) -> Optional[Dict[str, Any]]:
         """Get feed description from smart contract"""
         feed = self.get_feed(symbol)
@@ -338,10 +753,7 @@ class PriceService:
             return None
         
         try:
-            contract = self.w3.eth.contract(
-                address=self.w3.to_checksum_address(feed.proxyAddress),
-                abi=self.chainlink_abi
-            )
+            contract = self._chainlink_contract(feed.proxyAddress)
             
             description = contract.functions.description().call()
             
@@ -361,10 +773,7 @@ class PriceService:
             return None
         
         try:
-            contract = self.w3.eth.contract(
-                address=self.w3.to_checksum_address(feed.proxyAddress),
-                abi=self.chainlink_abi
-            )
+            contract = self._chainlink_contract(feed.proxyAddress)
             
             version = contract.functions.version().call()
             
@@ -384,10 +793,7 @@ class PriceService:
             return None
         
         try:
-            contract = self.w3.eth.contract(
-                address=self.w3.to_checksum_address(feed.proxyAddress),
-                abi=self.chainlink_abi
-            )
+            contract = self._chainlink_contract(feed.proxyAddress)
             
             decimals = contract.functions.decimals().call()
             
@@ -413,17 +819,11 @@ class PriceService:
         # Prepare multicall for all PoR feeds
         calls = []
         for feed in por_feeds:
-            contract = self.w3.eth.contract(
-                address=self.w3.to_checksum_address(feed.proxyAddress),
-                abi=self.chainlink_abi
-            )
-            # Use function selector for latestRoundData() - no parameters
-            call_data = self.w3.keccak(text='latestRoundData()')[:4]
-            calls.append((self.w3.to_checksum_address(feed.proxyAddress), call_data))
+            calls.append((self.w3.to_checksum_address(feed.proxyAddress), LATEST_ROUND_DATA_SELECTOR))
         
         try:
             # Execute multicall
-            block_number, return_data = self.multicall_contract.functions.aggregate(calls).call()
+            block_number, return_data = self.multicall_contract.functions.aggregate(calls).call(self.call_params)
             
             por_data = []
             for feed, data in zip(por_feeds, return_data):
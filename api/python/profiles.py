@@ -0,0 +1,123 @@
+"""
+Deployment profiles
+Named profiles in profiles.json bundle the chain, RPC, feed files and alerting
+settings of one deployment. Applying a profile fills the environment variables
+the service reads, leaving any that are already set alone. Relative paths are
+taken relative to profiles.json.
+
+PROFILE=prod-avalanche python main.py   (or: python main.py --profile prod-avalanche)
+"""
+
+import os
+import sys
+import json
+from typing import Any, Dict, Final, List, MutableMapping, Optional
+
+DEFAULT_PROFILES_FILE: Final[str] = '/app/profiles.json'
+
+PROFILE_SETTINGS: Final[Dict[str, str]] = {
+    'chain': 'CHAIN',
+    'rpcUrl': 'RPC_URL',
+    'feedsFile': 'FEEDS_FILE',
+    'customFeedsFile': 'CUSTOM_FEEDS',
+    'metadataFile': 'FEED_METADATA',
+}
+
+SINK_SETTINGS: Final[Dict[str, Dict[str, str]]] = {
+    'file': {'dir': 'SNAPSHOT_DIR'},
+    'binary': {'file': 'SNAPSHOT_LOG'},
+}
+
+ALERTING_SETTINGS: Final[Dict[str, str]] = {
+    'silencesFile': 'SILENCES_FILE',
+    'updateTolerance': 'UPDATE_TOLERANCE',
+}
+
+PATH_VARIABLES: Final[List[str]] = [
+    'FEEDS_FILE', 'CUSTOM_FEEDS', 'FEED_METADATA', 'SNAPSHOT_DIR', 'SNAPSHOT_LOG', 'SILENCES_FILE'
+]
+
+
+def _setting(value: Any) -> str:
+    # Matches the JSON spelling the Node services produce with String(value)
+    if isinstance(value, bool):
+        return 'true' if value else 'false'
+    return str(value)
+
+
+def _map_settings(name: str, section: str, settings: Dict[str, Any],
+                  mapping: Dict[str, str], env_vars: Dict[str, str]) -> None:
+    for key, value in settings.items():
+        if key not in mapping:
+            raise ValueError(f'Profile {name}: unknown {section} setting "{key}"')
+        env_vars[mapping[key]] = _setting(value)
+
+
+def profile_env(name: str, profile: Dict[str, Any]) -> Dict[str, str]:
+    """Sinks marked verify: true are listed in VERIFY_WRITES; "env" passes other variables through"""
+    env_vars: Dict[str, str] = {}
+    verified: List[str] = []
+
+    for key, value in profile.items():
+        if key in PROFILE_SETTINGS:
+            env_vars[PROFILE_SETTINGS[key]] = _setting(value)
+        elif key == 'sinks':
+            for sink, settings in value.items():
+                if sink not in SINK_SETTINGS:
+                    expected = ', '.join(SINK_SETTINGS)
+                    raise ValueError(f'Profile {name}: unknown sink "{sink}", expected one of {expected}')
+                rest = {k: v for k, v in settings.items() if k != 'verify'}
+                _map_settings(name, f'{sink} sink', rest, SINK_SETTINGS[sink], env_vars)
+                if settings.get('verify'):
+                    verified.append(sink)
+        elif key == 'alerting':
+            _map_settings(name, 'alerting', value, ALERTING_SETTINGS, env_vars)
+        elif key == 'env':
+            env_vars.update({variable: _setting(setting) for variable, setting in value.items()})
+        else:
+            raise ValueError(f'Profile {name}: unknown setting "{key}"')
+
+    if verified:
+        env_vars['VERIFY_WRITES'] = ','.join(verified)
+    return env_vars
+
+
+def load_profile(name: str, file: Optional[str] = None) -> Dict[str, str]:
+    path = file or os.environ.get('PROFILES_FILE', DEFAULT_PROFILES_FILE)
+    with open(path, 'r') as f:
+        profiles: Dict[str, Any] = json.load(f).get('profiles', {})
+    if name not in profiles:
+        available = ', '.join(profiles) or 'none'
+        raise ValueError(f'Unknown profile "{name}" in {path} (available: {available})')
+
+    env_vars = profile_env(name, profiles[name])
+    base = os.path.dirname(os.path.abspath(path))
+    for variable in PATH_VARIABLES:
+        if variable in env_vars:
+            env_vars[variable] = os.path.normpath(os.path.join(base, env_vars[variable]))
+    return env_vars
+
+
+def selected_profile(argv: Optional[List[str]] = None,
+                     env: Optional[MutableMapping[str, str]] = None) -> Optional[str]:
+    """--profile on the command line wins over PROFILE"""
+    args = sys.argv[1:] if argv is None else argv
+    environ = os.environ if env is None else env
+    if '--profile' in args:
+        index = args.index('--profile')
+        return args[index + 1] if index + 1 < len(args) else None
+    inline = next((arg for arg in args if arg.startswith('--profile=')), None)
+    return inline[len('--profile='):] if inline else environ.get('PROFILE')
+
+
+def apply_profile(name: Optional[str] = None,
+                  env: Optional[MutableMapping[str, str]] = None) -> Optional[str]:
+    """Apply the selected profile without overriding variables already set; returns its name"""
+    environ = os.environ if env is None else env
+    profile = name or selected_profile(env=environ)
+    if not profile:
+        return None
+
+    for variable, value in load_profile(profile, environ.get('PROFILES_FILE')).items():
+        environ.setdefault(variable, value)
+    return profile
@@ -0,0 +1,192 @@
+"""
+Alert silencing
+Silences (per feed, per alert type, time-bounded) and maintenance windows,
+read from a config file and editable through the API. API changes are
+written back to the same file so they survive restarts.
+"""
+
+import os
+import json
+import re
+import time
+import uuid
+from datetime import datetime, timezone
+from typing import Callable, Dict, List, Optional, Any, Final, Tuple
+
+ALERT_TYPES: Final[Tuple[str, ...]] = ('stale', 'slow-updates', 'rpc-failure')
+
+_OFFSET = re.compile(r'(Z|[+-]\d{2}:\d{2})$', re.IGNORECASE)
+
+
+class SilenceValidationError(ValueError):
+    """Rejected silence input; maps to a 400 VALIDATION_ERROR"""
+    
+    def __init__(self, field: str, message: str) -> None:
+        super().__init__(f"Validation error for {field}: {message}")
+        self.field = field
+
+
+def _iso(ms: float) -> str:
+    return datetime.fromtimestamp(ms / 1000, tz=timezone.utc).isoformat(timespec='milliseconds').replace('+00:00', 'Z')
+
+
+def _parse_time(field: str, value: Any) -> float:
+    """Offset-less date-times would be read as server-local time"""
+    if not isinstance(value, str) or not _OFFSET.search(value):
+        raise SilenceValidationError(field, "must be an RFC 3339 date-time with an explicit offset")
+    try:
+        return datetime.fromisoformat(value.replace('Z', '+00:00').replace('z', '+00:00')).timestamp() * 1000
+    except ValueError:
+        raise SilenceValidationError(field, "must be an RFC 3339 date-time with an explicit offset")
+
+
+def _parse_range(data: Dict[str, Any], now: float) -> Dict[str, str]:
+    """Resolve startsAt/endsAt/durationMinutes into a validated time range"""
+    start = now if data.get('startsAt') is None else _parse_time('startsAt', data['startsAt'])
+    
+    duration = data.get('durationMinutes')
+    if data.get('endsAt') is not None:
+        end = _parse_time('endsAt', data['endsAt'])
+    elif isinstance(duration, (int, float)) and not isinstance(duration, bool) and duration > 0:
+        end = start + duration * 60_000
+    else:
+        raise SilenceValidationError('endsAt', "either endsAt or a positive durationMinutes is required")
+    
+    if end <= start:
+        raise SilenceValidationError('endsAt', "must be after startsAt")
+    return {"startsAt": _iso(start), "endsAt": _iso(end)}
+
+
+def _is_active(entry: Dict[str, Any], now: float) -> bool:
+    return _parse_time('startsAt', entry["startsAt"]) <= now < _parse_time('endsAt', entry["endsAt"])
+
+
+def _now_ms() -> float:
+    return time.time() * 1000
+
+
+class SilenceManager:
+    """Silences and maintenance windows backed by a JSON config file"""
+    
+    def __init__(self, config_path: Optional[str]) -> None:
+        self.config_path = config_path
+        self.silences: List[Dict[str, Any]] = []
+        self.maintenance_windows: List[Dict[str, Any]] = []
+        self._load()
+    
+    def list(self, now: Optional[float] = None) -> Dict[str, Any]:
+        now = now if now is not None else _now_ms()
+        return {
+            "silences": list(self.silences),
+            "maintenanceWindows": list(self.maintenance_windows),
+            "inMaintenance": any(_is_active(w, now) for w in self.maintenance_windows)
+        }
+    
+    def add_silence(self, data: Dict[str, Any], now: Optional[float] = None) -> Dict[str, Any]:
+        now = now if now is not None else _now_ms()
+        silence = self._build_silence(data, now)
+        self.silences.append(silence)
+        self._save(now)
+        return silence
+    
+    def add_maintenance_window(self, data: Dict[str, Any], now: Optional[float] = None) -> Dict[str, Any]:
+        now = now if now is not None else _now_ms()
+        window = self._build_maintenance_window(data, now)
+        self.maintenance_windows.append(window)
+        self._save(now)
+        return window
+    
+    def remove(self, entry_id: str, now: Optional[float] = None) -> bool:
+        """Remove a silence or maintenance window; False when the id is unknown"""
+        before = len(self.silences) + len(self.maintenance_windows)
+        self.silences = [s for s in self.silences if s["id"] != entry_id]
+        self.maintenance_windows = [w for w in self.maintenance_windows if w["id"] != entry_id]
+        if len(self.silences) + len(self.maintenance_windows) == before:
+            return False
+        self._save(now if now is not None else _now_ms())
+        return True
+    
+    def match(self, alert_type: str, feed: Optional[str], now: Optional[float] = None) -> Optional[str]:
+        """Id of whatever silences this alert right now, maintenance windows first"""
+        now = now if now is not None else _now_ms()
+        for window in self.maintenance_windows:
+            if _is_active(window, now):
+                return window["id"]
+        for silence in self.silences:
+            if (_is_active(silence, now)
+                    and silence["alertType"] in (None, alert_type)
+                    and (silence["feed"] is None or (feed is not None and silence["feed"].upper() == feed.upper()))):
+                return silence["id"]
+        return None
+    
+    def apply(self, alert: Dict[str, Any], now: Optional[float] = None) -> Dict[str, Any]:
+        silenced_by = self.match(alert["type"], alert["feed"], now)
+        return {**alert, "silenced": silenced_by is not None, "silencedBy": silenced_by}
+    
+    def _build_silence(self, data: Dict[str, Any], now: float, entry_id: Optional[str] = None) -> Dict[str, Any]:
+        alert_type = data.get('alertType')
+        if alert_type is not None and alert_type not in ALERT_TYPES:
+            raise SilenceValidationError('alertType', f"must be one of {', '.join(ALERT_TYPES)}")
+        return {
+            "id": entry_id or str(uuid.uuid4()),
+            "feed": data.get('feed') or None,
+            "alertType": alert_type,
+            **_parse_range(data, now),
+            "reason": data.get('reason') or ''
+        }
+    
+    def _build_maintenance_window(self, data: Dict[str, Any], now: float,
+                                  entry_id: Optional[str] = None) -> Dict[str, Any]:
+        return {"id": entry_id or str(uuid.uuid4()), **_parse_range(data, now), "reason": data.get('reason') or ''}
+    
+    def _save(self, now: float) -> None:
+        """Expired entries are dropped whenever the file is rewritten"""
+        self.silences = [s for s in self.silences if _parse_time('endsAt', s["endsAt"]) > now]
+        self.maintenance_windows = [w for w in self.maintenance_windows if _parse_time('endsAt', w["endsAt"]) > now]
+        if not self.config_path:
+            return
+        try:
+            with open(self.config_path, 'w') as f:
+                json.dump({"silences": self.silences, "maintenanceWindows": self.maintenance_windows}, f, indent=2)
+        except OSError as e:
+            print(f"Warning: Could not persist silences to {self.config_path}: {e}")
+    
+    def _load(self) -> None:
+        """A bad entry is skipped on its own and expired ones are dropped. The file is written back
+        with absolute startsAt/endsAt, so a durationMinutes window doesn't restart on every boot"""
+        if not self.config_path or not os.path.exists(self.config_path):
+            return
+        try:
+            with open(self.config_path, 'r') as f:
+                config = json.load(f)
+        except (OSError, ValueError) as e:
+            print(f"Warning: Ignoring unreadable silences file {self.config_path}: {e}")
+            return
+        
+        if not isinstance(config, dict):
+            config = {}
+        now = _now_ms()
+        self.silences = self._load_entries('silences', config.get('silences'), self._build_silence, now)
+        self.maintenance_windows = self._load_entries(
+            'maintenanceWindows', config.get('maintenanceWindows'), self._build_maintenance_window, now
+        )
+        self._save(now)
+    
+    def _load_entries(self, key: str, entries: Any,
+                      build: Callable[[Dict[str, Any], float, Optional[str]], Dict[str, Any]],
+                      now: float) -> List[Dict[str, Any]]:
+        if entries is None:
+            return []
+        if not isinstance(entries, list):
+            print(f"Warning: Ignoring {key} in {self.config_path}: expected a list")
+            return []
+        
+        loaded: List[Dict[str, Any]] = []
+        for index, entry in enumerate(entries):
+            try:
+                if entry.get('endsAt') is not None and _parse_time('endsAt', entry['endsAt']) <= now:
+                    continue
+                loaded.append(build(entry, now, entry.get('id')))
+            except (ValueError, AttributeError, TypeError) as e:
+                print(f"Warning: Skipping {key}[{index}] in {self.config_path}: {e}")
+        return loaded
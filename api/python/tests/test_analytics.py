@@ -0,0 +1,82 @@
+"""Realized volatility from round answers"""
+
+import math
+import struct
+import unittest
+from typing import List, Tuple
+
+from analytics import SECONDS_PER_YEAR, RoundFields, completed_rounds, compute_realized_volatility
+
+
+def series(prices: List[float], interval: int = 60) -> List[Tuple[float, int]]:
+    return [(price, 1753056000 + i * interval) for i, price in enumerate(prices)]
+
+
+class RealizedVolatilityTest(unittest.TestCase):
+    def test_annualizes_the_sample_standard_deviation_by_the_mean_round_interval(self) -> None:
+        result = compute_realized_volatility(series([100, 200, 100]))
+        assert result is not None
+
+        self.assertEqual(result["observations"], 2)
+        self.assertEqual(result["meanIntervalSeconds"], 60)
+        self.assertAlmostEqual(result["stdDev"], math.log(2) * math.sqrt(2), places=12)
+        self.assertAlmostEqual(
+            result["realizedVolatility"], math.log(2) * math.sqrt(2) * math.sqrt(SECONDS_PER_YEAR / 60), places=6
+        )
+
+    def test_orders_rounds_by_time_first(self) -> None:
+        points = series([100, 105, 98, 110])
+        self.assertEqual(compute_realized_volatility(points[::-1]), compute_realized_volatility(points))
+
+    def test_a_flat_series_has_no_volatility(self) -> None:
+        result = compute_realized_volatility(series([100, 100, 100]))
+        assert result is not None
+        self.assertEqual((result["stdDev"], result["realizedVolatility"]), (0, 0))
+
+    def test_skips_returns_touching_non_positive_answers_but_keeps_their_time(self) -> None:
+        result = compute_realized_volatility(series([100, 0, 100, 200, 100]))
+        assert result is not None
+        self.assertEqual(result["observations"], 2)
+        self.assertEqual(result["meanIntervalSeconds"], 60)
+
+    def test_needs_two_usable_returns_over_a_positive_time_span(self) -> None:
+        self.assertIsNone(compute_realized_volatility(series([100, 200])))
+        self.assertIsNone(compute_realized_volatility(series([100, 0, 100, 200])))
+        self.assertIsNone(compute_realized_volatility(series([100, 200, 100], 0)))
+        self.assertIsNone(compute_realized_volatility([]))
+
+
+def encode_round(round_id: int, answer: int, updated_at: int) -> bytes:
+    return struct.pack('>5Q', round_id, answer, updated_at, updated_at, round_id)
+
+
+def decode_round(data: bytes) -> RoundFields:
+    return struct.unpack('>5Q', data)
+
+
+class CompletedRoundsTest(unittest.TestCase):
+    def test_skips_rounds_missing_from_the_history(self) -> None:
+        results = [
+            (True, encode_round(1, 100, 1753056000)),
+            # Zeroed, reverted and truncated reads all leave a gap
+            (True, encode_round(0, 0, 0)),
+            (False, b''),
+            (True, encode_round(3, 105, 1753056060)[:16]),
+            (True, encode_round(4, 110, 1753056120)),
+            (True, encode_round(5, 120, 1753056180)),
+        ]
+
+        rounds = completed_rounds(results, decode_round)
+        self.assertEqual([fields[0] for fields in rounds], [1, 4, 5])
+
+        result = compute_realized_volatility([(float(fields[1]), fields[3]) for fields in rounds])
+        assert result is not None
+        self.assertEqual(result["observations"], 2)
+        self.assertEqual(result["meanIntervalSeconds"], 90)
+
+    def test_a_history_with_no_completed_rounds_is_empty(self) -> None:
+        self.assertEqual(completed_rounds([(False, b''), (True, encode_round(0, 0, 0))], decode_round), [])
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,76 @@
+"""Rolling availability per feed and per RPC origin"""
+
+import json
+import os
+import tempfile
+import unittest
+from datetime import datetime, timezone
+
+from availability import AvailabilityTracker, rpc_key
+
+AT = int(datetime(2025, 7, 21, tzinfo=timezone.utc).timestamp() * 1000)
+MINUTE = 60 * 1000
+
+
+def iso(ms: int) -> str:
+    return datetime.fromtimestamp(ms / 1000, tz=timezone.utc).isoformat()
+
+
+class AvailabilityTrackerTest(unittest.TestCase):
+    def test_keeps_only_the_newest_samples_in_the_window(self) -> None:
+        tracker = AvailabilityTracker(3, '')
+        for i, ok in enumerate([False, True, False, True]):
+            tracker.record_feed('BTC / USD', ok, AT + i * MINUTE)
+
+        record = tracker.get_report()["feeds"][0]
+        self.assertEqual(record["successes"], 2)
+        self.assertEqual(record["failures"], 1)
+        self.assertAlmostEqual(record["availability"], 2 / 3)
+        self.assertEqual(record["since"], iso(AT + MINUTE))
+        self.assertEqual(record["lastFailure"], iso(AT + 2 * MINUTE))
+
+    def test_ranks_the_least_available_first_and_shares_out_the_failures(self) -> None:
+        tracker = AvailabilityTracker(10, '')
+        tracker.record_feed('BTC / USD', True, AT)
+        tracker.record_feed('ETH / USD', False, AT)
+        tracker.record_feed('ETH / USD', True, AT + MINUTE)
+        tracker.record_feed('AVAX / USD', False, AT)
+
+        feeds = tracker.get_report()["feeds"]
+        self.assertEqual([record["key"] for record in feeds], ['AVAX / USD', 'ETH / USD', 'BTC / USD'])
+        self.assertEqual([record["failureShare"] for record in feeds], [0.5, 0.5, 0.0])
+
+    def test_reduces_rpc_urls_to_their_origin(self) -> None:
+        self.assertEqual(rpc_key('https://avax-mainnet.g.alchemy.com/v2/SECRET'), 'https://avax-mainnet.g.alchemy.com')
+        self.assertEqual(rpc_key('https://rpc.example.com:8545/ext/bc/C/rpc?apikey=SECRET'), 'https://rpc.example.com:8545')
+        self.assertEqual(rpc_key('https://rpc.example.com:443/rpc'), 'https://rpc.example.com')
+        self.assertEqual(rpc_key('not a url'), 'invalid-url')
+
+        tracker = AvailabilityTracker(10, '')
+        tracker.record_rpc('https://avax-mainnet.g.alchemy.com/v2/SECRET', True, AT)
+        tracker.record_rpc('https://avax-mainnet.g.alchemy.com/v2/OTHER?key=SECRET', False, AT + MINUTE)
+
+        report = tracker.get_report()
+        self.assertEqual([record["key"] for record in report["rpcs"]], ['https://avax-mainnet.g.alchemy.com'])
+        self.assertEqual(report["rpcs"][0]["failures"], 1)
+        self.assertNotIn('SECRET', json.dumps(report))
+
+    def test_saved_samples_survive_a_restart(self) -> None:
+        handle, path = tempfile.mkstemp(suffix='.json')
+        os.close(handle)
+        try:
+            tracker = AvailabilityTracker(10, path)
+            tracker.record_feed('BTC / USD', True, AT)
+            tracker.record_feed('BTC / USD', False, AT + MINUTE)
+            tracker.record_rpc('https://api.avax.network/ext/bc/C/rpc', True, AT)
+            tracker.save()
+
+            with open(path) as f:
+                self.assertNotIn('/ext/bc/C/rpc', f.read())
+            self.assertEqual(AvailabilityTracker(10, path).get_report(), tracker.get_report())
+        finally:
+            os.remove(path)
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,75 @@
+"""Fault schedules and what each injected fault does"""
+
+import unittest
+
+from fault_injection import FaultInjector, _seeded_random, parse_fault_schedule
+
+ENTRIES = [(True, bytes(range(64))) for _ in range(6)]
+
+
+class ScheduleTest(unittest.TestCase):
+    def test_parses_steps_ranges_and_repeats(self) -> None:
+        self.assertEqual(parse_fault_schedule('timeout@2, partial@3-4,corrupt/5'), [
+            {'type': 'timeout', 'from': 2, 'to': 2},
+            {'type': 'partial', 'from': 3, 'to': 4},
+            {'type': 'corrupt', 'every': 5},
+        ])
+        self.assertEqual(parse_fault_schedule(None), [])
+
+    def test_rejects_unknown_faults(self) -> None:
+        with self.assertRaisesRegex(ValueError, 'Invalid fault "flaky@1"'):
+            parse_fault_schedule('flaky@1')
+        with self.assertRaisesRegex(ValueError, 'Invalid fault "timeout"'):
+            parse_fault_schedule('timeout')
+
+    def test_faults_fire_on_their_steps(self) -> None:
+        faults = FaultInjector('timeout@2-3,storage/2')
+        steps = []
+        for _ in range(4):
+            faults.next_step()
+            steps.append((faults.active('timeout'), faults.active('storage')))
+        self.assertEqual(steps, [(False, False), (True, True), (True, False), (False, True)])
+
+    def test_seeds_match_the_typescript_generator(self) -> None:
+        random = _seeded_random(1)
+        self.assertEqual([random(), random(), random()], [0.6270739405881613, 0.002735721180215478, 0.5274470399599522])
+
+
+class InjectionTest(unittest.TestCase):
+    def test_timeouts_and_storage_outages_raise(self) -> None:
+        faults = FaultInjector('timeout@1,storage@1')
+        faults.next_step()
+        with self.assertRaisesRegex(TimeoutError, 'Injected RPC timeout'):
+            faults.call(lambda: 1)
+        with self.assertRaisesRegex(OSError, 'Injected storage outage'):
+            faults.write(lambda: 1)
+
+        faults.next_step()
+        self.assertEqual(faults.call(lambda: 1), 1)
+
+    def test_partial_empties_entries_but_never_protected_ones(self) -> None:
+        faults = FaultInjector('partial@1', rate=0)
+        faults.next_step()
+        mangled = faults.mangle_results(ENTRIES, [5])
+
+        emptied = [i for i, (success, data) in enumerate(mangled) if data == b'']
+        self.assertEqual(len(emptied), 1)
+        self.assertNotIn(5, emptied)
+        self.assertTrue(all(success for success, _ in mangled))
+
+    def test_corrupt_truncates_entries(self) -> None:
+        faults = FaultInjector('corrupt@1', rate=1)
+        faults.next_step()
+        mangled = faults.mangle_results(ENTRIES, [0])
+
+        self.assertEqual(mangled[0], ENTRIES[0])
+        self.assertTrue(all(len(data) < 64 for _, data in mangled[1:]))
+
+    def test_nothing_is_mangled_off_schedule(self) -> None:
+        faults = FaultInjector('partial@2,corrupt@2')
+        faults.next_step()
+        self.assertEqual(faults.mangle_results(ENTRIES), ENTRIES)
+
+
+if __name__ == '__main__':
+    unittest.main()
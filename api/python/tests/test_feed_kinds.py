@@ -0,0 +1,26 @@
+"""Feed classification by name and by explicit kind"""
+
+import unittest
+
+from feed_kinds import classify_feed
+
+
+class ClassifyFeedTest(unittest.TestCase):
+    def test_names_decide_without_a_kind(self) -> None:
+        self.assertEqual(classify_feed('BTC / USD'), 'price')
+        self.assertEqual(classify_feed('USDC.e Proof of Reserves'), 'por')
+        self.assertEqual(classify_feed('PCE Price Index — Percent Change (Annual Rate)'), 'rate')
+        self.assertEqual(classify_feed('PCE Price Index — Level'), 'index')
+
+    def test_an_explicit_kind_wins_over_the_name(self) -> None:
+        self.assertEqual(classify_feed('S&P 500 Index Rate', 'price'), 'price')
+        self.assertEqual(classify_feed('BTC / USD', ''), 'price')
+        self.assertEqual(classify_feed('BTC / USD', None), 'price')
+
+    def test_unknown_kinds_are_rejected(self) -> None:
+        with self.assertRaisesRegex(ValueError, 'Unknown kind "yield" for BTC / USD'):
+            classify_feed('BTC / USD', 'yield')
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,123 @@
+"""Silence matching, range parsing and config file loading"""
+
+import json
+import os
+import tempfile
+import unittest
+
+from silences import SilenceManager, SilenceValidationError, _parse_range
+
+AT = 1_753_056_000_000  # 2025-07-21T00:00:00Z
+MINUTE = 60_000
+
+
+class ParseRangeTest(unittest.TestCase):
+    def test_duration_starts_now(self) -> None:
+        self.assertEqual(_parse_range({'durationMinutes': 30}, AT),
+                         {'startsAt': '2025-07-21T00:00:00.000Z', 'endsAt': '2025-07-21T00:30:00.000Z'})
+
+    def test_offsets_are_normalised_to_utc(self) -> None:
+        span = _parse_range({'startsAt': '2025-07-21T02:00:00+02:00', 'endsAt': '2025-07-21T01:00:00Z'}, AT)
+        self.assertEqual(span, {'startsAt': '2025-07-21T00:00:00.000Z', 'endsAt': '2025-07-21T01:00:00.000Z'})
+
+    def test_rejects_bad_ranges(self) -> None:
+        for data, field in [
+            ({'endsAt': '2025-07-21T01:00:00'}, 'endsAt'),
+            ({'startsAt': 'tomorrow', 'durationMinutes': 5}, 'startsAt'),
+            ({}, 'endsAt'),
+            ({'durationMinutes': 0}, 'endsAt'),
+            ({'durationMinutes': True}, 'endsAt'),
+            ({'startsAt': '2025-07-21T01:00:00Z', 'endsAt': '2025-07-21T01:00:00Z'}, 'endsAt'),
+        ]:
+            with self.subTest(data=data):
+                with self.assertRaises(SilenceValidationError) as caught:
+                    _parse_range(data, AT)
+                self.assertEqual(caught.exception.field, field)
+
+
+class MatchTest(unittest.TestCase):
+    def setUp(self) -> None:
+        self.manager = SilenceManager(None)
+
+    def test_matches_feed_case_insensitively_and_by_type(self) -> None:
+        silence = self.manager.add_silence({'feed': 'btc / usd', 'alertType': 'stale', 'durationMinutes': 10}, AT)
+
+        self.assertEqual(self.manager.match('stale', 'BTC / USD', AT), silence['id'])
+        self.assertIsNone(self.manager.match('slow-updates', 'BTC / USD', AT))
+        self.assertIsNone(self.manager.match('stale', 'ETH / USD', AT))
+        self.assertIsNone(self.manager.match('stale', None, AT))
+
+    def test_window_is_half_open(self) -> None:
+        silence = self.manager.add_silence({'startsAt': '2025-07-21T00:10:00Z', 'durationMinutes': 10}, AT)
+
+        self.assertIsNone(self.manager.match('rpc-failure', None, AT + 10 * MINUTE - 1))
+        self.assertEqual(self.manager.match('rpc-failure', None, AT + 10 * MINUTE), silence['id'])
+        self.assertIsNone(self.manager.match('rpc-failure', None, AT + 20 * MINUTE))
+
+    def test_maintenance_windows_win(self) -> None:
+        self.manager.add_silence({'alertType': 'stale', 'durationMinutes': 10}, AT)
+        window = self.manager.add_maintenance_window({'durationMinutes': 5}, AT)
+
+        self.assertEqual(self.manager.match('stale', 'BTC / USD', AT), window['id'])
+        self.assertTrue(self.manager.list(AT)['inMaintenance'])
+
+    def test_apply_marks_the_alert(self) -> None:
+        silence = self.manager.add_silence({'feed': 'BTC / USD', 'durationMinutes': 10}, AT)
+        alert = {'type': 'stale', 'feed': 'BTC / USD', 'message': 'stale'}
+
+        self.assertEqual(self.manager.apply(alert, AT), {**alert, 'silenced': True, 'silencedBy': silence['id']})
+        self.assertEqual(self.manager.apply({**alert, 'feed': 'ETH / USD'}, AT)['silenced'], False)
+
+    def test_rejects_unknown_alert_types(self) -> None:
+        with self.assertRaises(SilenceValidationError):
+            self.manager.add_silence({'alertType': 'depeg', 'durationMinutes': 10}, AT)
+
+
+class LoadTest(unittest.TestCase):
+    def setUp(self) -> None:
+        handle, self.path = tempfile.mkstemp(suffix='.json')
+        os.close(handle)
+
+    def tearDown(self) -> None:
+        os.remove(self.path)
+
+    def _write(self, config: object) -> None:
+        with open(self.path, 'w') as f:
+            json.dump(config, f)
+
+    def test_skips_bad_and_expired_entries_individually(self) -> None:
+        self._write({
+            'silences': [
+                {'id': 'expired', 'endsAt': '2000-01-01T00:00:00Z'},
+                {'id': 'bad-type', 'alertType': 'depeg', 'durationMinutes': 10},
+                {'id': 'no-end'},
+                'not an entry',
+                {'id': 'kept', 'feed': 'BTC / USD', 'endsAt': '2999-01-01T00:00:00Z'},
+            ],
+            'maintenanceWindows': [{'id': 'window', 'durationMinutes': 60}],
+        })
+        manager = SilenceManager(self.path)
+
+        self.assertEqual([s['id'] for s in manager.silences], ['kept'])
+        self.assertEqual([w['id'] for w in manager.maintenance_windows], ['window'])
+
+    def test_durations_are_pinned_in_the_file(self) -> None:
+        self._write({'maintenanceWindows': [{'id': 'window', 'durationMinutes': 60}]})
+        first = SilenceManager(self.path).maintenance_windows[0]
+
+        with open(self.path) as f:
+            stored = json.load(f)['maintenanceWindows'][0]
+        self.assertEqual(stored, first)
+        self.assertNotIn('durationMinutes', stored)
+        self.assertEqual(SilenceManager(self.path).maintenance_windows[0], first)
+
+    def test_unreadable_file_is_left_alone(self) -> None:
+        with open(self.path, 'w') as f:
+            f.write('{not json')
+        self.assertEqual(SilenceManager(self.path).silences, [])
+        with open(self.path) as f:
+            self.assertEqual(f.read(), '{not json')
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,61 @@
+"""Time zone conversion for the ?tz option of report endpoints"""
+
+import unittest
+
+from time_utils import localize_timestamps, resolve_timezone, to_timezone
+
+
+class ResolveTimezoneTest(unittest.TestCase):
+    def test_unknown_time_zones_are_rejected_before_any_conversion(self) -> None:
+        for name in ('Mars/Olympus', 'America', '../etc/passwd', '/etc/localtime'):
+            with self.assertRaisesRegex(ValueError, 'unknown time zone'):
+                resolve_timezone(name)
+
+    def test_no_time_zone_means_no_conversion(self) -> None:
+        self.assertIsNone(resolve_timezone(None))
+        self.assertIsNone(resolve_timezone(''))
+        report = {'timestamp': '2025-07-21T00:00:00.000Z'}
+        self.assertIs(localize_timestamps(report, None), report)
+
+
+class ToTimezoneTest(unittest.TestCase):
+    def test_carries_the_offset_in_force_at_that_instant(self) -> None:
+        new_york = resolve_timezone('America/New_York')
+        assert new_york is not None
+        self.assertEqual(to_timezone('2025-07-21T00:59:55.000Z', new_york), '2025-07-20T20:59:55.000-04:00')
+        self.assertEqual(to_timezone('2025-01-15T12:00:00.250Z', new_york), '2025-01-15T07:00:00.250-05:00')
+
+        kolkata = resolve_timezone('Asia/Kolkata')
+        assert kolkata is not None
+        self.assertEqual(to_timezone('2025-07-21T00:59:55.000Z', kolkata), '2025-07-21T06:29:55.000+05:30')
+
+    def test_naive_values_are_read_as_utc(self) -> None:
+        berlin = resolve_timezone('Europe/Berlin')
+        assert berlin is not None
+        self.assertEqual(to_timezone('2025-07-21T00:00:00', berlin), '2025-07-21T02:00:00.000+02:00')
+
+
+class LocalizeTimestampsTest(unittest.TestCase):
+    def test_converts_timestamp_fields_at_any_depth_and_leaves_the_rest_alone(self) -> None:
+        report = {
+            'window': 288,
+            'timestamp': '2025-07-21T00:00:00.000Z',
+            'feeds': [
+                {'key': '2025-07-21T00:00:00.000Z', 'since': '2025-07-20T12:00:00.000Z', 'lastSuccess': None, 'availability': 0.5}
+            ],
+            'health': {'gradedAt': '2025-07-21T00:00:00.000Z', 'grade': 'A', 'lastUpdate': 'never'},
+        }
+
+        self.assertEqual(localize_timestamps(report, resolve_timezone('Europe/Berlin')), {
+            'window': 288,
+            'timestamp': '2025-07-21T02:00:00.000+02:00',
+            'feeds': [
+                {'key': '2025-07-21T00:00:00.000Z', 'since': '2025-07-20T14:00:00.000+02:00', 'lastSuccess': None, 'availability': 0.5}
+            ],
+            'health': {'gradedAt': '2025-07-21T02:00:00.000+02:00', 'grade': 'A', 'lastUpdate': 'never'},
+        })
+        self.assertEqual(report['timestamp'], '2025-07-21T00:00:00.000Z')
+
+
+if __name__ == '__main__':
+    unittest.main()
@@ -0,0 +1,49 @@
+"""Update intervals derived from observed rounds"""
+
+import os
+import tempfile
+import unittest
+
+from update_frequency import UpdateFrequencyTracker
+
+PHASE = 3 << 64
+
+
+class RecordObservationTest(unittest.TestCase):
+    def setUp(self) -> None:
+        self.tracker = UpdateFrequencyTracker(1.5, '')
+
+    def observe(self, updated_at: int, round_id: int) -> object:
+        return self.tracker.record_observation('BTC / USD', 3600, 0.5, updated_at, round_id, updated_at)
+
+    def test_repeated_rounds_are_not_updates(self) -> None:
+        self.assertIsNone(self.observe(1000, PHASE + 1))
+        self.assertIsNone(self.observe(1000, PHASE + 1))
+        self.assertEqual(self.observe(2000, PHASE + 2), 1000)
+
+    def test_missed_rounds_split_the_gap(self) -> None:
+        self.observe(1000, PHASE + 1)
+        self.assertEqual(self.observe(4000, PHASE + 4), 1000)
+
+    def test_phase_changes_are_not_intervals(self) -> None:
+        self.observe(1000, PHASE + 1)
+        self.assertIsNone(self.observe(2000, (4 << 64) + 1))
+        self.assertEqual(self.observe(3000, (4 << 64) + 2), 1000)
+
+    def test_first_observation_after_a_restart_is_skipped(self) -> None:
+        handle, path = tempfile.mkstemp(suffix='.json')
+        os.close(handle)
+        try:
+            self.tracker.persist_path = path
+            self.observe(1000, PHASE + 1)
+            self.tracker.save()
+
+            resumed = UpdateFrequencyTracker(1.5, path)
+            self.assertIsNone(resumed.record_observation('BTC / USD', 3600, 0.5, 90000, PHASE + 2, 90000))
+            self.assertEqual(resumed.record_observation('BTC / USD', 3600, 0.5, 93600, PHASE + 3, 93600), 3600)
+        finally:
+            os.remove(path)
+
+
+if __name__ == '__main__':
+    unittest.main()
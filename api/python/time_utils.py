@@ -0,0 +1,57 @@
+"""
+UTC time helpers
+Timestamps are produced in UTC as RFC 3339 strings with an explicit offset.
+Report endpoints can re-express them in a named IANA time zone on request.
+"""
+
+from datetime import datetime, timezone
+from typing import Any, Final, FrozenSet, Optional
+from zoneinfo import ZoneInfo, ZoneInfoNotFoundError
+
+# Response fields holding timestamps that the tz option converts
+TIMESTAMP_FIELDS: Final[FrozenSet[str]] = frozenset({
+    'timestamp', 'updatedAt', 'blockTimestamp', 'lastUpdated', 'lastRefresh',
+    'fromTimestamp', 'toTimestamp', 'since', 'lastSuccess', 'lastFailure', 'lastUpdate', 'gradedAt',
+})
+
+
+def utc_now_iso() -> str:
+    """Current UTC time in the same shape as JavaScript's toISOString()"""
+    return datetime.now(timezone.utc).isoformat(timespec='milliseconds').replace('+00:00', 'Z')
+
+
+def resolve_timezone(name: Optional[str]) -> Optional[ZoneInfo]:
+    """Look up an IANA time zone; None when no conversion was requested"""
+    if not name:
+        return None
+    try:
+        return ZoneInfo(name)
+    except (ZoneInfoNotFoundError, ValueError) as e:
+        raise ValueError(f"unknown time zone '{name}'") from e
+
+
+def to_timezone(value: str, tz: ZoneInfo) -> str:
+    """Re-express an RFC 3339 timestamp in tz, keeping the explicit offset; anything else is returned as is"""
+    try:
+        moment = datetime.fromisoformat(value)
+    except ValueError:
+        return value
+    if moment.tzinfo is None:
+        # Stored timestamps are UTC; never let a naive value be read as local time
+        moment = moment.replace(tzinfo=timezone.utc)
+    return moment.astimezone(tz).isoformat(timespec='milliseconds')
+
+
+def localize_timestamps(data: Any, tz: Optional[ZoneInfo]) -> Any:
+    """Convert every known timestamp field in a response payload to tz"""
+    if tz is None:
+        return data
+    if isinstance(data, list):
+        return [localize_timestamps(item, tz) for item in data]
+    if isinstance(data, dict):
+        return {
+            key: to_timezone(value, tz) if key in TIMESTAMP_FIELDS and isinstance(value, str) and value
+            else localize_timestamps(value, tz)
+            for key, value in data.items()
+        }
+    return data
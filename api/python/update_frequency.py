@@ -0,0 +1,178 @@
+"""
+Update frequency monitoring
+Records each distinct on-chain update observed per feed and compares the
+observed cadence over trailing windows against the documented heartbeat,
+flagging feeds that update significantly less often than promised
+"""
+
+import os
+import json
+import time
+from datetime import datetime, timezone
+from typing import Dict, List, Optional, Set, Tuple, Any, Final
+
+UPDATE_FREQUENCY_WINDOWS: Final[Tuple[Tuple[str, int], ...]] = (
+    ('1h', 3600),
+    ('24h', 86400),
+    ('7d', 604800),
+)
+
+# A feed is slow once a gap or its update count misses the heartbeat promise by this factor
+DEFAULT_UPDATE_TOLERANCE: Final[float] = 1.5
+
+RETENTION_SECONDS: Final[int] = max(seconds for _, seconds in UPDATE_FREQUENCY_WINDOWS)
+
+STATUS_RANK: Final[Dict[str, int]] = {'slow': 0, 'ok': 1, 'insufficient-data': 2}
+
+# Proxy round ids carry the phase in their top bits; rounds only count up within one phase
+PHASE_OFFSET: Final[int] = 64
+
+
+def _rounds_between(previous: Optional[str], current: int) -> Optional[int]:
+    if previous is None:
+        return None
+    last = int(previous)
+    if last >> PHASE_OFFSET != current >> PHASE_OFFSET or current <= last:
+        return None
+    return current - last
+
+
+def _iso(seconds: float) -> str:
+    return datetime.fromtimestamp(seconds, tz=timezone.utc).isoformat(timespec='milliseconds').replace('+00:00', 'Z')
+
+
+def _tolerance_from_env() -> float:
+    try:
+        return float(os.environ.get('UPDATE_TOLERANCE', '')) or DEFAULT_UPDATE_TOLERANCE
+    except ValueError:
+        return DEFAULT_UPDATE_TOLERANCE
+
+
+class UpdateFrequencyTracker:
+    """Distinct updatedAt values per feed over the longest trailing window"""
+    
+    def __init__(self, tolerance: Optional[float] = None, persist_path: Optional[str] = None) -> None:
+        self.tolerance = tolerance if tolerance is not None else _tolerance_from_env()
+        self.persist_path = persist_path if persist_path is not None else os.environ.get('UPDATE_FREQUENCY_FILE')
+        self.feeds: Dict[str, Dict[str, Any]] = {}
+        self._resumed: Set[str] = set()  # loaded from disk, not yet observed since
+        self._load()
+    
+    def record_observation(self, symbol: str, heartbeat: int, deviation_threshold: float,
+                           updated_at: int, round_id: int, now: Optional[float] = None) -> Optional[float]:
+        """Polls see the same round repeatedly; only a new updatedAt counts as an update.
+        Returns the mean seconds per round since the previous observed update: a poll can miss
+        rounds, so the gap is divided by how far roundId moved. The first observation after
+        loading persisted state returns None, as its gap spans the restart."""
+        now = now if now is not None else time.time()
+        feed = self.feeds.setdefault(symbol, {
+            "heartbeat": heartbeat,
+            "deviationThreshold": deviation_threshold,
+            "firstSeen": min(now, updated_at),
+            "updates": []
+        })
+        feed["heartbeat"] = heartbeat
+        feed["deviationThreshold"] = deviation_threshold
+        resumed = symbol in self._resumed
+        self._resumed.discard(symbol)
+        
+        updates: List[int] = feed["updates"]
+        last = updates[-1] if updates else None
+        is_new = last is None or updated_at > last
+        if is_new:
+            updates.append(updated_at)
+        rounds = _rounds_between(feed.get("lastRoundId"), round_id)
+        feed["lastRoundId"] = str(round_id)
+        
+        cutoff = now - RETENTION_SECONDS
+        while len(updates) > 1 and updates[0] < cutoff:
+            updates.pop(0)
+        feed["firstSeen"] = max(feed["firstSeen"], cutoff)
+        if not is_new or last is None or resumed or rounds is None:
+            return None
+        return (updated_at - last) / rounds
+    
+    def get_report(self, now: Optional[float] = None) -> Dict[str, Any]:
+        now = now if now is not None else time.time()
+        records = [self._summarize(symbol, feed, now) for symbol, feed in self.feeds.items()]
+        records.sort(key=lambda r: (STATUS_RANK[r["status"]], -r["secondsSinceUpdate"]))
+        return {
+            "tolerance": self.tolerance,
+            "windows": [label for label, _ in UPDATE_FREQUENCY_WINDOWS],
+            "feeds": records
+        }
+    
+    def get_alerts(self, now: Optional[float] = None) -> List[Dict[str, Any]]:
+        return [record for record in self.get_report(now)["feeds"] if record["status"] == 'slow']
+    
+    def save(self) -> None:
+        """Write observed updates to disk so trailing windows survive restarts"""
+        if not self.persist_path:
+            return
+        try:
+            with open(self.persist_path, 'w') as f:
+                json.dump(self.feeds, f)
+        except OSError as e:
+            print(f"Warning: Could not persist update frequency to {self.persist_path}: {e}")
+    
+    def _load(self) -> None:
+        if not self.persist_path or not os.path.exists(self.persist_path):
+            return
+        try:
+            with open(self.persist_path, 'r') as f:
+                self.feeds = json.load(f)
+            self._resumed = set(self.feeds)
+        except (OSError, ValueError) as e:
+            print(f"Warning: Ignoring unreadable update frequency file {self.persist_path}: {e}")
+    
+    def _summarize(self, symbol: str, feed: Dict[str, Any], now: float) -> Dict[str, Any]:
+        heartbeat = feed["heartbeat"]
+        last = feed["updates"][-1]
+        seconds_since_update = max(0, round(now - last))
+        limit = heartbeat * self.tolerance
+        
+        windows = [self._summarize_window(label, seconds, feed, now) for label, seconds in UPDATE_FREQUENCY_WINDOWS]
+        reasons: List[str] = []
+        
+        if seconds_since_update > limit:
+            reasons.append(f"no update for {seconds_since_update}s (heartbeat {heartbeat}s)")
+        for w in windows:
+            if w["maxGap"] is not None and w["maxGap"] > limit:
+                reasons.append(f"{w['window']}: {w['maxGap']}s gap between updates (heartbeat {heartbeat}s)")
+            elif w["updates"] < int(w["coverage"] // limit):
+                reasons.append(f"{w['window']}: {w['updates']} updates, heartbeat promises at least {w['expectedUpdates']}")
+        
+        # Until a full heartbeat has been observed there is nothing to compare against
+        if reasons:
+            status = 'slow'
+        elif now - feed["firstSeen"] < heartbeat:
+            status = 'insufficient-data'
+        else:
+            status = 'ok'
+        
+        return {
+            "symbol": symbol,
+            "heartbeat": heartbeat,
+            "deviationThreshold": feed["deviationThreshold"],
+            "lastUpdate": _iso(last),
+            "secondsSinceUpdate": seconds_since_update,
+            "status": status,
+            "reasons": reasons,
+            "windows": windows
+        }
+    
+    def _summarize_window(self, label: str, seconds: int, feed: Dict[str, Any], now: float) -> Dict[str, Any]:
+        """Only the part of the window the tracker has actually observed counts toward expectations"""
+        start = now - seconds
+        coverage = max(0, min(seconds, now - feed["firstSeen"]))
+        in_window = [at for at in feed["updates"] if at >= start]
+        gaps = [b - a for a, b in zip(in_window, in_window[1:])]
+        
+        return {
+            "window": label,
+            "coverage": round(coverage),
+            "updates": len(in_window),
+            "expectedUpdates": int(coverage // feed["heartbeat"]),
+            "meanInterval": round(sum(gaps) / len(gaps)) if gaps else None,
+            "maxGap": max(gaps) if gaps else None
+        }
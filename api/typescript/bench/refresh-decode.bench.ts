@@ -0,0 +1,112 @@
+/**
+ * Refresh Cycle Benchmark
+ * Compares per-cycle heap allocations of the original encode + ethers Result decode path
+ * against precomputed calldata with the in-place aggregate3() decoder
+ *
+ * Usage: npm run bench (BENCH_FEEDS and BENCH_CYCLES override the defaults)
+ */
+
+import { ethers } from 'ethers';
+import { decodeAggregate3Result } from '../src/utils/multicallCodec';
+
+const FEEDS = Number(process.env.BENCH_FEEDS || 1000);
+const CYCLES = Number(process.env.BENCH_CYCLES || 50);
+const MULTICALL3_ADDRESS = '0xcA11bde05977b3631167028862bE2a173976CA11';
+
+const coder = ethers.AbiCoder.defaultAbiCoder();
+const chainlinkInterface = new ethers.Interface([
+  'function latestRoundData() view returns (uint80 roundId, int256 answer, uint256 startedAt, uint256 updatedAt, uint80 answeredInRound)'
+]);
+const multicallInterface = new ethers.Interface([
+  'function aggregate3((address target, bool allowFailure, bytes callData)[] calls) payable returns ((bool success, bytes returnData)[] returnData)',
+  'function getBlockNumber() view returns (uint256 blockNumber)',
+  'function getCurrentBlockTimestamp() view returns (uint256 timestamp)'
+]);
+
+const targets = Array.from({ length: FEEDS }, (_, i) => ethers.getAddress(ethers.toBeHex(i + 1, 20)));
+
+// Synthetic aggregate3() result: block number and timestamp followed by one latestRoundData answer per feed
+function buildResult(): string {
+  const returnData = [coder.encode(['uint256'], [65814031]), coder.encode(['uint256'], [1753058462])];
+  targets.forEach((_, i) => {
+    const roundId = 18446744073709562301n + BigInt(i);
+    returnData.push(coder.encode(
+      ['uint80', 'int256', 'uint256', 'uint256', 'uint80'],
+      [roundId, 11740279073738n + BigInt(i), 1753058400, 1753058400, roundId]
+    ));
+  });
+  return multicallInterface.encodeFunctionResult('aggregate3', [returnData.map(data => [true, data])]);
+}
+
+// What every refresh cycle used to do: encode all calls, then decode through ethers Results
+function originalCycle(result: string): number {
+  const calls = targets.map(target => ({
+    target,
+    allowFailure: true,
+    callData: chainlinkInterface.encodeFunctionData('latestRoundData', [])
+  }));
+  calls.unshift(
+    { target: MULTICALL3_ADDRESS, allowFailure: false, callData: multicallInterface.encodeFunctionData('getBlockNumber', []) },
+    { target: MULTICALL3_ADDRESS, allowFailure: false, callData: multicallInterface.encodeFunctionData('getCurrentBlockTimestamp', []) }
+  );
+  multicallInterface.encodeFunctionData('aggregate3', [calls]);
+
+  let checksum = 0;
+  const [results] = multicallInterface.decodeFunctionResult('aggregate3', result);
+  results.slice(2).forEach(([, data]: [boolean, string]) => {
+    const [, answer, , updatedAt] = chainlinkInterface.decodeFunctionResult('latestRoundData', data);
+    checksum += Number(answer % 1000n) + Number(updatedAt % 10n);
+  });
+  return checksum;
+}
+
+// Calldata is precomputed once, so a cycle is only the in-place decode
+function inPlaceCycle(result: string): number {
+  let checksum = 0;
+  decodeAggregate3Result(result, (index, data) => {
+    if (index < 2) return;
+    checksum += Number(data.int(1) % 1000n) + Number(data.uint(3) % 10n);
+  });
+  return checksum;
+}
+
+function measure(name: string, cycle: (result: string) => number, result: string): number {
+  const gc = (global as any).gc as (() => void) | undefined;
+  if (!gc) {
+    throw new Error('Run with --expose-gc to measure allocations');
+  }
+
+  cycle(result); // warm up
+
+  let allocated = 0;
+  for (let i = 0; i < CYCLES; i++) {
+    gc();
+    const before = process.memoryUsage().heapUsed;
+    cycle(result);
+    allocated += process.memoryUsage().heapUsed - before;
+  }
+
+  const start = process.hrtime.bigint();
+  for (let i = 0; i < CYCLES; i++) {
+    cycle(result);
+  }
+  const elapsedMs = Number(process.hrtime.bigint() - start) / 1e6;
+
+  const perCycle = allocated / CYCLES;
+  console.log(
+    `${name.padEnd(10)} ${(perCycle / 1024).toFixed(1).padStart(10)} KiB/cycle ` +
+    `${(elapsedMs / CYCLES).toFixed(2).padStart(8)} ms/cycle`
+  );
+  return perCycle;
+}
+
+const result = buildResult();
+console.log(`📊 ${FEEDS} feeds, ${CYCLES} cycles`);
+
+if (originalCycle(result) !== inPlaceCycle(result)) {
+  throw new Error('Decoders disagree');
+}
+
+const original = measure('original', originalCycle, result);
+const inPlace = measure('in-place', inPlaceCycle, result);
+console.log(`✅ ${(original / Math.max(inPlace, 1)).toFixed(1)}x less garbage per cycle`);
@@ -0,0 +1,8 @@
+// Unit tests live in test/ and run against the TypeScript sources through ts-jest
+module.exports = {
+  testEnvironment: 'node',
+  roots: ['<rootDir>/test'],
+  transform: {
+    '^.+\\.ts$': ['ts-jest', { tsconfig: 'tsconfig.test.json' }]
+  }
+};
@@ -0,0 +1,102 @@
+/**
+ * Typed binding for Chainlink AggregatorV3Interface
+ * The ABI and result types are generated from abi/AggregatorV3Interface.json
+ * (npm run generate-bindings); this class pairs each method with a typed
+ * encoder/decoder, so return tuple shapes are checked at compile time
+ */
+
+import { ethers } from 'ethers';
+import { AGGREGATOR_V3_INTERFACE_ABI, LatestRoundDataOutput } from './generated/AggregatorV3Interface';
+
+export type RoundDataResult = LatestRoundDataOutput;
+
+export const AGGREGATOR_V3_ABI = AGGREGATOR_V3_INTERFACE_ABI;
+
+const iface = new ethers.Interface(AGGREGATOR_V3_ABI);
+
+function toRoundData(result: ethers.Result): RoundDataResult {
+  return {
+    roundId: result[0] as bigint,
+    answer: result[1] as bigint,
+    startedAt: result[2] as bigint,
+    updatedAt: result[3] as bigint,
+    answeredInRound: result[4] as bigint
+  };
+}
+
+export class AggregatorV3Interface {
+  static readonly interface = iface;
+
+  constructor(
+    readonly address: string,
+    private readonly runner: ethers.Provider
+  ) {}
+
+  // Calldata encoders and result decoders, for batching through Multicall3
+
+  static encodeDecimals(): string {
+    return iface.encodeFunctionData('decimals', []);
+  }
+
+  static decodeDecimals(data: string): number {
+    return Number(iface.decodeFunctionResult('decimals', data)[0]);
+  }
+
+  static encodeDescription(): string {
+    return iface.encodeFunctionData('description', []);
+  }
+
+  static decodeDescription(data: string): string {
+    return iface.decodeFunctionResult('description', data)[0] as string;
+  }
+
+  static encodeVersion(): string {
+    return iface.encodeFunctionData('version', []);
+  }
+
+  static decodeVersion(data: string): bigint {
+    return iface.decodeFunctionResult('version', data)[0] as bigint;
+  }
+
+  static encodeGetRoundData(roundId: bigint): string {
+    return iface.encodeFunctionData('getRoundData', [roundId]);
+  }
+
+  static decodeGetRoundData(data: string): RoundDataResult {
+    return toRoundData(iface.decodeFunctionResult('getRoundData', data));
+  }
+
+  static encodeLatestRoundData(): string {
+    return iface.encodeFunctionData('latestRoundData', []);
+  }
+
+  static decodeLatestRoundData(data: string): RoundDataResult {
+    return toRoundData(iface.decodeFunctionResult('latestRoundData', data));
+  }
+
+  // Direct calls against a single feed
+
+  async decimals(): Promise<number> {
+    return AggregatorV3Interface.decodeDecimals(await this.call(AggregatorV3Interface.encodeDecimals()));
+  }
+
+  async description(): Promise<string> {
+    return AggregatorV3Interface.decodeDescription(await this.call(AggregatorV3Interface.encodeDescription()));
+  }
+
+  async version(): Promise<bigint> {
+    return AggregatorV3Interface.decodeVersion(await this.call(AggregatorV3Interface.encodeVersion()));
+  }
+
+  async getRoundData(roundId: bigint): Promise<RoundDataResult> {
+    return AggregatorV3Interface.decodeGetRoundData(await this.call(AggregatorV3Interface.encodeGetRoundData(roundId)));
+  }
+
+  async latestRoundData(): Promise<RoundDataResult> {
+    return AggregatorV3Interface.decodeLatestRoundData(await this.call(AggregatorV3Interface.encodeLatestRoundData()));
+  }
+
+  private call(data: string): Promise<string> {
+    return this.runner.call({ to: this.address, data });
+  }
+}
@@ -0,0 +1,50 @@
+/**
+ * Built-in exchange-rate feed types
+ * ERC-4626 vaults and liquid staking tokens expose the value of one share
+ * through a view method; these are batched alongside the Chainlink feeds
+ */
+
+import { ethers } from 'ethers';
+
+export type ExchangeRateFeedType = 'erc4626' | 'lst';
+
+export interface ExchangeRateFeed {
+  name: string;
+  symbol: string;
+  type: ExchangeRateFeedType;
+  address: string;
+  method: string;
+  decimals: number;
+}
+
+// Rates are quoted for one whole share (18-decimal share tokens)
+export const ONE_SHARE = 10n ** 18n;
+
+interface RateMethod {
+  signature: string;
+  takesShares: boolean;
+}
+
+// Known one-share rate getters; ERC-4626 always uses convertToAssets
+export const RATE_METHODS: Record<string, RateMethod> = {
+  convertToAssets: { signature: 'function convertToAssets(uint256 shares) view returns (uint256)', takesShares: true },
+  getPooledAvaxByShares: { signature: 'function getPooledAvaxByShares(uint256 shareAmount) view returns (uint256)', takesShares: true },
+  getExchangeRate: { signature: 'function getExchangeRate() view returns (uint256)', takesShares: false },
+  exchangeRate: { signature: 'function exchangeRate() view returns (uint256)', takesShares: false },
+  getRate: { signature: 'function getRate() view returns (uint256)', takesShares: false }
+};
+
+export const DEFAULT_METHOD: Record<ExchangeRateFeedType, string | undefined> = {
+  erc4626: 'convertToAssets',
+  lst: undefined
+};
+
+const iface = new ethers.Interface(Object.values(RATE_METHODS).map(m => m.signature));
+
+export function encodeRateCall(method: string): string {
+  const rateMethod = RATE_METHODS[method];
+  if (!rateMethod) {
+    throw new Error(`Unsupported exchange-rate method: ${method}`);
+  }
+  return iface.encodeFunctionData(method, rateMethod.takesShares ? [ONE_SHARE] : []);
+}
@@ -0,0 +1,43 @@
+/**
+ * ERC-2362 generic oracle feeds
+ * One oracle contract serves many values keyed by a bytes32 id through
+ * valueFor(id) -> (int256 value, uint256 timestamp, uint256 statusCode);
+ * these are batched alongside the Chainlink feeds
+ */
+
+import { ethers } from 'ethers';
+
+export interface GenericOracleFeed {
+  name: string;
+  symbol: string;
+  address: string;
+  id: string; // bytes32
+  decimals: number;
+}
+
+// Status codes follow HTTP; only 200 carries a usable value
+export const ERC2362_STATUS_OK = 200n;
+
+const iface = new ethers.Interface([
+  'function valueFor(bytes32 _id) view returns (int256 value, uint256 timestamp, uint256 statusCode)'
+]);
+
+/**
+ * Ids are given as bytes32 or as the "<base>-<quote>-<decimals>" string the standard
+ * hashes, in which case decimals come from the id unless explicitly set.
+ * Returns null when the id is unusable or decimals cannot be determined.
+ */
+export function resolveOracleId(id: unknown, decimals?: number): { id: string; decimals: number } | null {
+  if (typeof id !== 'string') return null;
+  if (/^0x[0-9a-fA-F]{64}$/.test(id)) {
+    return decimals === undefined ? null : { id, decimals };
+  }
+
+  const match = /^[a-z0-9]+-[a-z0-9]+-(\d+)$/.exec(id);
+  if (!match) return null;
+  return { id: ethers.id(id), decimals: decimals ?? Number(match[1]) };
+}
+
+export function encodeValueFor(id: string): string {
+  return iface.encodeFunctionData('valueFor', [id]);
+}
@@ -0,0 +1,82 @@
+/**
+ * Typed binding for Multicall3
+ * The ABI and struct types are generated from abi/Multicall3.json
+ * (npm run generate-bindings); covers the subset used to batch feed reads
+ */
+
+import { ethers } from 'ethers';
+import { MULTICALL3_ABI, AggregateOutput, CallStruct, Call3Struct, ResultStruct } from './generated/Multicall3';
+
+export type Call = CallStruct;
+export type Call3 = Call3Struct;
+export type AggregateResult = AggregateOutput;
+export type Call3Result = ResultStruct;
+
+export { MULTICALL3_ABI };
+
+export const MULTICALL3_ADDRESS = '0xcA11bde05977b3631167028862bE2a173976CA11';
+
+const iface = new ethers.Interface(MULTICALL3_ABI);
+
+export class Multicall3 {
+  static readonly interface = iface;
+
+  // gasLimit lifts the eth_call gas cap on providers whose default is too low for large batches
+  constructor(
+    private readonly runner: ethers.Provider,
+    readonly address: string = MULTICALL3_ADDRESS,
+    private readonly gasLimit?: bigint
+  ) {}
+
+  // Calldata encoders and result decoders
+
+  static encodeAggregate(calls: Call[]): string {
+    return iface.encodeFunctionData('aggregate', [calls]);
+  }
+
+  static decodeAggregate(data: string): AggregateResult {
+    const [blockNumber, returnData] = iface.decodeFunctionResult('aggregate', data);
+    return {
+      blockNumber: blockNumber as bigint,
+      returnData: [...returnData] as string[]
+    };
+  }
+
+  static encodeAggregate3(calls: Call3[]): string {
+    return iface.encodeFunctionData('aggregate3', [calls]);
+  }
+
+  static decodeAggregate3(data: string): Call3Result[] {
+    const [results] = iface.decodeFunctionResult('aggregate3', data);
+    return [...results].map(([success, returnData]: [boolean, string]) => ({ success, returnData }));
+  }
+
+  static encodeGetBlockNumber(): string {
+    return iface.encodeFunctionData('getBlockNumber', []);
+  }
+
+  static encodeGetCurrentBlockTimestamp(): string {
+    return iface.encodeFunctionData('getCurrentBlockTimestamp', []);
+  }
+
+  static decodeGetCurrentBlockTimestamp(data: string): bigint {
+    return iface.decodeFunctionResult('getCurrentBlockTimestamp', data)[0] as bigint;
+  }
+
+  // Direct calls
+
+  /** Execute aggregate() as a read-only call */
+  async aggregate(calls: Call[]): Promise<AggregateResult> {
+    return Multicall3.decodeAggregate(await this.call(Multicall3.encodeAggregate(calls)));
+  }
+
+  /** Execute aggregate3() as a read-only call; calls that allow failure report it per entry */
+  async aggregate3(calls: Call3[]): Promise<Call3Result[]> {
+    return Multicall3.decodeAggregate3(await this.call(Multicall3.encodeAggregate3(calls)));
+  }
+
+  /** Execute pre-encoded calldata and return the raw result, for in-place decoding */
+  call(data: string): Promise<string> {
+    return this.runner.call({ to: this.address, data, gasLimit: this.gasLimit });
+  }
+}
@@ -0,0 +1,44 @@
+/**
+ * Chain registry
+ * RPC and Multicall3 per chain, selected with CHAIN (default avalanche).
+ * Testnets read their own feed file and are flagged in /health so their
+ * numbers are never taken for mainnet ones.
+ */
+
+import { MULTICALL3_ADDRESS } from './Multicall3';
+
+export interface ChainConfig {
+  name: string;
+  chainId: number;
+  testnet: boolean;
+  rpcUrl: string;
+  multicall3: string;
+  feedsFile: string;
+}
+
+export const CHAINS: Record<string, ChainConfig> = {
+  avalanche: {
+    name: 'avalanche',
+    chainId: 43114,
+    testnet: false,
+    rpcUrl: 'https://api.avax.network/ext/bc/C/rpc',
+    multicall3: MULTICALL3_ADDRESS,
+    feedsFile: 'avalanche_chainlink_feeds.csv'
+  },
+  // Public endpoint without an API key; reads are eth_calls, so no funded account either
+  fuji: {
+    name: 'fuji',
+    chainId: 43113,
+    testnet: true,
+    rpcUrl: 'https://api.avax-test.network/ext/bc/C/rpc',
+    multicall3: MULTICALL3_ADDRESS,
+    feedsFile: 'fuji_chainlink_feeds.csv'
+  }
+};
+
+export function resolveChain(name: string = process.env.CHAIN || 'avalanche'): ChainConfig {
+  if (!Object.prototype.hasOwnProperty.call(CHAINS, name)) {
+    throw new Error(`Unknown chain "${name}", expected one of ${Object.keys(CHAINS).join(', ')}`);
+  }
+  return CHAINS[name];
+}
@@ -0,0 +1,10 @@
+export { AggregatorV3Interface, AGGREGATOR_V3_ABI } from './AggregatorV3Interface';
+export type { RoundDataResult } from './AggregatorV3Interface';
+export { Multicall3, MULTICALL3_ABI, MULTICALL3_ADDRESS } from './Multicall3';
+export type { Call, Call3, AggregateResult, Call3Result } from './Multicall3';
+export { encodeRateCall, RATE_METHODS, DEFAULT_METHOD, ONE_SHARE } from './ExchangeRate';
+export type { ExchangeRateFeed, ExchangeRateFeedType } from './ExchangeRate';
+export { encodeValueFor, resolveOracleId, ERC2362_STATUS_OK } from './GenericOracle';
+export type { GenericOracleFeed } from './GenericOracle';
+export { CHAINS, resolveChain } from './chains';
+export type { ChainConfig } from './chains';
@@ -10,8 +10,15 @@ import cron from 'node-cron';
 import { feedsRouter } from './routes/feeds';
 import { pricesRouter } from './routes/prices';
 import { healthRouter } from './routes/health';
+import { alertsRouter } from './routes/alerts';
 import { PriceService } from './services/PriceService';
 import { errorHandler, notFoundHandler } from './middleware/errorHandler';
+import { metricsRegistry } from './utils/metrics';
+import { applyProfile } from './utils/profile';
+
+// A profile fills in settings before anything reads them
+const profile = applyProfile();
+if (profile) console.log(`🧭 Using profile ${profile}`);
 
 const app = express();
 const PORT = process.env.PORT || 3000;
@@ -86,6 +93,10 @@ const swaggerOptions = {
       {
         name: 'Prices',
         description: 'Real-time price data from Chainlink feeds'
+      },
+      {
+        name: 'Alerts',
+        description: 'Current alerts, silences and maintenance windows'
       }
     ]
   },
@@ -119,6 +130,13 @@ app.use((req, res, next) => {
 app.use('/health', healthRouter);
 app.use('/feeds', feedsRouter);
 app.use('/prices', pricesRouter);
+app.use('/alerts', alertsRouter);
+
+// Prometheus scrape endpoint
+app.get('/metrics', async (req, res) => {
+  res.setHeader('Content-Type', metricsRegistry.contentType);
+  res.send(await metricsRegistry.metrics());
+});
 
 // Root endpoint
 app.get('/', (req, res) => {
@@ -131,7 +149,9 @@ app.get('/', (req, res) => {
     endpoints: {
       health: '/health',
       feeds: '/feeds',
-      prices: '/prices'
+      prices: '/prices',
+      alerts: '/alerts',
+      metrics: '/metrics'
     },
     github: 'https://github.com/avasnap/cchainlink'
   });
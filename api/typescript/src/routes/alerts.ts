@@ -0,0 +1,276 @@
+import { Router, Request, Response } from 'express';
+import { PriceService } from '../services/PriceService';
+import { Alert, ApiResponse, MaintenanceWindow, Silence, SilenceList } from '../types';
+import { asyncHandler } from '../middleware/errorHandler';
+import { SilenceNotFoundError } from '../utils/errors';
+
+export const alertsRouter = Router();
+
+/**
+ * @swagger
+ * components:
+ *   schemas:
+ *     Alert:
+ *       type: object
+ *       properties:
+ *         type:
+ *           type: string
+ *           enum: [stale, slow-updates, rpc-failure]
+ *         feed:
+ *           type: string
+ *           nullable: true
+ *           example: "BTCUSD"
+ *         message:
+ *           type: string
+ *         silenced:
+ *           type: boolean
+ *         silencedBy:
+ *           type: string
+ *           nullable: true
+ *           description: Id of the silence or maintenance window suppressing the alert
+ *     Silence:
+ *       type: object
+ *       properties:
+ *         id:
+ *           type: string
+ *         feed:
+ *           type: string
+ *           nullable: true
+ *           description: Feed symbol; null silences every feed
+ *         alertType:
+ *           type: string
+ *           enum: [stale, slow-updates, rpc-failure]
+ *           nullable: true
+ *           description: Alert type; null silences every type
+ *         startsAt:
+ *           type: string
+ *           format: date-time
+ *         endsAt:
+ *           type: string
+ *           format: date-time
+ *         reason:
+ *           type: string
+ *     MaintenanceWindow:
+ *       type: object
+ *       properties:
+ *         id:
+ *           type: string
+ *         startsAt:
+ *           type: string
+ *           format: date-time
+ *         endsAt:
+ *           type: string
+ *           format: date-time
+ *         reason:
+ *           type: string
+ *     SilenceInput:
+ *       type: object
+ *       properties:
+ *         startsAt:
+ *           type: string
+ *           format: date-time
+ *           description: Defaults to now
+ *         endsAt:
+ *           type: string
+ *           format: date-time
+ *         durationMinutes:
+ *           type: number
+ *           description: Used when endsAt is omitted
+ *           example: 120
+ *         reason:
+ *           type: string
+ *           example: "RPC provider maintenance"
+ */
+
+/**
+ * @swagger
+ * /alerts:
+ *   get:
+ *     summary: Current alerts
+ *     description: |
+ *       Alerts currently firing: stale or slow-updating feeds and RPC endpoints whose last
+ *       call failed. Silenced alerts are omitted unless includeSilenced=true.
+ *     tags: [Alerts]
+ *     parameters:
+ *       - in: query
+ *         name: includeSilenced
+ *         required: false
+ *         schema:
+ *           type: boolean
+ *     responses:
+ *       200:
+ *         description: Current alerts
+ *         content:
+ *           application/json:
+ *             schema:
+ *               type: object
+ *               properties:
+ *                 success:
+ *                   type: boolean
+ *                 data:
+ *                   type: array
+ *                   items:
+ *                     $ref: '#/components/schemas/Alert'
+ *                 timestamp:
+ *                   type: string
+ *                   format: date-time
+ */
+alertsRouter.get('/', (req: Request, res: Response) => {
+  const priceService: PriceService = (req as any).priceService;
+  const includeSilenced = req.query.includeSilenced === 'true';
+
+  const response: ApiResponse<Alert[]> = {
+    success: true,
+    data: priceService.getAlerts().filter(alert => includeSilenced || !alert.silenced),
+    timestamp: new Date().toISOString()
+  };
+
+  res.json(response);
+});
+
+/**
+ * @swagger
+ * /alerts/silences:
+ *   get:
+ *     summary: List silences and maintenance windows
+ *     tags: [Alerts]
+ *     responses:
+ *       200:
+ *         description: Configured silences and maintenance windows
+ *         content:
+ *           application/json:
+ *             schema:
+ *               type: object
+ *               properties:
+ *                 success:
+ *                   type: boolean
+ *                 data:
+ *                   type: object
+ *                   properties:
+ *                     silences:
+ *                       type: array
+ *                       items:
+ *                         $ref: '#/components/schemas/Silence'
+ *                     maintenanceWindows:
+ *                       type: array
+ *                       items:
+ *                         $ref: '#/components/schemas/MaintenanceWindow'
+ *                     inMaintenance:
+ *                       type: boolean
+ *                 timestamp:
+ *                   type: string
+ *                   format: date-time
+ *   post:
+ *     summary: Create a silence
+ *     description: Silences alerts for one feed and/or one alert type until endsAt
+ *     tags: [Alerts]
+ *     requestBody:
+ *       required: true
+ *       content:
+ *         application/json:
+ *           schema:
+ *             allOf:
+ *               - $ref: '#/components/schemas/SilenceInput'
+ *               - type: object
+ *                 properties:
+ *                   feed:
+ *                     type: string
+ *                     example: "BTCUSD"
+ *                   alertType:
+ *                     type: string
+ *                     enum: [stale, slow-updates, rpc-failure]
+ *     responses:
+ *       201:
+ *         description: Silence created
+ *       400:
+ *         description: Invalid silence
+ */
+alertsRouter.get('/silences', (req: Request, res: Response) => {
+  const priceService: PriceService = (req as any).priceService;
+
+  const response: ApiResponse<SilenceList> = {
+    success: true,
+    data: priceService.getSilences().list(),
+    timestamp: new Date().toISOString()
+  };
+
+  res.json(response);
+});
+
+alertsRouter.post('/silences', asyncHandler(async (req: Request, res: Response) => {
+  const priceService: PriceService = (req as any).priceService;
+
+  const response: ApiResponse<Silence> = {
+    success: true,
+    data: priceService.getSilences().addSilence(req.body ?? {}),
+    timestamp: new Date().toISOString()
+  };
+
+  res.status(201).json(response);
+}));
+
+/**
+ * @swagger
+ * /alerts/maintenance:
+ *   post:
+ *     summary: Schedule a maintenance window
+ *     description: Silences every alert between startsAt and endsAt
+ *     tags: [Alerts]
+ *     requestBody:
+ *       required: true
+ *       content:
+ *         application/json:
+ *           schema:
+ *             $ref: '#/components/schemas/SilenceInput'
+ *     responses:
+ *       201:
+ *         description: Maintenance window created
+ *       400:
+ *         description: Invalid time range
+ */
+alertsRouter.post('/maintenance', asyncHandler(async (req: Request, res: Response) => {
+  const priceService: PriceService = (req as any).priceService;
+
+  const response: ApiResponse<MaintenanceWindow> = {
+    success: true,
+    data: priceService.getSilences().addMaintenanceWindow(req.body ?? {}),
+    timestamp: new Date().toISOString()
+  };
+
+  res.status(201).json(response);
+}));
+
+/**
+ * @swagger
+ * /alerts/silences/{id}:
+ *   delete:
+ *     summary: Remove a silence or maintenance window
+ *     tags: [Alerts]
+ *     parameters:
+ *       - in: path
+ *         name: id
+ *         required: true
+ *         schema:
+ *           type: string
+ *     responses:
+ *       200:
+ *         description: Removed
+ *       404:
+ *         description: No silence or maintenance window with that id
+ */
+alertsRouter.delete('/silences/:id', asyncHandler(async (req: Request, res: Response) => {
+  const priceService: PriceService = (req as any).priceService;
+  const { id } = req.params;
+
+  if (!priceService.getSilences().remove(id)) {
+    throw new SilenceNotFoundError(id);
+  }
+
+  const response: ApiResponse<{ id: string }> = {
+    success: true,
+    data: { id },
+    timestamp: new Date().toISOString()
+  };
+
+  res.json(response);
+}));
@@ -1,8 +1,9 @@
 import { Router, Request, Response, NextFunction } from 'express';
 import { PriceService } from '../services/PriceService';
-import { ApiResponse, FeedMetadata } from '../types';
+import { ApiResponse, FeedMetadata, VolatilityData } from '../types';
 import { asyncHandler } from '../middleware/errorHandler';
-import { FeedNotFoundError, FeedsLoadError } from '../utils/errors';
+import { FeedNotFoundError, FeedsLoadError, ValidationError } from '../utils/errors';
+import { isValidTimeZone, localizeTimestamps } from '../utils/time';
 
 export const feedsRouter = Router();
 
@@ -19,6 +20,11 @@ export const feedsRouter = Router();
  *         symbol:
  *           type: string
  *           example: "BTCUSD"
+ *         kind:
+ *           type: string
+ *           enum: [price, rate, index, por]
+ *           description: What the answer represents; rates may be negative, PoR reports reserve amounts
+ *           example: "price"
  *         contractAddress:
  *           type: string
  *           example: "0xa9Afa74dDAC812B86Eeaa60A035c6592470F4A48"
@@ -499,4 +505,117 @@ feedsRouter.get('/:symbol/rounds/:roundId', async (req, res) => {
       timestamp: new Date().toISOString()
     });
   }
-});
\ No newline at end of file
+});
+
+/**
+ * @swagger
+ * /feeds/{symbol}/volatility:
+ *   get:
+ *     summary: Get realized volatility for a feed
+ *     description: Returns annualized realized volatility computed from log returns over the trailing rounds
+ *     tags: [Feeds]
+ *     parameters:
+ *       - in: path
+ *         name: symbol
+ *         required: true
+ *         schema:
+ *           type: string
+ *         description: Feed symbol
+ *         example: BTCUSD
+ *       - in: query
+ *         name: window
+ *         schema:
+ *           type: integer
+ *           minimum: 3
+ *           maximum: 500
+ *           default: 50
+ *         description: Number of trailing rounds to include
+ *       - in: query
+ *         name: tz
+ *         required: false
+ *         schema:
+ *           type: string
+ *           example: "America/New_York"
+ *         description: IANA time zone to express timestamps in (default UTC)
+ *     responses:
+ *       200:
+ *         description: Volatility computed successfully
+ *         content:
+ *           application/json:
+ *             schema:
+ *               type: object
+ *               properties:
+ *                 success:
+ *                   type: boolean
+ *                   example: true
+ *                 data:
+ *                   type: object
+ *                   properties:
+ *                     symbol:
+ *                       type: string
+ *                       example: "BTCUSD"
+ *                     window:
+ *                       type: number
+ *                       example: 50
+ *                     observations:
+ *                       type: number
+ *                       example: 49
+ *                     fromTimestamp:
+ *                       type: string
+ *                       format: date-time
+ *                     toTimestamp:
+ *                       type: string
+ *                       format: date-time
+ *                     meanIntervalSeconds:
+ *                       type: number
+ *                       example: 1843.2
+ *                     stdDev:
+ *                       type: number
+ *                       description: Standard deviation of per-round log returns
+ *                       example: 0.0021
+ *                     realizedVolatility:
+ *                       type: number
+ *                       description: Annualized realized volatility
+ *                       example: 0.4897
+ *                 timestamp:
+ *                   type: string
+ *                   format: date-time
+ *       400:
+ *         description: Invalid window
+ *       404:
+ *         description: Feed not found
+ *       422:
+ *         description: Not enough round history
+ */
+feedsRouter.get('/:symbol/volatility', asyncHandler(async (req: Request, res: Response, next: NextFunction) => {
+  const priceService: PriceService = (req as any).priceService;
+  const { symbol } = req.params;
+
+  if (!symbol) {
+    throw new FeedNotFoundError('undefined');
+  }
+
+  const window = req.query.window === undefined ? 50 : Number(req.query.window);
+  if (!Number.isInteger(window) || window < 3 || window > 500) {
+    throw new ValidationError('window', 'must be an integer between 3 and 500');
+  }
+
+  const timeZone = req.query.tz;
+  if (timeZone !== undefined && !isValidTimeZone(timeZone)) {
+    throw new ValidationError('tz', `unknown time zone '${timeZone}'`);
+  }
+
+  const volatility = await priceService.getVolatility(symbol, window);
+
+  if (!volatility) {
+    throw new FeedNotFoundError(symbol);
+  }
+
+  const response: ApiResponse<VolatilityData> = {
+    success: true,
+    data: localizeTimestamps(volatility, timeZone),
+    timestamp: new Date().toISOString()
+  };
+
+  res.json(response);
+}));
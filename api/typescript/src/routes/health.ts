@@ -1,6 +1,8 @@
 import { Router } from 'express';
 import { PriceService } from '../services/PriceService';
-import { HealthCheck, ApiResponse } from '../types';
+import { HealthCheck, ApiResponse, AvailabilityReport, UpdateFrequencyReport, FeedHealthReport } from '../types';
+import { ValidationError } from '../utils/errors';
+import { isValidTimeZone, localizeTimestamps } from '../utils/time';
 
 export const healthRouter = Router();
 
@@ -42,6 +44,14 @@ export const healthRouter = Router();
  *                         connected:
  *                           type: boolean
  *                           example: true
+ *                         chain:
+ *                           type: string
+ *                           description: Registry name selected with CHAIN
+ *                           example: "avalanche"
+ *                         testnet:
+ *                           type: boolean
+ *                           description: True when serving a testnet such as Fuji
+ *                           example: false
  *                         chainId:
  *                           type: string
  *                           example: "43114"
@@ -123,4 +133,348 @@ healthRouter.get('/', async (req, res) => {
       timestamp: new Date().toISOString()
     });
   }
-});
\ No newline at end of file
+});
+
+/**
+ * @swagger
+ * /health/availability:
+ *   get:
+ *     summary: Feed and RPC availability
+ *     description: |
+ *       Rolling record of successful and failed reads per feed and per RPC endpoint.
+ *       Entries are sorted least available first; failureShare attributes each entry's
+ *       portion of all failures in the window.
+ *     tags: [Health]
+ *     parameters:
+ *       - in: query
+ *         name: tz
+ *         required: false
+ *         schema:
+ *           type: string
+ *           example: "America/New_York"
+ *         description: IANA time zone to express timestamps in (default UTC)
+ *     responses:
+ *       200:
+ *         description: Availability report
+ *         content:
+ *           application/json:
+ *             schema:
+ *               type: object
+ *               properties:
+ *                 success:
+ *                   type: boolean
+ *                   example: true
+ *                 data:
+ *                   type: object
+ *                   properties:
+ *                     window:
+ *                       type: number
+ *                       description: Samples retained per feed or RPC
+ *                       example: 288
+ *                     rpcs:
+ *                       type: array
+ *                       items:
+ *                         $ref: '#/components/schemas/AvailabilityRecord'
+ *                     feeds:
+ *                       type: array
+ *                       items:
+ *                         $ref: '#/components/schemas/AvailabilityRecord'
+ *                 timestamp:
+ *                   type: string
+ *                   format: date-time
+ * components:
+ *   schemas:
+ *     AvailabilityRecord:
+ *       type: object
+ *       properties:
+ *         key:
+ *           type: string
+ *           description: Feed symbol or RPC URL
+ *           example: "BTCUSD"
+ *         successes:
+ *           type: number
+ *           example: 286
+ *         failures:
+ *           type: number
+ *           example: 2
+ *         availability:
+ *           type: number
+ *           example: 0.993
+ *         failureShare:
+ *           type: number
+ *           example: 0.25
+ *         since:
+ *           type: string
+ *           format: date-time
+ *         lastSuccess:
+ *           type: string
+ *           format: date-time
+ *           nullable: true
+ *         lastFailure:
+ *           type: string
+ *           format: date-time
+ *           nullable: true
+ */
+healthRouter.get('/availability', (req, res, next) => {
+  const priceService: PriceService = (req as any).priceService;
+
+  const timeZone = req.query.tz;
+  if (timeZone !== undefined && !isValidTimeZone(timeZone)) {
+    return next(new ValidationError('tz', `unknown time zone '${timeZone}'`));
+  }
+
+  const response: ApiResponse<AvailabilityReport> = {
+    success: true,
+    data: localizeTimestamps(priceService.getAvailability(), timeZone),
+    timestamp: new Date().toISOString()
+  };
+
+  res.json(response);
+});
+
+/**
+ * @swagger
+ * /health/update-frequency:
+ *   get:
+ *     summary: Observed update frequency versus heartbeat
+ *     description: |
+ *       Distinct on-chain updates observed per feed over trailing 1h, 24h and 7d windows,
+ *       compared against the documented heartbeat. A feed is slow when it has gone longer
+ *       than heartbeat × tolerance without an update, or a window holds fewer updates than
+ *       that cadence guarantees. Slow feeds are listed first.
+ *     tags: [Health]
+ *     parameters:
+ *       - in: query
+ *         name: status
+ *         required: false
+ *         schema:
+ *           type: string
+ *           enum: [ok, slow, insufficient-data]
+ *         description: Only return feeds with this status
+ *       - in: query
+ *         name: tz
+ *         required: false
+ *         schema:
+ *           type: string
+ *           example: "America/New_York"
+ *         description: IANA time zone to express timestamps in (default UTC)
+ *     responses:
+ *       200:
+ *         description: Update frequency report
+ *         content:
+ *           application/json:
+ *             schema:
+ *               type: object
+ *               properties:
+ *                 success:
+ *                   type: boolean
+ *                   example: true
+ *                 data:
+ *                   type: object
+ *                   properties:
+ *                     tolerance:
+ *                       type: number
+ *                       example: 1.5
+ *                     windows:
+ *                       type: array
+ *                       items:
+ *                         type: string
+ *                       example: ["1h", "24h", "7d"]
+ *                     feeds:
+ *                       type: array
+ *                       items:
+ *                         $ref: '#/components/schemas/UpdateFrequencyRecord'
+ *                 timestamp:
+ *                   type: string
+ *                   format: date-time
+ * components:
+ *   schemas:
+ *     UpdateFrequencyRecord:
+ *       type: object
+ *       properties:
+ *         symbol:
+ *           type: string
+ *           example: "BTCUSD"
+ *         heartbeat:
+ *           type: number
+ *           example: 86400
+ *         deviationThreshold:
+ *           type: number
+ *           example: 0.1
+ *         lastUpdate:
+ *           type: string
+ *           format: date-time
+ *         secondsSinceUpdate:
+ *           type: number
+ *           example: 1200
+ *         status:
+ *           type: string
+ *           enum: [ok, slow, insufficient-data]
+ *         reasons:
+ *           type: array
+ *           items:
+ *             type: string
+ *         windows:
+ *           type: array
+ *           items:
+ *             type: object
+ *             properties:
+ *               window:
+ *                 type: string
+ *                 example: "24h"
+ *               coverage:
+ *                 type: number
+ *                 example: 86400
+ *               updates:
+ *                 type: number
+ *                 example: 31
+ *               expectedUpdates:
+ *                 type: number
+ *                 example: 1
+ *               meanInterval:
+ *                 type: number
+ *                 nullable: true
+ *               maxGap:
+ *                 type: number
+ *                 nullable: true
+ */
+healthRouter.get('/update-frequency', (req, res, next) => {
+  const priceService: PriceService = (req as any).priceService;
+
+  const timeZone = req.query.tz;
+  if (timeZone !== undefined && !isValidTimeZone(timeZone)) {
+    return next(new ValidationError('tz', `unknown time zone '${timeZone}'`));
+  }
+
+  const status = req.query.status;
+  if (status !== undefined && !['ok', 'slow', 'insufficient-data'].includes(status as string)) {
+    return next(new ValidationError('status', 'must be one of ok, slow, insufficient-data'));
+  }
+
+  const report = priceService.getUpdateFrequency();
+  if (status !== undefined) {
+    report.feeds = report.feeds.filter(record => record.status === status);
+  }
+
+  const response: ApiResponse<UpdateFrequencyReport> = {
+    success: true,
+    data: localizeTimestamps(report, timeZone),
+    timestamp: new Date().toISOString()
+  };
+
+  res.json(response);
+});
+/**
+ * @swagger
+ * /health/feeds:
+ *   get:
+ *     summary: Composite health grade per feed
+ *     description: |
+ *       Score from 0 to 100 and letter grade per feed, recomputed every refresh from
+ *       time-weighted staleness, update regularity, decode failures and deviation
+ *       anomalies. Components that cannot be measured yet are null and left out of
+ *       the score. The same score and grade are attached to each price as health.
+ *       Worst feeds are listed first.
+ *     tags: [Health]
+ *     parameters:
+ *       - in: query
+ *         name: grade
+ *         required: false
+ *         schema:
+ *           type: string
+ *           enum: [A, B, C, D, F]
+ *         description: Only return feeds with this grade
+ *       - in: query
+ *         name: tz
+ *         required: false
+ *         schema:
+ *           type: string
+ *           example: "America/New_York"
+ *         description: IANA time zone to express timestamps in (default UTC)
+ *     responses:
+ *       200:
+ *         description: Feed health report
+ *         content:
+ *           application/json:
+ *             schema:
+ *               type: object
+ *               properties:
+ *                 success:
+ *                   type: boolean
+ *                   example: true
+ *                 data:
+ *                   type: object
+ *                   properties:
+ *                     weights:
+ *                       type: object
+ *                       example: { staleness: 0.35, regularity: 0.25, decoding: 0.25, deviation: 0.15 }
+ *                     feeds:
+ *                       type: array
+ *                       items:
+ *                         $ref: '#/components/schemas/FeedHealthRecord'
+ *                 timestamp:
+ *                   type: string
+ *                   format: date-time
+ * components:
+ *   schemas:
+ *     FeedHealthRecord:
+ *       type: object
+ *       properties:
+ *         symbol:
+ *           type: string
+ *           example: "BTCUSD"
+ *         score:
+ *           type: number
+ *           example: 96.4
+ *         grade:
+ *           type: string
+ *           enum: [A, B, C, D, F]
+ *         components:
+ *           type: object
+ *           properties:
+ *             staleness:
+ *               type: number
+ *               nullable: true
+ *             regularity:
+ *               type: number
+ *               nullable: true
+ *             decoding:
+ *               type: number
+ *               nullable: true
+ *             deviation:
+ *               type: number
+ *               nullable: true
+ *         anomalies:
+ *           type: number
+ *           description: Recent updates that moved far beyond the deviation threshold
+ *           example: 0
+ *         gradedAt:
+ *           type: string
+ *           format: date-time
+ */
+healthRouter.get('/feeds', (req, res, next) => {
+  const priceService: PriceService = (req as any).priceService;
+
+  const timeZone = req.query.tz;
+  if (timeZone !== undefined && !isValidTimeZone(timeZone)) {
+    return next(new ValidationError('tz', `unknown time zone '${timeZone}'`));
+  }
+
+  const grade = req.query.grade;
+  if (grade !== undefined && !['A', 'B', 'C', 'D', 'F'].includes(grade as string)) {
+    return next(new ValidationError('grade', 'must be one of A, B, C, D, F'));
+  }
+
+  const report = priceService.getFeedHealth();
+  if (grade !== undefined) {
+    report.feeds = report.feeds.filter(record => record.grade === grade);
+  }
+
+  const response: ApiResponse<FeedHealthReport> = {
+    success: true,
+    data: localizeTimestamps(report, timeZone),
+    timestamp: new Date().toISOString()
+  };
+
+  res.json(response);
+});
@@ -1,6 +1,8 @@
 import { Router } from 'express';
 import { PriceService } from '../services/PriceService';
 import { ApiResponse, PriceData, PriceRefreshResponse } from '../types';
+import { ValidationError } from '../utils/errors';
+import { isValidTimeZone, localizeTimestamps } from '../utils/time';
 
 export const pricesRouter = Router();
 
@@ -14,9 +16,23 @@ export const pricesRouter = Router();
  *         symbol:
  *           type: string
  *           example: "BTCUSD"
+ *         source:
+ *           type: string
+ *           enum: [chainlink, erc4626, lst, erc2362]
+ *           description: Chainlink feed, built-in exchange-rate read or ERC-2362 oracle
+ *           example: "chainlink"
+ *         kind:
+ *           type: string
+ *           enum: [price, rate, index, por]
+ *           description: What the answer represents; rates may be negative, PoR reports reserve amounts
+ *           example: "price"
  *         price:
  *           type: number
  *           example: 117402.79073738
+ *         exactPrice:
+ *           type: string
+ *           description: Full-precision decimal rendering of the answer
+ *           example: "117402.79073738"
  *         decimals:
  *           type: number
  *           example: 8
@@ -27,6 +43,15 @@ export const pricesRouter = Router();
  *           type: string
  *           format: date-time
  *           example: "2025-07-21T00:59:55.000Z"
+ *         blockTimestamp:
+ *           type: string
+ *           format: date-time
+ *           description: Timestamp of the block the answer was sampled at
+ *           example: "2025-07-21T01:00:12.000Z"
+ *         ageAtBlock:
+ *           type: number
+ *           description: Seconds between updatedAt and the sampled block's timestamp
+ *           example: 17
  *         proxyAddress:
  *           type: string
  *           example: "0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743"
@@ -45,6 +70,17 @@ export const pricesRouter = Router();
  *             answeredInRound:
  *               type: string
  *               example: "18446744073709562301"
+ *         health:
+ *           type: object
+ *           description: Composite feed health at the last refresh; gate on grade before trusting the price
+ *           properties:
+ *             score:
+ *               type: number
+ *               example: 96.4
+ *             grade:
+ *               type: string
+ *               enum: [A, B, C, D, F]
+ *               example: "A"
  */
 
 /**
@@ -297,6 +333,14 @@ pricesRouter.post('/refresh', async (req, res) => {
  *     summary: Get all Proof of Reserve data
  *     description: Returns current reserve amounts for all Proof of Reserve feeds
  *     tags: [Prices]
+ *     parameters:
+ *       - in: query
+ *         name: tz
+ *         required: false
+ *         schema:
+ *           type: string
+ *           example: "America/New_York"
+ *         description: IANA time zone to express timestamps in (default UTC)
  *     responses:
  *       200:
  *         description: Proof of Reserve data retrieved successfully
@@ -359,14 +403,19 @@ pricesRouter.post('/refresh', async (req, res) => {
  *                   type: string
  *                   format: date-time
  */
-pricesRouter.get('/reserves', async (req, res) => {
+pricesRouter.get('/reserves', async (req, res, next) => {
+  const timeZone = req.query.tz;
+  if (timeZone !== undefined && !isValidTimeZone(timeZone)) {
+    return next(new ValidationError('tz', `unknown time zone '${timeZone}'`));
+  }
+
   try {
     const priceService: PriceService = (req as any).priceService;
     const reservesSnapshot = await priceService.getReservesSnapshot();
 
     const response: ApiResponse<any> = {
       success: true,
-      data: reservesSnapshot,
+      data: localizeTimestamps(reservesSnapshot, timeZone),
       timestamp: new Date().toISOString(),
       blockNumber: reservesSnapshot.blockNumber
     };
@@ -400,6 +449,13 @@ pricesRouter.get('/reserves', async (req, res) => {
  *           type: string
  *         description: Asset symbol (e.g., BTCB, BTC.b, USDC.e)
  *         example: BTCB
+ *       - in: query
+ *         name: tz
+ *         required: false
+ *         schema:
+ *           type: string
+ *           example: "America/New_York"
+ *         description: IANA time zone to express timestamps in (default UTC)
  *     responses:
  *       200:
  *         description: Proof of Reserve data retrieved successfully
@@ -444,7 +500,12 @@ pricesRouter.get('/reserves', async (req, res) => {
  *       404:
  *         description: Reserve data not found
  */
-pricesRouter.get('/reserves/:symbol', async (req, res) => {
+pricesRouter.get('/reserves/:symbol', async (req, res, next) => {
+  const timeZone = req.query.tz;
+  if (timeZone !== undefined && !isValidTimeZone(timeZone)) {
+    return next(new ValidationError('tz', `unknown time zone '${timeZone}'`));
+  }
+
   try {
     const priceService: PriceService = (req as any).priceService;
     const { symbol } = req.params;
@@ -463,7 +524,7 @@ pricesRouter.get('/reserves/:symbol', async (req, res) => {
 
     const response: ApiResponse<any> = {
       success: true,
-      data: reserve,
+      data: localizeTimestamps(reserve, timeZone),
       timestamp: new Date().toISOString()
     };
 
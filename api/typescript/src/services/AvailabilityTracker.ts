@@ -0,0 +1,117 @@
+/**
+ * Availability Tracking
+ * Keeps a rolling record of successful and failed reads per feed and per RPC
+ * endpoint, so gaps can be attributed to the provider or the feed causing them
+ */
+
+import fs from 'fs';
+import { AvailabilityRecord, AvailabilityReport } from '../types';
+
+interface Sample {
+  ok: boolean;
+  at: number; // unix milliseconds
+}
+
+type SampleStore = Map<string, Sample[]>;
+
+// 24 hours of 5-minute refreshes
+export const DEFAULT_AVAILABILITY_WINDOW = 288;
+
+// RPC endpoints are reported by origin only: API keys often live in the URL path or query
+export function rpcKey(url: string): string {
+  try {
+    return new URL(url).origin;
+  } catch {
+    return 'invalid-url';
+  }
+}
+
+export class AvailabilityTracker {
+  private feeds: SampleStore = new Map();
+  private rpcs: SampleStore = new Map();
+
+  constructor(
+    private readonly windowSize: number = DEFAULT_AVAILABILITY_WINDOW,
+    private readonly persistPath: string | undefined = process.env.AVAILABILITY_FILE
+  ) {
+    this.load();
+  }
+
+  public recordFeed(symbol: string, ok: boolean, at: number = Date.now()): void {
+    this.record(this.feeds, symbol, ok, at);
+  }
+
+  public recordRpc(url: string, ok: boolean, at: number = Date.now()): void {
+    this.record(this.rpcs, rpcKey(url), ok, at);
+  }
+
+  public getReport(): AvailabilityReport {
+    return {
+      window: this.windowSize,
+      rpcs: this.summarize(this.rpcs),
+      feeds: this.summarize(this.feeds)
+    };
+  }
+
+  // Write the current window to disk so availability survives restarts
+  public save(): void {
+    if (!this.persistPath) return;
+
+    const snapshot = {
+      feeds: Object.fromEntries(this.feeds),
+      rpcs: Object.fromEntries(this.rpcs)
+    };
+    try {
+      fs.writeFileSync(this.persistPath, JSON.stringify(snapshot));
+    } catch (error) {
+      console.warn(`⚠️ Could not persist availability to ${this.persistPath}:`, error);
+    }
+  }
+
+  private load(): void {
+    if (!this.persistPath || !fs.existsSync(this.persistPath)) return;
+
+    try {
+      const snapshot = JSON.parse(fs.readFileSync(this.persistPath, 'utf8'));
+      this.feeds = new Map(Object.entries(snapshot.feeds ?? {}));
+      this.rpcs = new Map(Object.entries(snapshot.rpcs ?? {}));
+    } catch (error) {
+      console.warn(`⚠️ Ignoring unreadable availability file ${this.persistPath}:`, error);
+    }
+  }
+
+  private record(store: SampleStore, key: string, ok: boolean, at: number): void {
+    const samples = store.get(key) ?? [];
+    samples.push({ ok, at });
+    if (samples.length > this.windowSize) {
+      samples.splice(0, samples.length - this.windowSize);
+    }
+    store.set(key, samples);
+  }
+
+  // Least available first; failureShare attributes each key's portion of all recorded failures
+  private summarize(store: SampleStore): AvailabilityRecord[] {
+    const totalFailures = Array.from(store.values())
+      .reduce((sum, samples) => sum + samples.filter(s => !s.ok).length, 0);
+
+    const records = Array.from(store.entries()).map(([key, samples]) => {
+      const successes = samples.filter(s => s.ok).length;
+      const failures = samples.length - successes;
+      const lastSuccess = [...samples].reverse().find(s => s.ok);
+      const lastFailure = [...samples].reverse().find(s => !s.ok);
+
+      return {
+        key,
+        successes,
+        failures,
+        availability: samples.length > 0 ? successes / samples.length : 1,
+        failureShare: totalFailures > 0 ? failures / totalFailures : 0,
+        since: new Date(samples[0]?.at ?? Date.now()).toISOString(),
+        lastSuccess: lastSuccess ? new Date(lastSuccess.at).toISOString() : null,
+        lastFailure: lastFailure ? new Date(lastFailure.at).toISOString() : null
+      };
+    });
+
+    return records.sort((a, b) => a.availability - b.availability || b.failures - a.failures);
+  }
+}
@@ -0,0 +1,176 @@
+/**
+ * Feed Health Grading
+ * Folds time-weighted staleness, update regularity, decode failures and
+ * deviation anomalies into one score and letter grade per feed, refreshed
+ * every price cycle, so consumers can gate on a single field before trusting
+ * a price
+ */
+
+import fs from 'fs';
+import { AvailabilityReport, FeedHealthComponents, FeedHealthGrade, FeedHealthRecord, FeedHealthReport, UpdateFrequencyReport } from '../types';
+
+interface FeedState {
+  heartbeat: number | null; // seconds; null for live reads with no update promise
+  deviationThreshold: number; // percent
+  lastAnswer: number | null;
+  lastUpdatedAt: number | null; // unix seconds of the answer last seen
+  staleness: number | null; // time-weighted mean of age / heartbeat
+  sampledAt: number | null; // unix seconds staleness was last advanced
+  moves: boolean[]; // recent answer changes, true when the move was anomalous
+}
+
+export const FEED_HEALTH_WEIGHTS: Readonly<Record<keyof FeedHealthComponents, number>> = {
+  staleness: 0.35,
+  regularity: 0.25,
+  decoding: 0.25,
+  deviation: 0.15
+};
+
+// Minimum score for each grade, best first
+export const FEED_HEALTH_GRADES: ReadonlyArray<[FeedHealthGrade, number]> = [
+  ['A', 90],
+  ['B', 75],
+  ['C', 50],
+  ['D', 25],
+  ['F', 0]
+];
+
+// Older staleness samples lose half their weight every hour
+export const DEFAULT_STALENESS_HALF_LIFE = 3600;
+
+// A single move this many times the deviation threshold is anomalous; never below MIN_ANOMALY_MOVE percent
+export const DEFAULT_ANOMALY_FACTOR = 10;
+const MIN_ANOMALY_MOVE = 1;
+
+const MOVE_WINDOW = 50;
+
+export class FeedHealthTracker {
+  private feeds: Map<string, FeedState> = new Map();
+  private grades: Map<string, FeedHealthRecord> = new Map();
+
+  constructor(
+    private readonly halfLife: number = parseFloat(process.env.FEED_HEALTH_HALF_LIFE ?? '') || DEFAULT_STALENESS_HALF_LIFE,
+    private readonly anomalyFactor: number = parseFloat(process.env.DEVIATION_ANOMALY_FACTOR ?? '') || DEFAULT_ANOMALY_FACTOR,
+    private readonly persistPath: string | undefined = process.env.FEED_HEALTH_FILE
+  ) {
+    this.load();
+  }
+
+  // Record a decoded answer; only a new updatedAt counts as a move
+  public observe(symbol: string, heartbeat: number | null, deviationThreshold: number, answer: number, updatedAt: number): void {
+    const feed = this.feeds.get(symbol) ?? {
+      heartbeat, deviationThreshold, lastAnswer: null, lastUpdatedAt: null, staleness: null, sampledAt: null, moves: []
+    };
+    feed.heartbeat = heartbeat;
+    feed.deviationThreshold = deviationThreshold;
+
+    if (feed.lastUpdatedAt === null || updatedAt > feed.lastUpdatedAt) {
+      if (feed.lastAnswer !== null && feed.lastAnswer !== 0) {
+        const movePercent = Math.abs(answer - feed.lastAnswer) / Math.abs(feed.lastAnswer) * 100;
+        feed.moves.push(movePercent > Math.max(deviationThreshold * this.anomalyFactor, MIN_ANOMALY_MOVE));
+        if (feed.moves.length > MOVE_WINDOW) feed.moves.shift();
+      }
+      feed.lastAnswer = answer;
+      feed.lastUpdatedAt = updatedAt;
+    }
+
+    this.feeds.set(symbol, feed);
+  }
+
+  // Re-grade every known feed once per cycle, after all answers have been observed
+  public refresh(frequency: UpdateFrequencyReport, availability: AvailabilityReport, now: number = Date.now() / 1000): void {
+    const regularity = new Map(frequency.feeds.map(record => {
+      const gaps = record.windows.map(w => w.maxGap).filter((gap): gap is number => gap !== null);
+      const limit = record.heartbeat * frequency.tolerance;
+      return [record.symbol, gaps.length > 0 ? Math.min(1, limit / Math.max(...gaps)) : null] as const;
+    }));
+    const decoding = new Map(availability.feeds.map(record => [record.key, record.availability]));
+
+    const symbols = new Set([...this.feeds.keys(), ...decoding.keys()]);
+    symbols.forEach(symbol => {
+      const feed = this.feeds.get(symbol);
+      const components: FeedHealthComponents = {
+        staleness: feed ? this.advanceStaleness(feed, now) : null,
+        regularity: regularity.get(symbol) ?? null,
+        decoding: decoding.get(symbol) ?? null,
+        deviation: feed && feed.moves.length > 0 ? 1 - feed.moves.filter(Boolean).length / feed.moves.length : null
+      };
+      const score = FeedHealthTracker.score(components);
+
+      this.grades.set(symbol, {
+        symbol,
+        score,
+        grade: FeedHealthTracker.grade(score),
+        components,
+        anomalies: feed ? feed.moves.filter(Boolean).length : 0,
+        gradedAt: new Date(now * 1000).toISOString()
+      });
+    });
+  }
+
+  public get(symbol: string): FeedHealthRecord | undefined {
+    return this.grades.get(symbol);
+  }
+
+  public getReport(): FeedHealthReport {
+    return {
+      weights: { ...FEED_HEALTH_WEIGHTS },
+      feeds: Array.from(this.grades.values()).sort((a, b) => a.score - b.score)
+    };
+  }
+
+  // Weighted mean of the components that could be measured, as 0-100
+  static score(components: FeedHealthComponents): number {
+    let total = 0;
+    let weight = 0;
+    (Object.keys(FEED_HEALTH_WEIGHTS) as Array<keyof FeedHealthComponents>).forEach(key => {
+      const value = components[key];
+      if (value === null) return;
+      total += value * FEED_HEALTH_WEIGHTS[key];
+      weight += FEED_HEALTH_WEIGHTS[key];
+    });
+    return weight > 0 ? Math.round(total / weight * 1000) / 10 : 0;
+  }
+
+  static grade(score: number): FeedHealthGrade {
+    return FEED_HEALTH_GRADES.find(([, min]) => score >= min)?.[0] ?? 'F';
+  }
+
+  // Write feed state to disk so staleness history and recent moves survive restarts
+  public save(): void {
+    if (!this.persistPath) return;
+
+    try {
+      fs.writeFileSync(this.persistPath, JSON.stringify(Object.fromEntries(this.feeds)));
+    } catch (error) {
+      console.warn(`⚠️ Could not persist feed health to ${this.persistPath}:`, error);
+    }
+  }
+
+  private load(): void {
+    if (!this.persistPath || !fs.existsSync(this.persistPath)) return;
+
+    try {
+      this.feeds = new Map(Object.entries(JSON.parse(fs.readFileSync(this.persistPath, 'utf8'))));
+    } catch (error) {
+      console.warn(`⚠️ Ignoring unreadable feed health file ${this.persistPath}:`, error);
+    }
+  }
+
+  // Age within one heartbeat scores 1, falling to 0 at two heartbeats; a sample's
+  // weight grows with the time since the last one, so brief blips fade quickly
+  private advanceStaleness(feed: FeedState, now: number): number | null {
+    if (!feed.heartbeat || feed.lastUpdatedAt === null) return null;
+
+    const ratio = Math.max(0, now - feed.lastUpdatedAt) / feed.heartbeat;
+    if (feed.staleness === null || feed.sampledAt === null) {
+      feed.staleness = ratio;
+    } else if (now > feed.sampledAt) {
+      const alpha = 1 - Math.pow(2, -(now - feed.sampledAt) / this.halfLife);
+      feed.staleness += alpha * (ratio - feed.staleness);
+    }
+    feed.sampledAt = Math.max(feed.sampledAt ?? now, now);
+
+    return Math.min(1, Math.max(0, 2 - feed.staleness));
+  }
+}
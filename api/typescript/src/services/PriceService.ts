@@ -2,110 +2,92 @@ import { ethers } from 'ethers';
 import fs from 'fs';
 import csv from 'csv-parser';
 import path from 'path';
-import { FeedMetadata, PriceData, RoundData, FeedDescription, FeedVersion, FeedDecimals, ProofOfReserveData, ReservesSnapshot } from '../types';
+import { FeedKind, FeedMetadata, PriceData, RoundData, FeedDescription, FeedVersion, FeedDecimals, ProofOfReserveData, ReservesSnapshot, VolatilityData, AvailabilityReport, UpdateFrequencyReport, FeedHealthReport, Alert } from '../types';
+import { computeRealizedVolatility } from '../utils/volatility';
+import { FeedsLoadError, InsufficientHistoryError, ValidationError } from '../utils/errors';
+import { decodeAggregate3Result, ReturnDataView } from '../utils/multicallCodec';
+import { FaultInjector } from '../utils/faultInjection';
+import { observeUpdateInterval, setFeedHealthScore, setRealizedVolatility } from '../utils/metrics';
+import { AvailabilityTracker } from './AvailabilityTracker';
+import { UpdateFrequencyTracker } from './UpdateFrequencyTracker';
+import { FeedHealthTracker } from './FeedHealthTracker';
+import { SilenceManager } from './SilenceManager';
+import { classifyFeed, validateAnswer, formatAnswer, FEED_KIND_RULES } from '../utils/feedKind';
+import { AggregatorV3Interface, RoundDataResult, Multicall3, resolveChain, ExchangeRateFeed, ExchangeRateFeedType, DEFAULT_METHOD, RATE_METHODS, encodeRateCall, GenericOracleFeed, encodeValueFor, resolveOracleId, ERC2362_STATUS_OK } from '../contracts';
+
+// getBlockNumber and getCurrentBlockTimestamp lead every refresh batch, ahead of the feed reads
+const HEADER_CALLS = 2;
+
+// A feed read that reverted inside aggregate3, named by its revert reason when it has one
+function callFailure(data: ReturnDataView): Error {
+  const reason = data.revertReason();
+  return new Error(reason === null ? 'Call reverted' : `Call reverted: ${reason}`);
+}
+
+// GAS_LIMIT is shared with the CLI; MULTICALL_GAS_LIMIT is the old API-only name, still read with a warning
+function gasLimitFromEnv(): bigint | undefined {
+  if (process.env.MULTICALL_GAS_LIMIT && !process.env.GAS_LIMIT) {
+    console.warn('⚠️ MULTICALL_GAS_LIMIT is deprecated, set GAS_LIMIT instead');
+  }
+  const name = process.env.GAS_LIMIT ? 'GAS_LIMIT' : 'MULTICALL_GAS_LIMIT';
+  const gasLimit = process.env[name];
+  if (!gasLimit) {
+    return undefined;
+  }
+  if (!/^\d+$/.test(gasLimit) || BigInt(gasLimit) === 0n) {
+    throw new Error(`${name} must be a positive integer, got "${gasLimit}"`);
+  }
+  return BigInt(gasLimit);
+}
 
 export class PriceService {
   private provider: ethers.JsonRpcProvider;
-  private multicall: ethers.Contract;
+  private multicall: Multicall3;
   private feeds: FeedMetadata[] = [];
+  private exchangeRateFeeds: ExchangeRateFeed[] = [];
+  private oracleFeeds: GenericOracleFeed[] = [];
   private prices: Map<string, PriceData> = new Map();
   private lastUpdate: Date = new Date(0);
   private isRefreshing = false;
-
-  private readonly MULTICALL3_ADDRESS = '0xcA11bde05977b3631167028862bE2a173976CA11';
-  private readonly AVALANCHE_RPC = 'https://api.avax.network/ext/bc/C/rpc';
+  private refreshCalldata: string | null = null;
+  private availability = new AvailabilityTracker();
+  private updateFrequency = new UpdateFrequencyTracker();
+  private feedHealth = new FeedHealthTracker();
+  private slowFeeds: Set<string> = new Set();
+  private faults = FaultInjector.fromEnv();
+  private silences = new SilenceManager(this.silencesPath());
+
+  private readonly network = resolveChain();
+  private readonly MULTICALL3_ADDRESS = this.network.multicall3;
+  private readonly AVALANCHE_RPC = process.env.RPC_URL || this.network.rpcUrl;
+  private readonly CHAIN = this.network.name;
   
-  private readonly MULTICALL3_ABI = [
-    {
-      "inputs": [
-        {
-          "components": [
-            { "internalType": "address", "name": "target", "type": "address" },
-            { "internalType": "bytes", "name": "callData", "type": "bytes" }
-          ],
-          "internalType": "struct Multicall3.Call[]",
-          "name": "calls",
-          "type": "tuple[]"
-        }
-      ],
-      "name": "aggregate",
-      "outputs": [
-        { "internalType": "uint256", "name": "blockNumber", "type": "uint256" },
-        { "internalType": "bytes[]", "name": "returnData", "type": "bytes[]" }
-      ],
-      "stateMutability": "payable",
-      "type": "function"
-    }
-  ];
-
-  private readonly CHAINLINK_ABI = [
-    {
-      "inputs": [],
-      "name": "latestRoundData",
-      "outputs": [
-        { "internalType": "uint80", "name": "roundId", "type": "uint80" },
-        { "internalType": "int256", "name": "answer", "type": "int256" },
-        { "internalType": "uint256", "name": "startedAt", "type": "uint256" },
-        { "internalType": "uint256", "name": "updatedAt", "type": "uint256" },
-        { "internalType": "uint80", "name": "answeredInRound", "type": "uint80" }
-      ],
-      "stateMutability": "view",
-      "type": "function"
-    },
-    {
-      "inputs": [
-        { "internalType": "uint80", "name": "_roundId", "type": "uint80" }
-      ],
-      "name": "getRoundData",
-      "outputs": [
-        { "internalType": "uint80", "name": "roundId", "type": "uint80" },
-        { "internalType": "int256", "name": "answer", "type": "int256" },
-        { "internalType": "uint256", "name": "startedAt", "type": "uint256" },
-        { "internalType": "uint256", "name": "updatedAt", "type": "uint256" },
-        { "internalType": "uint80", "name": "answeredInRound", "type": "uint80" }
-      ],
-      "stateMutability": "view",
-      "type": "function"
-    },
-    {
-      "inputs": [],
-      "name": "decimals",
-      "outputs": [
-        { "internalType": "uint8", "name": "", "type": "uint8" }
-      ],
-      "stateMutability": "view",
-      "type": "function"
-    },
-    {
-      "inputs": [],
-      "name": "description",
-      "outputs": [
-        { "internalType": "string", "name": "", "type": "string" }
-      ],
-      "stateMutability": "view",
-      "type": "function"
-    },
-    {
-      "inputs": [],
-      "name": "version",
-      "outputs": [
-        { "internalType": "uint256", "name": "", "type": "uint256" }
-      ],
-      "stateMutability": "view",
-      "type": "function"
-    }
-  ];
-
   constructor() {
     this.provider = new ethers.JsonRpcProvider(this.AVALANCHE_RPC);
-    this.multicall = new ethers.Contract(this.MULTICALL3_ADDRESS, this.MULTICALL3_ABI, this.provider);
+    this.multicall = new Multicall3(
+      this.provider,
+      this.MULTICALL3_ADDRESS,
+      gasLimitFromEnv()
+    );
+    // Simulated state would leak into served prices, alerts and history
+    if (process.env.STATE_OVERRIDE) {
+      console.warn('⚠️ STATE_OVERRIDE is ignored: state overrides are CLI-only');
+    }
     this.loadFeeds();
   }
 
   private async loadFeeds(): Promise<void> {
-    const csvPath = process.env.NODE_ENV === 'production' 
-      ? path.join('/app', 'avalanche_chainlink_feeds.csv')
-      : path.join(__dirname, '../../../avalanche_chainlink_feeds.csv');
+    const csvPath = process.env.FEEDS_FILE || (process.env.NODE_ENV === 'production'
+      ? path.join('/app', this.network.feedsFile)
+      : path.join(__dirname, '../../../', this.network.feedsFile));
+    
+    // Only the mainnet feed list ships with the repo; testnet lists are generated
+    if (!fs.existsSync(csvPath)) {
+      throw new FeedsLoadError(`No feed list for ${this.CHAIN} at ${csvPath}; generate it with scripts/generate-feeds.js --chain ${this.CHAIN} or set FEEDS_FILE`);
+    }
+
+    this.exchangeRateFeeds = this.loadExchangeRateFeeds();
+    this.oracleFeeds = this.loadOracleFeeds();
     
     return new Promise((resolve, reject) => {
       const feedsData: FeedMetadata[] = [];
@@ -115,9 +97,17 @@ export class PriceService {
         .on('data', (row) => {
           // Convert CSV row to FeedMetadata format
           const symbol = this.extractSymbol(row.name);
+          let kind: FeedKind;
+          try {
+            kind = classifyFeed(row.name, row.kind);
+          } catch (error) {
+            reject(new FeedsLoadError(`${csvPath}: ${error instanceof Error ? error.message : error}`));
+            return;
+          }
           feedsData.push({
             name: row.name,
             symbol,
+            kind,
             contractAddress: row.contract_address,
             proxyAddress: row.proxy_address,
             decimals: parseInt(row.decimals),
@@ -131,6 +121,7 @@ export class PriceService {
         })
         .on('end', () => {
           this.feeds = feedsData;
+          this.refreshCalldata = null;
           console.log(`📊 Loaded ${this.feeds.length} Chainlink feeds`);
           resolve();
         })
@@ -138,6 +129,69 @@ export class PriceService {
     });
   }
 
+  // Typed entries from custom_feeds.json; raw-signature entries are CLI-only
+  private loadCustomFeedEntries(): any[] {
+    const configPath = process.env.CUSTOM_FEEDS || (process.env.NODE_ENV === 'production'
+      ? path.join('/app', 'custom_feeds.json')
+      : path.join(__dirname, '../../../custom_feeds.json'));
+
+    if (!fs.existsSync(configPath)) return [];
+
+    const config = JSON.parse(fs.readFileSync(configPath, 'utf8'));
+    return config.feeds ?? [];
+  }
+
+  // ERC-4626 and LST entries
+  private loadExchangeRateFeeds(): ExchangeRateFeed[] {
+    const feeds: ExchangeRateFeed[] = [];
+
+    for (const entry of this.loadCustomFeedEntries()) {
+      if (entry.type !== 'erc4626' && entry.type !== 'lst') continue;
+
+      const type: ExchangeRateFeedType = entry.type;
+      const method = entry.method ?? DEFAULT_METHOD[type];
+      if (!method || !RATE_METHODS[method] || !ethers.isAddress(entry.target)) {
+        console.warn(`⚠️ Skipping invalid ${type} feed: ${entry.name}`);
+        continue;
+      }
+
+      feeds.push({
+        name: entry.name,
+        symbol: this.extractSymbol(entry.name),
+        type,
+        address: entry.target,
+        method,
+        decimals: entry.decode?.decimals ?? 18
+      });
+    }
+
+    return feeds;
+  }
+
+  // ERC-2362 entries, one valueFor(id) read each
+  private loadOracleFeeds(): GenericOracleFeed[] {
+    const feeds: GenericOracleFeed[] = [];
+
+    for (const entry of this.loadCustomFeedEntries()) {
+      if (entry.type !== 'erc2362') continue;
+
+      const oracleId = resolveOracleId(entry.id, entry.decode?.decimals);
+      if (!oracleId || !ethers.isAddress(entry.target)) {
+        console.warn(`⚠️ Skipping invalid erc2362 feed: ${entry.name}`);
+        continue;
+      }
+
+      feeds.push({
+        name: entry.name,
+        symbol: this.extractSymbol(entry.name),
+        address: entry.target,
+        ...oracleId
+      });
+    }
+
+    return feeds;
+  }
+
   private extractSymbol(name: string): string {
     // Extract symbol from feed name (e.g., "BTC / USD" -> "BTCUSD")
     const cleaned = name.replace(/[^a-zA-Z]/g, '').toUpperCase();
@@ -156,6 +210,24 @@ export class PriceService {
     return cleaned;
   }
 
+  // The refresh batch only depends on the feed list, so encode it once and reuse it every cycle.
+  // It goes through aggregate3 so a feed that reverts fails on its own instead of sinking the batch.
+  private buildRefreshCalldata(): string {
+    const latestRoundData = AggregatorV3Interface.encodeLatestRoundData();
+    const calls = [
+      // aggregate3 reports no block number, so read it first, then the block timestamp so
+      // answer age is known while decoding the feeds that follow
+      { target: this.MULTICALL3_ADDRESS, allowFailure: false, callData: Multicall3.encodeGetBlockNumber() },
+      { target: this.MULTICALL3_ADDRESS, allowFailure: false, callData: Multicall3.encodeGetCurrentBlockTimestamp() },
+      ...this.feeds.map(feed => ({ target: feed.proxyAddress, allowFailure: true, callData: latestRoundData })),
+      // Exchange-rate reads ride after the Chainlink feeds, then generic oracle reads
+      ...this.exchangeRateFeeds.map(feed => ({ target: feed.address, allowFailure: true, callData: encodeRateCall(feed.method) })),
+      ...this.oracleFeeds.map(feed => ({ target: feed.address, allowFailure: true, callData: encodeValueFor(feed.id) }))
+    ];
+
+    return Multicall3.encodeAggregate3(calls);
+  }
+
   public async refreshPrices(): Promise<{ successful: number; errors: any[]; blockNumber: string; duration: number }> {
     if (this.isRefreshing) {
       throw new Error('Price refresh already in progress');
@@ -166,41 +238,109 @@ export class PriceService {
     const errors: any[] = [];
 
     try {
-      const chainlinkInterface = new ethers.Interface(this.CHAINLINK_ABI);
-      
-      // Prepare multicall data
-      const calls = this.feeds.map(feed => ({
-        target: feed.proxyAddress,
-        callData: chainlinkInterface.encodeFunctionData('latestRoundData', [])
-      }));
+      const feeds = this.feeds;
+      const rateFeeds = this.exchangeRateFeeds;
+      const oracleFeeds = this.oracleFeeds;
+      const totalFeeds = feeds.length + rateFeeds.length + oracleFeeds.length;
+      const calldata = (this.refreshCalldata ??= this.buildRefreshCalldata());
 
-      console.log(`🔄 Fetching prices for ${calls.length} feeds via Multicall3...`);
+      console.log(`🔄 Fetching prices for ${totalFeeds} feeds via Multicall3...`);
       
-      // Execute multicall
-      if (!this.multicall.aggregate) {
-        throw new Error('Multicall contract not properly initialized');
+      if (this.faults.enabled) {
+        console.log(`🧪 Fault injection active (refresh ${this.faults.nextStep()})`);
       }
-      const [blockNumber, returnData] = await this.multicall.aggregate.staticCall(calls);
+
+      // Execute multicall with the precomputed calldata; the header entries are never mangled so block and time stay readable
+      const result = this.faults.mangleAggregate3Result(await this.callRpc(calldata), [0, 1]);
       
-      // Process results
+      // Process results; entries 0 and 1 are the block number and timestamp, then Chainlink feeds,
+      // exchange-rate feeds and oracle feeds, each of which may have reverted on its own
       let successful = 0;
+      let blockNumber = 0n;
+      let blockTimestamp = 0;
+      let blockTimestampIso = '';
       
-      returnData.forEach((data: string, index: number) => {
+      decodeAggregate3Result(result, (index, data, success) => {
+        if (index === 0) {
+          blockNumber = data.uint(0);
+          return;
+        }
+        if (index === 1) {
+          blockTimestamp = Number(data.uint(0));
+          blockTimestampIso = new Date(blockTimestamp * 1000).toISOString();
+          return;
+        }
+
+        const position = index - HEADER_CALLS;
+        if (position >= feeds.length + rateFeeds.length) {
+          const oracleFeed = oracleFeeds[position - feeds.length - rateFeeds.length];
+          try {
+            if (!oracleFeed) return;
+            if (!success) throw callFailure(data);
+            const status = data.uint(2);
+            if (status !== ERC2362_STATUS_OK) throw new Error(`Oracle returned status ${status}`);
+            const oraclePrice = this.toOraclePrice(oracleFeed, data.int(0), Number(data.uint(1)), blockTimestamp, blockTimestampIso);
+            this.prices.set(oracleFeed.symbol, oraclePrice);
+            this.availability.recordFeed(oracleFeed.symbol, true);
+            this.feedHealth.observe(oracleFeed.symbol, null, 0, oraclePrice.price, Number(data.uint(1)));
+            successful++;
+          } catch (error) {
+            if (oracleFeed) this.availability.recordFeed(oracleFeed.symbol, false);
+            errors.push({
+              symbol: oracleFeed?.symbol || `OracleFeed_${position - feeds.length - rateFeeds.length}`,
+              error: error instanceof Error ? error.message : 'Unknown error'
+            });
+          }
+          return;
+        }
+
+        if (position >= feeds.length) {
+          const rateFeed = rateFeeds[position - feeds.length];
+          try {
+            if (!rateFeed) return;
+            if (!success) throw callFailure(data);
+            const ratePrice = this.toExchangeRatePrice(rateFeed, data.uint(0), blockTimestamp, blockTimestampIso);
+            this.prices.set(rateFeed.symbol, ratePrice);
+            this.availability.recordFeed(rateFeed.symbol, true);
+            this.feedHealth.observe(rateFeed.symbol, null, 0, ratePrice.price, blockTimestamp);
+            successful++;
+          } catch (error) {
+            if (rateFeed) this.availability.recordFeed(rateFeed.symbol, false);
+            errors.push({
+              symbol: rateFeed?.symbol || `RateFeed_${position - feeds.length}`,
+              error: error instanceof Error ? error.message : 'Unknown error'
+            });
+          }
+          return;
+        }
+
+        const feed = feeds[position];
         try {
-          const [roundId, answer, startedAt, updatedAt, answeredInRound] = 
-            chainlinkInterface.decodeFunctionResult('latestRoundData', data);
-          
-          const feed = this.feeds[index];
           if (!feed) return;
+          if (!success) throw callFailure(data);
+          
+          const roundId = data.uint(0);
+          const answer = data.int(1);
+          const startedAt = data.uint(2);
+          const updatedAt = data.uint(3);
+          const answeredInRound = data.uint(4);
+          
+          const invalid = validateAnswer(feed.kind, answer);
+          if (invalid) throw new Error(invalid);
           
           const price = Number(answer) / Math.pow(10, feed.decimals);
           
           const priceData: PriceData = {
             symbol: feed.symbol,
+            source: 'chainlink',
+            kind: feed.kind,
             price,
+            exactPrice: formatAnswer(answer, feed.decimals),
             decimals: feed.decimals,
             roundId: roundId.toString(),
             updatedAt: new Date(Number(updatedAt) * 1000).toISOString(),
+            blockTimestamp: blockTimestampIso,
+            ageAtBlock: Math.max(0, blockTimestamp - Number(updatedAt)),
             proxyAddress: feed.proxyAddress,
             raw: {
               answer: answer.toString(),
@@ -211,12 +351,16 @@ export class PriceService {
           };
           
           this.prices.set(feed.symbol, priceData);
+          this.availability.recordFeed(feed.symbol, true);
+          const interval = this.updateFrequency.recordObservation(feed.symbol, feed.heartbeat, feed.deviationThreshold, Number(updatedAt), roundId, blockTimestamp);
+          if (interval !== null) observeUpdateInterval(this.CHAIN, feed.symbol, interval);
+          this.feedHealth.observe(feed.symbol, feed.heartbeat, feed.deviationThreshold, price, Number(updatedAt));
           successful++;
           
         } catch (error) {
-          const feed = this.feeds[index];
+          if (feed) this.availability.recordFeed(feed.symbol, false);
           const errorInfo = {
-            symbol: feed?.symbol || `Feed_${index}`,
+            symbol: feed?.symbol || `Feed_${position}`,
             error: error instanceof Error ? error.message : 'Unknown error'
           };
           errors.push(errorInfo);
@@ -225,9 +369,11 @@ export class PriceService {
       });
 
       this.lastUpdate = new Date();
+      this.reportSlowFeeds(blockTimestamp);
+      this.gradeFeeds(blockTimestamp);
       const duration = Date.now() - startTime;
       
-      console.log(`✅ Price refresh completed: ${successful}/${this.feeds.length} successful in ${duration}ms`);
+      console.log(`✅ Price refresh completed: ${successful}/${totalFeeds} successful in ${duration}ms`);
       
       return {
         successful,
@@ -241,9 +387,157 @@ export class PriceService {
       throw error;
     } finally {
       this.isRefreshing = false;
+      this.persistTrackers();
+    }
+  }
+
+  private persistTrackers(): void {
+    try {
+      this.faults.write(() => {
+        this.availability.save();
+        this.updateFrequency.save();
+        this.feedHealth.save();
+      });
+    } catch (error) {
+      console.warn('⚠️ Skipping tracker persistence:', error);
+    }
+  }
+
+  // Feeds that failed to decode keep their last price, so they are graded down alongside it
+  private gradeFeeds(now: number): void {
+    this.feedHealth.refresh(this.updateFrequency.getReport(now), this.availability.getReport(), now);
+    this.prices.forEach((priceData, symbol) => {
+      const health = this.feedHealth.get(symbol);
+      if (!health) return;
+      priceData.health = { score: health.score, grade: health.grade };
+      setFeedHealthScore(this.CHAIN, symbol, health.score);
+    });
+  }
+
+  // Warn once when a feed starts updating less often than its heartbeat promises, unless silenced
+  private reportSlowFeeds(now: number): void {
+    const alerts = this.updateFrequencyAlerts(now);
+    alerts
+      .filter(alert => !alert.silenced && !this.slowFeeds.has(alert.feed as string))
+      .forEach(alert => console.warn(`⚠️ ${alert.message}`));
+    this.slowFeeds = new Set(alerts.map(alert => alert.feed as string));
+  }
+
+  // A slow feed past heartbeat × tolerance with no update is stale; otherwise its cadence is just too low
+  private updateFrequencyAlerts(now: number): Alert[] {
+    const report = this.updateFrequency.getReport(now);
+    return report.feeds
+      .filter(record => record.status === 'slow')
+      .map(record => this.silences.apply({
+        type: record.secondsSinceUpdate > record.heartbeat * report.tolerance ? 'stale' : 'slow-updates',
+        feed: record.symbol,
+        message: `${record.symbol} is updating slower than its heartbeat: ${record.reasons.join('; ')}`
+      }));
+  }
+
+  public getAlerts(): Alert[] {
+    const rpcAlerts = this.availability.getReport().rpcs
+      .filter(record => record.lastFailure !== null && (record.lastSuccess === null || record.lastFailure > record.lastSuccess))
+      .map(record => this.silences.apply({
+        type: 'rpc-failure',
+        feed: null,
+        message: `Last call to ${record.key} failed at ${record.lastFailure}`
+      }));
+
+    return [...rpcAlerts, ...this.updateFrequencyAlerts(Date.now() / 1000)];
+  }
+
+  public getSilences(): SilenceManager {
+    return this.silences;
+  }
+
+  private silencesPath(): string {
+    if (process.env.SILENCES_FILE) return process.env.SILENCES_FILE;
+    return process.env.NODE_ENV === 'production'
+      ? path.join('/app', 'silences.json')
+      : path.join(__dirname, '../../../silences.json');
+  }
+
+  // Transport failures count against the RPC. A reverted batch was answered but read nothing,
+  // so it counts against every feed in it rather than for the RPC; feeds that revert on their
+  // own inside a successful batch are recorded while decoding
+  private async callRpc(calldata: string): Promise<string> {
+    try {
+      const result = await this.faults.call(() => this.multicall.call(calldata));
+      this.availability.recordRpc(this.AVALANCHE_RPC, true);
+      return result;
+    } catch (error) {
+      if ((error as { code?: string }).code === 'CALL_EXCEPTION') {
+        this.batchSymbols().forEach(symbol => this.availability.recordFeed(symbol, false));
+      } else {
+        this.availability.recordRpc(this.AVALANCHE_RPC, false);
+      }
+      throw error;
     }
   }
 
+  private batchSymbols(): string[] {
+    return [...this.feeds, ...this.exchangeRateFeeds, ...this.oracleFeeds].map(feed => feed.symbol);
+  }
+
+  public getAvailability(): AvailabilityReport {
+    return this.availability.getReport();
+  }
+
+  public getUpdateFrequency(): UpdateFrequencyReport {
+    return this.updateFrequency.getReport();
+  }
+
+  public getFeedHealth(): FeedHealthReport {
+    return this.feedHealth.getReport();
+  }
+
+  // Exchange rates are live reads, so they are stamped with the sampled block
+  private toExchangeRatePrice(feed: ExchangeRateFeed, assets: bigint, blockTimestamp: number, blockTimestampIso: string): PriceData {
+    return {
+      symbol: feed.symbol,
+      source: feed.type,
+      kind: 'rate',
+      price: Number(assets) / Math.pow(10, feed.decimals),
+      exactPrice: formatAnswer(assets, feed.decimals),
+      decimals: feed.decimals,
+      roundId: '0',
+      updatedAt: blockTimestampIso,
+      blockTimestamp: blockTimestampIso,
+      ageAtBlock: 0,
+      proxyAddress: feed.address,
+      raw: {
+        answer: assets.toString(),
+        startedAt: blockTimestamp.toString(),
+        updatedAt: blockTimestamp.toString(),
+        answeredInRound: '0'
+      }
+    };
+  }
+
+  // Oracle values carry their own timestamp, so age is measured like a Chainlink answer
+  private toOraclePrice(feed: GenericOracleFeed, value: bigint, updatedAt: number, blockTimestamp: number, blockTimestampIso: string): PriceData {
+    return {
+      symbol: feed.symbol,
+      source: 'erc2362',
+      kind: 'price',
+      price: Number(value) / Math.pow(10, feed.decimals),
+      exactPrice: formatAnswer(value, feed.decimals),
+      decimals: feed.decimals,
+      roundId: '0',
+      updatedAt: new Date(updatedAt * 1000).toISOString(),
+      blockTimestamp: blockTimestampIso,
+      ageAtBlock: Math.max(0, blockTimestamp - updatedAt),
+      proxyAddress: feed.address,
+      raw: {
+        answer: value.toString(),
+        startedAt: updatedAt.toString(),
+        updatedAt: updatedAt.toString(),
+        answeredInRound: '0'
+      }
+    };
+  }
+
   public getFeeds(): FeedMetadata[] {
     return [...this.feeds];
   }
@@ -290,12 +584,16 @@ export class PriceService {
       
       return {
         connected: true,
+        chain: this.network.name,
+        testnet: this.network.testnet,
         chainId: network.chainId.toString(),
         blockNumber: blockNumber.toString()
       };
     } catch (error) {
       return {
         connected: false,
+        chain: this.network.name,
+        testnet: this.network.testnet,
         chainId: 'unknown',
         blockNumber: 'unknown'
       };
@@ -308,12 +606,9 @@ export class PriceService {
     if (!feed) return null;
 
     try {
-      const contract = new ethers.Contract(feed.proxyAddress, this.CHAINLINK_ABI, this.provider);
-      if (!contract.getRoundData) {
-        throw new Error('getRoundData function not available');
-      }
-      const [retRoundId, answer, startedAt, updatedAt, answeredInRound] = 
-        await contract.getRoundData(roundId);
+      const aggregator = new AggregatorV3Interface(feed.proxyAddress, this.provider);
+      const { roundId: retRoundId, answer, startedAt, updatedAt, answeredInRound } =
+        await aggregator.getRoundData(BigInt(roundId));
       
       const price = Number(answer) / Math.pow(10, feed.decimals);
       
@@ -334,25 +629,105 @@ export class PriceService {
     }
   }
 
+  // Walk back from the latest round, fetching up to `count` rounds in one multicall
+  public async getRoundHistory(symbol: string, count: number): Promise<RoundData[] | null> {
+    const feed = this.getFeed(symbol);
+    if (!feed) return null;
+
+    const aggregator = new AggregatorV3Interface(feed.proxyAddress, this.provider);
+    const { roundId: latest } = await aggregator.latestRoundData();
+
+    // Proxy round IDs carry the phase in the upper bits; stay within the current phase
+    const aggregatorRound = latest & ((1n << 64n) - 1n);
+    const available = Number(aggregatorRound < BigInt(count) ? aggregatorRound : BigInt(count));
+
+    const calls = Array.from({ length: available }, (_, i) => ({
+      target: feed.proxyAddress,
+      allowFailure: true,
+      callData: AggregatorV3Interface.encodeGetRoundData(latest - BigInt(available - 1 - i))
+    }));
+
+    // A round missing from the history, common across phase boundaries, either reverts or reads
+    // as zeros; skip it rather than losing the whole series
+    const results = await this.multicall.aggregate3(calls);
+    const rounds: RoundData[] = [];
+
+    for (const { success, returnData } of results) {
+      if (!success) continue;
+
+      let round: RoundDataResult;
+      try {
+        round = AggregatorV3Interface.decodeGetRoundData(returnData);
+      } catch {
+        continue;
+      }
+      const { roundId, answer, startedAt, updatedAt, answeredInRound } = round;
+      if (updatedAt === 0n) continue;
+
+      rounds.push({
+        roundId: roundId.toString(),
+        answer: answer.toString(),
+        startedAt: startedAt.toString(),
+        updatedAt: updatedAt.toString(),
+        answeredInRound: answeredInRound.toString(),
+        price: Number(answer) / Math.pow(10, feed.decimals),
+        decimals: feed.decimals,
+        symbol: feed.symbol,
+        timestamp: new Date(Number(updatedAt) * 1000).toISOString()
+      });
+    }
+
+    return rounds;
+  }
+
+  // Realized volatility over the trailing `window` rounds
+  public async getVolatility(symbol: string, window: number): Promise<VolatilityData | null> {
+    const feed = this.getFeed(symbol);
+    if (feed && !FEED_KIND_RULES[feed.kind].volatility) {
+      throw new ValidationError('symbol', `volatility is not meaningful for ${feed.kind} feeds`);
+    }
+
+    const rounds = await this.getRoundHistory(symbol, window);
+    if (!rounds) return null;
+
+    const result = computeRealizedVolatility(rounds.map(round => ({
+      price: round.price,
+      timestamp: Number(round.updatedAt)
+    })));
+    if (!result) {
+      throw new InsufficientHistoryError(symbol, window);
+    }
+
+    const first = rounds[0];
+    const last = rounds[rounds.length - 1];
+    setRealizedVolatility(this.CHAIN, first?.symbol ?? symbol, window, result.realizedVolatility);
+
+    return {
+      symbol: first?.symbol ?? symbol,
+      window,
+      observations: result.observations,
+      fromTimestamp: first?.timestamp ?? '',
+      toTimestamp: last?.timestamp ?? '',
+      meanIntervalSeconds: result.meanIntervalSeconds,
+      stdDev: result.stdDev,
+      realizedVolatility: result.realizedVolatility
+    };
+  }
+
   // Get feed descriptions via multicall
   public async getFeedDescriptions(): Promise<FeedDescription[]> {
     try {
-      const chainlinkInterface = new ethers.Interface(this.CHAINLINK_ABI);
-      
       const calls = this.feeds.map(feed => ({
         target: feed.proxyAddress,
-        callData: chainlinkInterface.encodeFunctionData('description', [])
+        callData: AggregatorV3Interface.encodeDescription()
       }));
 
-      if (!this.multicall.aggregate) {
-        throw new Error('Multicall contract not properly initialized');
-      }
-      const [, returnData] = await this.multicall.aggregate.staticCall(calls);
+      const { returnData } = await this.multicall.aggregate(calls);
       
       const descriptions: FeedDescription[] = [];
-      returnData.forEach((data: string, index: number) => {
+      returnData.forEach((data, index) => {
         try {
-          const [description] = chainlinkInterface.decodeFunctionResult('description', data);
+          const description = AggregatorV3Interface.decodeDescription(data);
           const feed = this.feeds[index];
           if (feed) {
             descriptions.push({
@@ -376,22 +751,17 @@ export class PriceService {
   // Get feed versions via multicall
   public async getFeedVersions(): Promise<FeedVersion[]> {
     try {
-      const chainlinkInterface = new ethers.Interface(this.CHAINLINK_ABI);
-      
       const calls = this.feeds.map(feed => ({
         target: feed.proxyAddress,
-        callData: chainlinkInterface.encodeFunctionData('version', [])
+        callData: AggregatorV3Interface.encodeVersion()
       }));
 
-      if (!this.multicall.aggregate) {
-        throw new Error('Multicall contract not properly initialized');
-      }
-      const [, returnData] = await this.multicall.aggregate.staticCall(calls);
+      const { returnData } = await this.multicall.aggregate(calls);
       
       const versions: FeedVersion[] = [];
-      returnData.forEach((data: string, index: number) => {
+      returnData.forEach((data, index) => {
         try {
-          const [version] = chainlinkInterface.decodeFunctionResult('version', data);
+          const version = AggregatorV3Interface.decodeVersion(data);
           const feed = this.feeds[index];
           if (feed) {
             versions.push({
@@ -415,27 +785,22 @@ export class PriceService {
   // Get feed decimals via multicall
   public async getFeedDecimals(): Promise<FeedDecimals[]> {
     try {
-      const chainlinkInterface = new ethers.Interface(this.CHAINLINK_ABI);
-      
       const calls = this.feeds.map(feed => ({
         target: feed.proxyAddress,
-        callData: chainlinkInterface.encodeFunctionData('decimals', [])
+        callData: AggregatorV3Interface.encodeDecimals()
       }));
 
-      if (!this.multicall.aggregate) {
-        throw new Error('Multicall contract not properly initialized');
-      }
-      const [, returnData] = await this.multicall.aggregate.staticCall(calls);
+      const { returnData } = await this.multicall.aggregate(calls);
       
       const decimalsData: FeedDecimals[] = [];
-      returnData.forEach((data: string, index: number) => {
+      returnData.forEach((data, index) => {
         try {
-          const [decimals] = chainlinkInterface.decodeFunctionResult('decimals', data);
+          const decimals = AggregatorV3Interface.decodeDecimals(data);
           const feed = this.feeds[index];
           if (feed) {
             decimalsData.push({
               symbol: feed.symbol,
-              decimals: Number(decimals),
+              decimals,
               proxyAddress: feed.proxyAddress
             });
           }
@@ -465,25 +830,20 @@ export class PriceService {
     const reserves: ProofOfReserveData[] = [];
 
     try {
-      const chainlinkInterface = new ethers.Interface(this.CHAINLINK_ABI);
-      
       // Get latest round data for PoR feeds
       const calls = porFeeds.map(feed => ({
         target: feed.proxyAddress,
-        callData: chainlinkInterface.encodeFunctionData('latestRoundData', [])
+        callData: AggregatorV3Interface.encodeLatestRoundData()
       }));
 
       if (calls.length === 0) return reserves;
 
-      if (!this.multicall.aggregate) {
-        throw new Error('Multicall contract not properly initialized');
-      }
-      const [, returnData] = await this.multicall.aggregate.staticCall(calls);
+      const { returnData } = await this.multicall.aggregate(calls);
       
-      returnData.forEach((data: string, index: number) => {
+      returnData.forEach((data, index) => {
         try {
-          const [roundId, answer, startedAt, updatedAt, answeredInRound] = 
-            chainlinkInterface.decodeFunctionResult('latestRoundData', data);
+          const { roundId, answer, updatedAt } =
+            AggregatorV3Interface.decodeLatestRoundData(data);
           
           const feed = porFeeds[index];
           if (!feed) return;
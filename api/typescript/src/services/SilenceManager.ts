@@ -0,0 +1,188 @@
+/**
+ * Alert Silencing
+ * Silences (per feed, per alert type, time-bounded) and maintenance windows,
+ * read from a config file and editable through the API. API changes are
+ * written back to the same file so they survive restarts.
+ */
+
+import crypto from 'crypto';
+import fs from 'fs';
+import { Alert, AlertType, MaintenanceWindow, Silence, SilenceList } from '../types';
+import { ValidationError } from '../utils/errors';
+
+export const ALERT_TYPES: readonly AlertType[] = ['stale', 'slow-updates', 'rpc-failure'];
+
+export interface SilenceInput {
+  feed?: string | null;
+  alertType?: string | null;
+  startsAt?: string;
+  endsAt?: string;
+  durationMinutes?: number;
+  reason?: string;
+}
+
+// Offset-less date-times would be read as server-local time
+function parseTime(field: string, value: unknown): number {
+  if (typeof value !== 'string' || !/(Z|[+-]\d{2}:\d{2})$/i.test(value) || !Number.isFinite(Date.parse(value))) {
+    throw new ValidationError(field, 'must be an RFC 3339 date-time with an explicit offset');
+  }
+  return Date.parse(value);
+}
+
+// Resolves startsAt/endsAt/durationMinutes into a validated time range
+function parseRange(input: SilenceInput, now: number): { startsAt: string; endsAt: string } {
+  const start = input.startsAt === undefined ? now : parseTime('startsAt', input.startsAt);
+
+  let end: number;
+  if (input.endsAt !== undefined) {
+    end = parseTime('endsAt', input.endsAt);
+  } else if (typeof input.durationMinutes === 'number' && input.durationMinutes > 0) {
+    end = start + input.durationMinutes * 60_000;
+  } else {
+    throw new ValidationError('endsAt', 'either endsAt or a positive durationMinutes is required');
+  }
+
+  if (end <= start) {
+    throw new ValidationError('endsAt', 'must be after startsAt');
+  }
+  return { startsAt: new Date(start).toISOString(), endsAt: new Date(end).toISOString() };
+}
+
+type StoredEntry = SilenceInput & { id?: string };
+
+function buildSilence(input: StoredEntry, now: number): Silence {
+  if (input.alertType != null && !ALERT_TYPES.includes(input.alertType as AlertType)) {
+    throw new ValidationError('alertType', `must be one of ${ALERT_TYPES.join(', ')}`);
+  }
+  return {
+    id: input.id ?? crypto.randomUUID(),
+    feed: input.feed || null,
+    alertType: (input.alertType as AlertType) ?? null,
+    ...parseRange(input, now),
+    reason: input.reason ?? ''
+  };
+}
+
+function buildMaintenanceWindow(input: StoredEntry, now: number): MaintenanceWindow {
+  return {
+    id: input.id ?? crypto.randomUUID(),
+    ...parseRange(input, now),
+    reason: input.reason ?? ''
+  };
+}
+
+const isActive = (entry: { startsAt: string; endsAt: string }, now: number): boolean =>
+  Date.parse(entry.startsAt) <= now && now < Date.parse(entry.endsAt);
+
+export class SilenceManager {
+  private silences: Silence[] = [];
+  private maintenanceWindows: MaintenanceWindow[] = [];
+
+  constructor(private readonly configPath: string | undefined) {
+    this.load();
+  }
+
+  public list(now: number = Date.now()): SilenceList {
+    return {
+      silences: [...this.silences],
+      maintenanceWindows: [...this.maintenanceWindows],
+      inMaintenance: this.maintenanceWindows.some(w => isActive(w, now))
+    };
+  }
+
+  public addSilence(input: SilenceInput, now: number = Date.now()): Silence {
+    const silence = buildSilence(input, now);
+    this.silences.push(silence);
+    this.save(now);
+    return silence;
+  }
+
+  public addMaintenanceWindow(input: SilenceInput, now: number = Date.now()): MaintenanceWindow {
+    const window = buildMaintenanceWindow(input, now);
+    this.maintenanceWindows.push(window);
+    this.save(now);
+    return window;
+  }
+
+  // Removes a silence or maintenance window; false when the id is unknown
+  public remove(id: string, now: number = Date.now()): boolean {
+    const before = this.silences.length + this.maintenanceWindows.length;
+    this.silences = this.silences.filter(s => s.id !== id);
+    this.maintenanceWindows = this.maintenanceWindows.filter(w => w.id !== id);
+    if (this.silences.length + this.maintenanceWindows.length === before) return false;
+
+    this.save(now);
+    return true;
+  }
+
+  // Id of whatever silences this alert right now, maintenance windows first
+  public match(type: AlertType, feed: string | null, now: number = Date.now()): string | null {
+    const window = this.maintenanceWindows.find(w => isActive(w, now));
+    if (window) return window.id;
+
+    const silence = this.silences.find(s => isActive(s, now) &&
+      (s.alertType === null || s.alertType === type) &&
+      (s.feed === null || s.feed.toUpperCase() === feed?.toUpperCase()));
+    return silence?.id ?? null;
+  }
+
+  public apply(alert: Omit<Alert, 'silenced' | 'silencedBy'>, now: number = Date.now()): Alert {
+    const silencedBy = this.match(alert.type, alert.feed, now);
+    return { ...alert, silenced: silencedBy !== null, silencedBy };
+  }
+
+  // Expired entries are dropped whenever the file is rewritten
+  private save(now: number): void {
+    this.silences = this.silences.filter(s => Date.parse(s.endsAt) > now);
+    this.maintenanceWindows = this.maintenanceWindows.filter(w => Date.parse(w.endsAt) > now);
+    if (!this.configPath) return;
+
+    try {
+      fs.writeFileSync(this.configPath, JSON.stringify({
+        silences: this.silences,
+        maintenanceWindows: this.maintenanceWindows
+      }, null, 2));
+    } catch (error) {
+      console.warn(`⚠️ Could not persist silences to ${this.configPath}:`, error);
+    }
+  }
+
+  // A bad entry is skipped on its own and expired ones are dropped. The file is written back
+  // with absolute startsAt/endsAt, so a durationMinutes window doesn't restart on every boot
+  private load(): void {
+    if (!this.configPath || !fs.existsSync(this.configPath)) return;
+
+    let config: { silences?: unknown; maintenanceWindows?: unknown } | null;
+    try {
+      config = JSON.parse(fs.readFileSync(this.configPath, 'utf8'));
+    } catch (error) {
+      console.warn(`⚠️ Ignoring unreadable silences file ${this.configPath}:`, error);
+      return;
+    }
+
+    const now = Date.now();
+    this.silences = this.loadEntries('silences', config?.silences, entry => buildSilence(entry, now), now);
+    this.maintenanceWindows = this.loadEntries('maintenanceWindows', config?.maintenanceWindows,
+      entry => buildMaintenanceWindow(entry, now), now);
+    this.save(now);
+  }
+
+  private loadEntries<T>(key: string, entries: unknown, build: (entry: StoredEntry) => T, now: number): T[] {
+    if (entries === undefined) return [];
+    if (!Array.isArray(entries)) {
+      console.warn(`⚠️ Ignoring ${key} in ${this.configPath}: expected a list`);
+      return [];
+    }
+
+    const loaded: T[] = [];
+    entries.forEach((entry: StoredEntry, index) => {
+      try {
+        if (entry.endsAt !== undefined && parseTime('endsAt', entry.endsAt) <= now) return;
+        loaded.push(build(entry));
+      } catch (error) {
+        console.warn(`⚠️ Skipping ${key}[${index}] in ${this.configPath}: ${(error as Error).message}`);
+      }
+    });
+    return loaded;
+  }
+}
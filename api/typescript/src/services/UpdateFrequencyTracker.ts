@@ -0,0 +1,168 @@
+/**
+ * Update Frequency Monitoring
+ * Records each distinct on-chain update observed per feed and compares the
+ * observed cadence over trailing windows against the feed's documented
+ * heartbeat, flagging feeds that update significantly less often than promised
+ */
+
+import fs from 'fs';
+import { UpdateFrequencyRecord, UpdateFrequencyReport, UpdateFrequencyWindow } from '../types';
+
+interface FeedUpdates {
+  heartbeat: number; // seconds
+  deviationThreshold: number;
+  firstSeen: number; // unix seconds the tracker started observing the feed
+  updates: number[]; // distinct updatedAt values, unix seconds, ascending
+  lastRoundId?: string; // proxy roundId of the latest observation
+}
+
+export const UPDATE_FREQUENCY_WINDOWS: ReadonlyArray<{ label: string; seconds: number }> = [
+  { label: '1h', seconds: 3600 },
+  { label: '24h', seconds: 86400 },
+  { label: '7d', seconds: 604800 }
+];
+
+// A feed is slow once a gap or its update count misses the heartbeat promise by this factor
+export const DEFAULT_UPDATE_TOLERANCE = 1.5;
+
+const RETENTION_SECONDS = Math.max(...UPDATE_FREQUENCY_WINDOWS.map(w => w.seconds));
+
+// Proxy round ids carry the phase in their top bits; rounds only count up within one phase
+const PHASE_OFFSET = 64n;
+
+function roundsBetween(previous: string | undefined, current: bigint): number | null {
+  if (previous === undefined) return null;
+  const last = BigInt(previous);
+  if (last >> PHASE_OFFSET !== current >> PHASE_OFFSET || current <= last) return null;
+  return Number(current - last);
+}
+
+export class UpdateFrequencyTracker {
+  private feeds: Map<string, FeedUpdates> = new Map();
+  private resumed: Set<string> = new Set(); // loaded from disk, not yet observed since
+
+  constructor(
+    private readonly tolerance: number = parseFloat(process.env.UPDATE_TOLERANCE ?? '') || DEFAULT_UPDATE_TOLERANCE,
+    private readonly persistPath: string | undefined = process.env.UPDATE_FREQUENCY_FILE
+  ) {
+    this.load();
+  }
+
+  // Polls see the same round repeatedly; only a new updatedAt counts as an update.
+  // Returns the mean seconds per round since the previous observed update: a poll can miss
+  // rounds, so the gap is divided by how far roundId moved. The first observation after
+  // loading persisted state returns null, as its gap spans the restart.
+  public recordObservation(symbol: string, heartbeat: number, deviationThreshold: number, updatedAt: number, roundId: bigint, now: number = Date.now() / 1000): number | null {
+    const feed = this.feeds.get(symbol) ?? { heartbeat, deviationThreshold, firstSeen: Math.min(now, updatedAt), updates: [] };
+    feed.heartbeat = heartbeat;
+    feed.deviationThreshold = deviationThreshold;
+    const resumed = this.resumed.delete(symbol);
+
+    const last = feed.updates[feed.updates.length - 1];
+    const isNew = last === undefined || updatedAt > last;
+    if (isNew) {
+      feed.updates.push(updatedAt);
+    }
+    const rounds = roundsBetween(feed.lastRoundId, roundId);
+    feed.lastRoundId = roundId.toString();
+
+    const cutoff = now - RETENTION_SECONDS;
+    while (feed.updates.length > 1 && feed.updates[0] < cutoff) {
+      feed.updates.shift();
+    }
+    feed.firstSeen = Math.max(feed.firstSeen, cutoff);
+
+    this.feeds.set(symbol, feed);
+    if (!isNew || last === undefined || resumed || rounds === null) return null;
+    return (updatedAt - last) / rounds;
+  }
+
+  public getReport(now: number = Date.now() / 1000): UpdateFrequencyReport {
+    const feeds = Array.from(this.feeds.entries()).map(([symbol, feed]) => this.summarize(symbol, feed, now));
+    const rank = { slow: 0, ok: 1, 'insufficient-data': 2 } as const;
+
+    return {
+      tolerance: this.tolerance,
+      windows: UPDATE_FREQUENCY_WINDOWS.map(w => w.label),
+      feeds: feeds.sort((a, b) => rank[a.status] - rank[b.status] || b.secondsSinceUpdate - a.secondsSinceUpdate)
+    };
+  }
+
+  public getAlerts(now: number = Date.now() / 1000): UpdateFrequencyRecord[] {
+    return this.getReport(now).feeds.filter(record => record.status === 'slow');
+  }
+
+  // Write observed updates to disk so trailing windows survive restarts
+  public save(): void {
+    if (!this.persistPath) return;
+
+    try {
+      fs.writeFileSync(this.persistPath, JSON.stringify(Object.fromEntries(this.feeds)));
+    } catch (error) {
+      console.warn(`⚠️ Could not persist update frequency to ${this.persistPath}:`, error);
+    }
+  }
+
+  private load(): void {
+    if (!this.persistPath || !fs.existsSync(this.persistPath)) return;
+
+    try {
+      this.feeds = new Map(Object.entries(JSON.parse(fs.readFileSync(this.persistPath, 'utf8'))));
+      this.resumed = new Set(this.feeds.keys());
+    } catch (error) {
+      console.warn(`⚠️ Ignoring unreadable update frequency file ${this.persistPath}:`, error);
+    }
+  }
+
+  private summarize(symbol: string, feed: FeedUpdates, now: number): UpdateFrequencyRecord {
+    const last = feed.updates[feed.updates.length - 1];
+    const secondsSinceUpdate = Math.max(0, Math.round(now - last));
+    const limit = feed.heartbeat * this.tolerance;
+
+    const windows = UPDATE_FREQUENCY_WINDOWS.map(({ label, seconds }) => this.summarizeWindow(label, seconds, feed, now));
+    const reasons: string[] = [];
+
+    if (secondsSinceUpdate > limit) {
+      reasons.push(`no update for ${secondsSinceUpdate}s (heartbeat ${feed.heartbeat}s)`);
+    }
+    windows.forEach(w => {
+      if (w.maxGap !== null && w.maxGap > limit) {
+        reasons.push(`${w.window}: ${w.maxGap}s gap between updates (heartbeat ${feed.heartbeat}s)`);
+      } else if (w.updates < Math.floor(w.coverage / limit)) {
+        reasons.push(`${w.window}: ${w.updates} updates, heartbeat promises at least ${w.expectedUpdates}`);
+      }
+    });
+
+    // Until a full heartbeat has been observed there is nothing to compare against
+    const observedFor = now - feed.firstSeen;
+    const status = reasons.length > 0 ? 'slow' : observedFor < feed.heartbeat ? 'insufficient-data' : 'ok';
+
+    return {
+      symbol,
+      heartbeat: feed.heartbeat,
+      deviationThreshold: feed.deviationThreshold,
+      lastUpdate: new Date(last * 1000).toISOString(),
+      secondsSinceUpdate,
+      status,
+      reasons,
+      windows
+    };
+  }
+
+  // Only the part of the window the tracker has actually observed counts toward expectations
+  private summarizeWindow(label: string, seconds: number, feed: FeedUpdates, now: number): UpdateFrequencyWindow {
+    const start = now - seconds;
+    const coverage = Math.max(0, Math.min(seconds, now - feed.firstSeen));
+    const inWindow = feed.updates.filter(at => at >= start);
+    const gaps = inWindow.slice(1).map((at, i) => at - inWindow[i]);
+
+    return {
+      window: label,
+      coverage: Math.round(coverage),
+      updates: inWindow.length,
+      expectedUpdates: Math.floor(coverage / feed.heartbeat),
+      meanInterval: gaps.length > 0 ? Math.round(gaps.reduce((sum, gap) => sum + gap, 0) / gaps.length) : null,
+      maxGap: gaps.length > 0 ? Math.max(...gaps) : null
+    };
+  }
+}
@@ -1,8 +1,12 @@
 // Shared API types for both TypeScript and Python implementations
 
+// What a feed's answer represents; controls validation, formatting and sanity checks
+export type FeedKind = 'price' | 'rate' | 'index' | 'por';
+
 export interface FeedMetadata {
   name: string;
   symbol: string;
+  kind: FeedKind;
   contractAddress: string;
   proxyAddress: string;
   decimals: number;
@@ -14,12 +18,20 @@ export interface FeedMetadata {
   quoteAsset: string;
 }
 
+// Where a price came from: a Chainlink feed, a built-in exchange-rate read or an ERC-2362 oracle
+export type PriceSource = 'chainlink' | 'erc4626' | 'lst' | 'erc2362';
+
 export interface PriceData {
   symbol: string;
+  source: PriceSource;
+  kind: FeedKind;
   price: number;
+  exactPrice: string; // full-precision decimal string of the answer
   decimals: number;
   roundId: string;
   updatedAt: string;
+  blockTimestamp: string;
+  ageAtBlock: number; // seconds between updatedAt and the sampled block, never negative
   proxyAddress: string;
   raw: {
     answer: string;
@@ -27,6 +39,10 @@ export interface PriceData {
     updatedAt: string;
     answeredInRound: string;
   };
+  health?: {
+    score: number;
+    grade: FeedHealthGrade;
+  };
 }
 
 export interface ApiResponse<T> {
@@ -51,6 +67,8 @@ export interface HealthCheck {
   uptime: number;
   avalanche: {
     connected: boolean;
+    chain: string;
+    testnet: boolean;
     chainId: string;
     blockNumber: string;
   };
@@ -60,6 +78,108 @@ export interface HealthCheck {
   };
 }
 
+export interface AvailabilityRecord {
+  key: string; // feed symbol or RPC URL
+  successes: number;
+  failures: number;
+  availability: number; // 0..1 over the rolling window
+  failureShare: number; // this key's share of all failures in the window
+  since: string;
+  lastSuccess: string | null;
+  lastFailure: string | null;
+}
+
+export interface AvailabilityReport {
+  window: number; // samples retained per key
+  rpcs: AvailabilityRecord[];
+  feeds: AvailabilityRecord[];
+}
+
+export interface UpdateFrequencyWindow {
+  window: string; // e.g. "24h"
+  coverage: number; // seconds of the window actually observed
+  updates: number; // distinct on-chain updates seen in the window
+  expectedUpdates: number; // minimum the heartbeat promises over the covered span
+  meanInterval: number | null; // seconds between consecutive updates
+  maxGap: number | null;
+}
+
+export interface UpdateFrequencyRecord {
+  symbol: string;
+  heartbeat: number;
+  deviationThreshold: number;
+  lastUpdate: string;
+  secondsSinceUpdate: number;
+  status: 'ok' | 'slow' | 'insufficient-data';
+  reasons: string[]; // why the feed is considered slow
+  windows: UpdateFrequencyWindow[];
+}
+
+export interface UpdateFrequencyReport {
+  tolerance: number; // factor a gap may exceed the heartbeat by before alerting
+  windows: string[];
+  feeds: UpdateFrequencyRecord[];
+}
+
+// A = safe to use, F = do not trust without checking the components
+export type FeedHealthGrade = 'A' | 'B' | 'C' | 'D' | 'F';
+
+// Each component is 0-1, or null when it could not be measured yet
+export interface FeedHealthComponents {
+  staleness: number | null; // time-weighted age against the heartbeat
+  regularity: number | null; // largest gap between updates against heartbeat × tolerance
+  decoding: number | null; // share of reads that decoded and validated
+  deviation: number | null; // share of recent moves that were not anomalous jumps
+}
+
+export interface FeedHealthRecord {
+  symbol: string;
+  score: number; // 0-100, weighted over the measured components
+  grade: FeedHealthGrade;
+  components: FeedHealthComponents;
+  anomalies: number;
+  gradedAt: string;
+}
+
+export interface FeedHealthReport {
+  weights: Record<keyof FeedHealthComponents, number>;
+  feeds: FeedHealthRecord[];
+}
+
+// Conditions the service alerts on; silences can target one type or all of them
+export type AlertType = 'stale' | 'slow-updates' | 'rpc-failure';
+
+export interface Alert {
+  type: AlertType;
+  feed: string | null; // null for alerts not tied to a feed
+  message: string;
+  silenced: boolean;
+  silencedBy: string | null; // id of the matching silence or maintenance window
+}
+
+export interface Silence {
+  id: string;
+  feed: string | null; // null matches every feed
+  alertType: AlertType | null; // null matches every alert type
+  startsAt: string;
+  endsAt: string;
+  reason: string;
+}
+
+// Planned maintenance silences every alert while it runs
+export interface MaintenanceWindow {
+  id: string;
+  startsAt: string;
+  endsAt: string;
+  reason: string;
+}
+
+export interface SilenceList {
+  silences: Silence[];
+  maintenanceWindows: MaintenanceWindow[];
+  inMaintenance: boolean;
+}
+
 export interface PriceRefreshResponse {
   refreshed: boolean;
   totalFeeds: number;
@@ -124,4 +244,15 @@ export interface ReservesSnapshot {
     totalValueUSD?: number;
     lastUpdated: string;
   };
-}
\ No newline at end of file
+}
+
+export interface VolatilityData {
+  symbol: string;
+  window: number;
+  observations: number;
+  fromTimestamp: string;
+  toTimestamp: string;
+  meanIntervalSeconds: number;
+  stdDev: number;
+  realizedVolatility: number;
+}
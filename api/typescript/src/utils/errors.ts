@@ -50,6 +50,12 @@ export class RoundNotFoundError extends ApiError {
   }
 }
 
+export class InsufficientHistoryError extends ApiError {
+  constructor(symbol: string, window: number) {
+    super(`Not enough round history to compute volatility for '${symbol}' over ${window} rounds`, 422, 'INSUFFICIENT_HISTORY');
+  }
+}
+
 /**
  * Proof of Reserve errors
  */
@@ -65,6 +71,15 @@ export class ReservesLoadError extends ApiError {
   }
 }
 
+/**
+ * Alerting errors
+ */
+export class SilenceNotFoundError extends ApiError {
+  constructor(id: string) {
+    super(`Silence or maintenance window '${id}' not found`, 404, 'SILENCE_NOT_FOUND');
+  }
+}
+
 /**
  * Blockchain-related errors
  */
@@ -0,0 +1,139 @@
+/**
+ * Fault Injection
+ * Chaos/testing mode that injects RPC timeouts, partial multicall failures,
+ * corrupted return data and storage outages on a schedule of refresh steps.
+ *
+ * CHAOS="timeout@2,partial@3-4,corrupt/5,storage@6"
+ * `@N` fires on refresh N, `@N-M` on refreshes N through M, `/K` on every Kth refresh.
+ */
+
+import { Multicall3 } from '../contracts';
+
+export type FaultType = 'timeout' | 'partial' | 'corrupt' | 'storage';
+
+export const FAULT_TYPES: readonly FaultType[] = ['timeout', 'partial', 'corrupt', 'storage'];
+
+export type ScheduledFault =
+  | { type: FaultType; from: number; to: number }
+  | { type: FaultType; every: number };
+
+export function parseFaultSchedule(spec: string | undefined): ScheduledFault[] {
+  if (!spec) return [];
+
+  return spec.split(',').map(part => part.trim()).filter(Boolean).map(part => {
+    const match = /^([a-z]+)(?:@(\d+)(?:-(\d+))?|\/(\d+))$/.exec(part);
+    if (!match || !FAULT_TYPES.includes(match[1] as FaultType)) {
+      throw new Error(`Invalid fault "${part}": expected one of ${FAULT_TYPES.join(', ')} followed by @N, @N-M or /K`);
+    }
+
+    const [, type, from, to, every] = match;
+    if (every) {
+      return { type: type as FaultType, every: Number(every) };
+    }
+    return { type: type as FaultType, from: Number(from), to: Number(to || from) };
+  });
+}
+
+// Small seeded generator so a schedule hits the same entries on every run
+function seededRandom(seed: number): () => number {
+  let state = seed >>> 0;
+  return () => {
+    state = (state + 0x6D2B79F5) >>> 0;
+    let t = state;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+}
+
+export class FaultInjector {
+  private readonly schedule: ScheduledFault[];
+  private readonly random: () => number;
+  private currentStep = 0;
+
+  constructor(spec: string | undefined, seed = 1, private readonly rate = 0.25) {
+    this.schedule = parseFaultSchedule(spec);
+    this.random = seededRandom(seed);
+  }
+
+  // CHAOS enables injection; CHAOS_SEED and CHAOS_RATE tune which entries are hit
+  static fromEnv(env: NodeJS.ProcessEnv = process.env): FaultInjector {
+    return new FaultInjector(
+      env.CHAOS,
+      env.CHAOS_SEED ? Number(env.CHAOS_SEED) : 1,
+      env.CHAOS_RATE ? Number(env.CHAOS_RATE) : 0.25
+    );
+  }
+
+  get enabled(): boolean {
+    return this.schedule.length > 0;
+  }
+
+  get step(): number {
+    return this.currentStep;
+  }
+
+  public nextStep(): number {
+    return ++this.currentStep;
+  }
+
+  public active(type: FaultType): boolean {
+    const step = this.currentStep;
+    return this.schedule.some(fault => fault.type === type && ('every' in fault
+      ? step % fault.every === 0
+      : step >= fault.from && step <= fault.to));
+  }
+
+  // Simulated RPC timeout, shaped like the error ethers raises
+  public async call<T>(fn: () => Promise<T>): Promise<T> {
+    if (this.active('timeout')) {
+      throw Object.assign(new Error(`Injected RPC timeout (step ${this.currentStep})`), { code: 'TIMEOUT' });
+    }
+    return fn();
+  }
+
+  // Failed calls come back empty; corrupted ones are cut short mid-word
+  public mangleReturnData(returnData: string[], protectedIndexes: number[] = []): string[] {
+    const entries = [...returnData];
+    if (this.active('partial')) {
+      this.pick(entries.length, protectedIndexes).forEach(i => {
+        entries[i] = '0x';
+      });
+    }
+    if (this.active('corrupt')) {
+      this.pick(entries.length, protectedIndexes).forEach(i => {
+        const bytes = (entries[i].length - 2) / 2;
+        entries[i] = entries[i].slice(0, 2 + 2 * Math.floor(this.random() * bytes));
+      });
+    }
+    return entries;
+  }
+
+  // Same as mangleReturnData, applied to a raw aggregate3() result; mangled entries still report success
+  public mangleAggregate3Result(result: string, protectedIndexes: number[] = []): string {
+    if (!this.active('partial') && !this.active('corrupt')) return result;
+
+    const results = Multicall3.decodeAggregate3(result);
+    const returnData = this.mangleReturnData(results.map(entry => entry.returnData), protectedIndexes);
+    return Multicall3.interface.encodeFunctionResult('aggregate3', [
+      results.map((entry, i) => [entry.success, returnData[i]])
+    ]);
+  }
+
+  public write<T>(fn: () => T): T {
+    if (this.active('storage')) {
+      throw new Error(`Injected storage outage (step ${this.currentStep})`);
+    }
+    return fn();
+  }
+
+  // Picks at least one entry so a scheduled fault is never a silent no-op
+  private pick(count: number, protectedIndexes: number[]): Set<number> {
+    const candidates = [...Array(count).keys()].filter(i => !protectedIndexes.includes(i));
+    const chosen = candidates.filter(() => this.random() < this.rate);
+    if (chosen.length === 0 && candidates.length > 0) {
+      chosen.push(candidates[Math.floor(this.random() * candidates.length)]);
+    }
+    return new Set(chosen);
+  }
+}
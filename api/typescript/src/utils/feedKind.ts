@@ -0,0 +1,54 @@
+/**
+ * Feed Kinds
+ * Not every Chainlink feed is an asset price: exchange rates and macro rates can
+ * be zero or negative, indices are unitless levels, and PoR feeds report reserve
+ * amounts. The kind decides which answers are valid and which checks apply.
+ */
+
+import { ethers } from 'ethers';
+import { FeedKind } from '../types';
+
+export interface FeedKindRules {
+  allowNegative: boolean;
+  allowZero: boolean;
+  volatility: boolean; // log-return volatility is only meaningful for positive, market-driven series
+}
+
+export const FEED_KIND_RULES: Record<FeedKind, FeedKindRules> = {
+  price: { allowNegative: false, allowZero: false, volatility: true },
+  rate: { allowNegative: true, allowZero: true, volatility: false },
+  index: { allowNegative: false, allowZero: true, volatility: true },
+  por: { allowNegative: false, allowZero: true, volatility: false }
+};
+
+// An explicit kind (the feed list's optional kind column) wins over the name;
+// otherwise order matters: "PCE Price Index — Percent Change" is a rate, not an index
+export function classifyFeed(name: string, kind?: string): FeedKind {
+  if (kind) {
+    if (!Object.hasOwn(FEED_KIND_RULES, kind)) {
+      throw new Error(`Unknown kind "${kind}" for ${name}, expected one of ${Object.keys(FEED_KIND_RULES).join(', ')}`);
+    }
+    return kind as FeedKind;
+  }
+  if (/reserve/i.test(name)) return 'por';
+  if (/exchange[\s-]rate|percent change|\bnav\b/i.test(name)) return 'rate';
+  if (/\bindex\b|\blevel\b|emergency count/i.test(name)) return 'index';
+  return 'price';
+}
+
+/** Returns a description of why the answer is invalid for this kind, or null if it is acceptable */
+export function validateAnswer(kind: FeedKind, answer: bigint): string | null {
+  const rules = FEED_KIND_RULES[kind];
+  if (answer < 0n && !rules.allowNegative) {
+    return `Negative answer ${answer} is not valid for a ${kind} feed`;
+  }
+  if (answer === 0n && !rules.allowZero) {
+    return `Zero answer is not valid for a ${kind} feed`;
+  }
+  return null;
+}
+
+/** Exact decimal rendering of an answer; unlike Number() it never loses precision */
+export function formatAnswer(answer: bigint, decimals: number): string {
+  return ethers.formatUnits(answer, decimals);
+}
@@ -0,0 +1,50 @@
+/**
+ * Prometheus Metrics
+ * Histogram of observed time between on-chain answer updates, labelled per
+ * chain and per feed, so alerts can fire on shifts in a feed's update
+ * distribution rather than only on point-in-time staleness. The composite
+ * health score and realized volatility are exported alongside as gauges.
+ */
+
+import { Gauge, Histogram, Registry, collectDefaultMetrics } from 'prom-client';
+
+export const metricsRegistry = new Registry();
+collectDefaultMetrics({ register: metricsRegistry });
+
+// From sub-minute deviation updates up to two-day heartbeats
+export const UPDATE_INTERVAL_BUCKETS = [15, 30, 60, 120, 300, 600, 1800, 3600, 7200, 21600, 43200, 86400, 172800];
+
+export const updateIntervalHistogram = new Histogram({
+  name: 'chainlink_feed_update_interval_seconds',
+  help: 'Observed seconds between consecutive on-chain answer updates',
+  labelNames: ['chain', 'feed'] as const,
+  buckets: UPDATE_INTERVAL_BUCKETS,
+  registers: [metricsRegistry]
+});
+
+export function observeUpdateInterval(chain: string, feed: string, seconds: number): void {
+  updateIntervalHistogram.observe({ chain, feed }, seconds);
+}
+
+export const feedHealthGauge = new Gauge({
+  name: 'chainlink_feed_health_score',
+  help: 'Composite feed health score from 0 (untrustworthy) to 100',
+  labelNames: ['chain', 'feed'] as const,
+  registers: [metricsRegistry]
+});
+
+export function setFeedHealthScore(chain: string, feed: string, score: number): void {
+  feedHealthGauge.set({ chain, feed }, score);
+}
+
+// Set whenever the volatility endpoint computes it; windows aren't comparable, so each gets its own series
+export const realizedVolatilityGauge = new Gauge({
+  name: 'chainlink_feed_realized_volatility',
+  help: 'Annualized realized volatility of log returns over the trailing window of rounds',
+  labelNames: ['chain', 'feed', 'window'] as const,
+  registers: [metricsRegistry]
+});
+
+export function setRealizedVolatility(chain: string, feed: string, window: number, volatility: number): void {
+  realizedVolatilityGauge.set({ chain, feed, window: String(window) }, volatility);
+}
@@ -0,0 +1,129 @@
+/**
+ * Multicall3 aggregate() / aggregate3() Result Decoding
+ * Walks the ABI-encoded (uint256, bytes[]) or (bool, bytes)[] result in place, so each
+ * refresh cycle decodes every feed without building intermediate Result objects or byte slices
+ */
+
+import { ethers } from 'ethers';
+
+const WORD = 32;
+// Error(string), the revert Solidity raises for require() and revert("...")
+const ERROR_STRING_SELECTOR = 0x08c379a0;
+const TEXT_DECODER = new TextDecoder();
+
+/**
+ * Read-only cursor over a single returnData entry.
+ * One instance is re-pointed at each entry in turn rather than allocated per feed.
+ */
+export class ReturnDataView {
+  private readonly view: DataView;
+  private base = 0;
+  private end = 0;
+
+  constructor(bytes: Uint8Array) {
+    this.view = new DataView(bytes.buffer, bytes.byteOffset, bytes.byteLength);
+  }
+
+  /** Length in bytes of the entry currently pointed at */
+  get length(): number {
+    return this.end - this.base;
+  }
+
+  /** Read the 32-byte word at an absolute offset as an unsigned integer */
+  wordAt(offset: number): bigint {
+    if (offset + WORD > this.view.byteLength) {
+      throw new Error(`Return data too short: need ${offset + WORD} bytes, have ${this.view.byteLength}`);
+    }
+    return (this.view.getBigUint64(offset) << 192n) |
+      (this.view.getBigUint64(offset + 8) << 128n) |
+      (this.view.getBigUint64(offset + 16) << 64n) |
+      this.view.getBigUint64(offset + 24);
+  }
+
+  /** Read an offset or length word at an absolute offset */
+  sizeAt(offset: number): number {
+    return Number(this.wordAt(offset));
+  }
+
+  /** Point the cursor at the dynamic bytes value encoded at `offset` */
+  seek(offset: number): void {
+    const length = this.sizeAt(offset);
+    this.base = offset + WORD;
+    this.end = this.base + length;
+  }
+
+  /** Read the `index`th word of the current entry as uint256 */
+  uint(index: number): bigint {
+    const offset = this.base + index * WORD;
+    if (offset + WORD > this.end) {
+      throw new Error(`Return data too short: expected at least ${(index + 1) * WORD} bytes, got ${this.length}`);
+    }
+    return this.wordAt(offset);
+  }
+
+  /** Read the `index`th word of the current entry as int256 */
+  int(index: number): bigint {
+    return BigInt.asIntN(256, this.uint(index));
+  }
+
+  /** The reason of an Error(string) revert held in the current entry, or null for any other data */
+  revertReason(): string | null {
+    if (this.length < 4 + 2 * WORD || this.view.getUint32(this.base) !== ERROR_STRING_SELECTOR) {
+      return null;
+    }
+    const start = this.base + 4;
+    const offset = start + this.sizeAt(start);
+    const length = this.sizeAt(offset);
+    if (offset + WORD + length > this.end) {
+      return null;
+    }
+    return TEXT_DECODER.decode(new Uint8Array(this.view.buffer, this.view.byteOffset + offset + WORD, length));
+  }
+}
+
+/**
+ * Decode the raw result of Multicall3.aggregate(), visiting each returnData entry in order.
+ * The view passed to `visit` is only valid for the duration of that call.
+ * Returns the block number the aggregate call executed at.
+ */
+export function decodeAggregateResult(
+  result: string,
+  visit: (index: number, data: ReturnDataView) => void
+): bigint {
+  const view = new ReturnDataView(ethers.getBytes(result));
+
+  const blockNumber = view.wordAt(0);
+  const arrayStart = view.sizeAt(WORD);
+  const count = view.sizeAt(arrayStart);
+  const heads = arrayStart + WORD;
+
+  for (let i = 0; i < count; i++) {
+    view.seek(heads + view.sizeAt(heads + i * WORD));
+    visit(i, view);
+  }
+
+  return blockNumber;
+}
+
+/**
+ * Decode the raw result of Multicall3.aggregate3(), visiting each (success, returnData) entry in order.
+ * A failed entry holds the call's revert data. The view passed to `visit` is only valid for the
+ * duration of that call.
+ */
+export function decodeAggregate3Result(
+  result: string,
+  visit: (index: number, data: ReturnDataView, success: boolean) => void
+): void {
+  const view = new ReturnDataView(ethers.getBytes(result));
+
+  const arrayStart = view.sizeAt(0);
+  const count = view.sizeAt(arrayStart);
+  const heads = arrayStart + WORD;
+
+  for (let i = 0; i < count; i++) {
+    const entry = heads + view.sizeAt(heads + i * WORD);
+    const success = view.wordAt(entry) !== 0n;
+    view.seek(entry + view.sizeAt(entry + WORD));
+    visit(i, view, success);
+  }
+}
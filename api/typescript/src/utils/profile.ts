@@ -0,0 +1,120 @@
+/**
+ * Deployment Profiles
+ * Named profiles in profiles.json bundle the chain, RPC, feed files and alerting
+ * settings of one deployment. Applying a profile fills the environment variables
+ * the service reads, leaving any that are already set alone. Relative paths are
+ * taken relative to profiles.json.
+ *
+ * PROFILE=prod-avalanche npm start   (or: npm start -- --profile prod-avalanche)
+ */
+
+import fs from 'fs';
+import path from 'path';
+
+const PROFILE_SETTINGS: Record<string, string> = {
+  chain: 'CHAIN',
+  rpcUrl: 'RPC_URL',
+  feedsFile: 'FEEDS_FILE',
+  customFeedsFile: 'CUSTOM_FEEDS',
+  metadataFile: 'FEED_METADATA'
+};
+
+const SINK_SETTINGS: Record<string, Record<string, string>> = {
+  file: { dir: 'SNAPSHOT_DIR' },
+  binary: { file: 'SNAPSHOT_LOG' }
+};
+
+const ALERTING_SETTINGS: Record<string, string> = {
+  silencesFile: 'SILENCES_FILE',
+  updateTolerance: 'UPDATE_TOLERANCE'
+};
+
+const PATH_VARIABLES = ['FEEDS_FILE', 'CUSTOM_FEEDS', 'FEED_METADATA', 'SNAPSHOT_DIR', 'SNAPSHOT_LOG', 'SILENCES_FILE'];
+
+export function defaultProfilesFile(): string {
+  return process.env.NODE_ENV === 'production'
+    ? path.join('/app', 'profiles.json')
+    : path.join(__dirname, '../../../profiles.json');
+}
+
+function mapSettings(name: string, section: string, settings: Record<string, unknown>, mapping: Record<string, string>, vars: Record<string, string>): void {
+  for (const [key, value] of Object.entries(settings)) {
+    if (!(key in mapping)) {
+      throw new Error(`Profile ${name}: unknown ${section} setting "${key}"`);
+    }
+    vars[mapping[key]] = String(value);
+  }
+}
+
+// Sinks marked verify: true are listed in VERIFY_WRITES; "env" passes other variables through
+export function profileEnv(name: string, profile: Record<string, any>): Record<string, string> {
+  const vars: Record<string, string> = {};
+  const verified: string[] = [];
+
+  for (const [key, value] of Object.entries(profile)) {
+    if (key in PROFILE_SETTINGS) {
+      vars[PROFILE_SETTINGS[key]] = String(value);
+    } else if (key === 'sinks') {
+      for (const [sink, settings] of Object.entries(value as Record<string, Record<string, unknown>>)) {
+        if (!(sink in SINK_SETTINGS)) {
+          throw new Error(`Profile ${name}: unknown sink "${sink}", expected one of ${Object.keys(SINK_SETTINGS).join(', ')}`);
+        }
+        const { verify, ...rest } = settings;
+        mapSettings(name, `${sink} sink`, rest, SINK_SETTINGS[sink], vars);
+        if (verify) verified.push(sink);
+      }
+    } else if (key === 'alerting') {
+      mapSettings(name, 'alerting', value, ALERTING_SETTINGS, vars);
+    } else if (key === 'env') {
+      for (const [variable, setting] of Object.entries(value as Record<string, unknown>)) {
+        vars[variable] = String(setting);
+      }
+    } else {
+      throw new Error(`Profile ${name}: unknown setting "${key}"`);
+    }
+  }
+
+  if (verified.length > 0) {
+    vars.VERIFY_WRITES = verified.join(',');
+  }
+  return vars;
+}
+
+export function loadProfile(name: string, file: string = process.env.PROFILES_FILE || defaultProfilesFile()): Record<string, string> {
+  const { profiles = {} } = JSON.parse(fs.readFileSync(file, 'utf8'));
+  if (!Object.prototype.hasOwnProperty.call(profiles, name)) {
+    const available = Object.keys(profiles).join(', ') || 'none';
+    throw new Error(`Unknown profile "${name}" in ${file} (available: ${available})`);
+  }
+
+  const vars = profileEnv(name, profiles[name]);
+  for (const variable of PATH_VARIABLES) {
+    if (vars[variable] !== undefined) {
+      vars[variable] = path.resolve(path.dirname(file), vars[variable]);
+    }
+  }
+  return vars;
+}
+
+// --profile on the command line wins over PROFILE
+export function selectedProfile(argv: string[] = process.argv.slice(2), env: NodeJS.ProcessEnv = process.env): string | undefined {
+  const index = argv.indexOf('--profile');
+  if (index !== -1) return argv[index + 1];
+  const inline = argv.find(arg => arg.startsWith('--profile='));
+  return inline ? inline.slice('--profile='.length) : env.PROFILE;
+}
+
+/**
+ * Applies the selected profile to env without overriding variables already set.
+ * Returns the profile name, or undefined when none was selected.
+ */
+export function applyProfile(name: string | undefined = selectedProfile(), env: NodeJS.ProcessEnv = process.env): string | undefined {
+  if (!name) return undefined;
+
+  for (const [variable, value] of Object.entries(loadProfile(name, env.PROFILES_FILE || defaultProfilesFile()))) {
+    if (env[variable] === undefined) {
+      env[variable] = value;
+    }
+  }
+  return name;
+}
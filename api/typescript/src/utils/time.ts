@@ -0,0 +1,76 @@
+/**
+ * UTC Time Helpers
+ * Timestamps are produced in UTC as RFC 3339 strings with an explicit offset.
+ * Report endpoints can re-express them in a named IANA time zone on request.
+ */
+
+// Response fields holding timestamps that the tz option converts
+const TIMESTAMP_FIELDS = new Set([
+  'timestamp', 'updatedAt', 'blockTimestamp', 'lastUpdated', 'lastRefresh',
+  'fromTimestamp', 'toTimestamp', 'since', 'lastSuccess', 'lastFailure', 'lastUpdate', 'gradedAt'
+]);
+
+export function isValidTimeZone(value: unknown): value is string {
+  if (typeof value !== 'string' || value.length === 0) return false;
+  try {
+    new Intl.DateTimeFormat('en-US', { timeZone: value });
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+const pad = (value: number, length = 2) => String(value).padStart(length, '0');
+
+/** Re-express an RFC 3339 timestamp in timeZone, e.g. 2025-07-20T20:59:55.000-04:00 */
+export function toTimeZone(iso: string, timeZone: string): string {
+  const date = new Date(iso);
+  if (Number.isNaN(date.getTime())) return iso;
+
+  const parts: Record<string, number> = {};
+  new Intl.DateTimeFormat('en-US', {
+    timeZone,
+    hourCycle: 'h23',
+    year: 'numeric',
+    month: '2-digit',
+    day: '2-digit',
+    hour: '2-digit',
+    minute: '2-digit',
+    second: '2-digit'
+  }).formatToParts(date).forEach(part => {
+    if (part.type !== 'literal') parts[part.type] = Number(part.value);
+  });
+
+  const year = parts.year ?? 0;
+  const month = parts.month ?? 1;
+  const day = parts.day ?? 1;
+  const hour = parts.hour ?? 0;
+  const minute = parts.minute ?? 0;
+  const second = parts.second ?? 0;
+
+  // Offset is the wall-clock reading minus the instant, both at whole-second precision
+  const wallClock = Date.UTC(year, month - 1, day, hour, minute, second);
+  const offsetMinutes = Math.round((wallClock - (date.getTime() - date.getUTCMilliseconds())) / 60000);
+  const sign = offsetMinutes < 0 ? '-' : '+';
+  const offset = `${sign}${pad(Math.floor(Math.abs(offsetMinutes) / 60))}:${pad(Math.abs(offsetMinutes) % 60)}`;
+
+  return `${pad(year, 4)}-${pad(month)}-${pad(day)}T${pad(hour)}:${pad(minute)}:${pad(second)}.${pad(date.getUTCMilliseconds(), 3)}${offset}`;
+}
+
+/** Convert every known timestamp field in a response payload to timeZone */
+export function localizeTimestamps<T>(data: T, timeZone: string | undefined): T {
+  if (!timeZone) return data;
+
+  const walk = (value: unknown, key?: string): unknown => {
+    if (Array.isArray(value)) return value.map(item => walk(item));
+    if (value && typeof value === 'object') {
+      return Object.fromEntries(Object.entries(value).map(([k, v]) => [k, walk(v, k)]));
+    }
+    if (typeof value === 'string' && key && TIMESTAMP_FIELDS.has(key)) {
+      return toTimeZone(value, timeZone);
+    }
+    return value;
+  };
+
+  return walk(data) as T;
+}
@@ -0,0 +1,56 @@
+/**
+ * Realized Volatility Calculations
+ * Computes rolling volatility from a series of Chainlink round answers
+ */
+
+export interface PricePoint {
+  price: number;
+  timestamp: number; // unix seconds
+}
+
+export interface VolatilityResult {
+  observations: number;
+  meanIntervalSeconds: number;
+  stdDev: number;
+  realizedVolatility: number;
+}
+
+const SECONDS_PER_YEAR = 365 * 24 * 60 * 60;
+
+/**
+ * Compute realized volatility from consecutive log returns.
+ * Rounds report at irregular intervals, so the per-round standard deviation is
+ * annualized using the mean time between rounds in the window.
+ * Returns null when fewer than two usable returns are available.
+ */
+export function computeRealizedVolatility(points: PricePoint[]): VolatilityResult | null {
+  const sorted = [...points].sort((a, b) => a.timestamp - b.timestamp);
+
+  const returns: number[] = [];
+  for (let i = 1; i < sorted.length; i++) {
+    const prev = sorted[i - 1];
+    const curr = sorted[i];
+    if (!prev || !curr) continue;
+    // Log returns are undefined for non-positive answers
+    if (prev.price <= 0 || curr.price <= 0) continue;
+    returns.push(Math.log(curr.price / prev.price));
+  }
+
+  if (returns.length < 2) return null;
+
+  const first = sorted[0];
+  const last = sorted[sorted.length - 1];
+  if (!first || !last || last.timestamp <= first.timestamp) return null;
+
+  const mean = returns.reduce((sum, r) => sum + r, 0) / returns.length;
+  const variance = returns.reduce((sum, r) => sum + (r - mean) ** 2, 0) / (returns.length - 1);
+  const stdDev = Math.sqrt(variance);
+  const meanIntervalSeconds = (last.timestamp - first.timestamp) / (sorted.length - 1);
+
+  return {
+    observations: returns.length,
+    meanIntervalSeconds,
+    stdDev,
+    realizedVolatility: stdDev * Math.sqrt(SECONDS_PER_YEAR / meanIntervalSeconds)
+  };
+}
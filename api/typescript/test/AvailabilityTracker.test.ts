@@ -0,0 +1,82 @@
+// Rolling availability per feed and per RPC origin
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { AvailabilityTracker, rpcKey } from '../src/services/AvailabilityTracker';
+
+const AT = Date.parse('2025-07-21T00:00:00Z');
+const MINUTE = 60 * 1000;
+
+describe('AvailabilityTracker', () => {
+  test('keeps only the newest samples in the window', () => {
+    const tracker = new AvailabilityTracker(3, undefined);
+    tracker.recordFeed('BTC / USD', false, AT);
+    tracker.recordFeed('BTC / USD', true, AT + MINUTE);
+    tracker.recordFeed('BTC / USD', false, AT + 2 * MINUTE);
+    tracker.recordFeed('BTC / USD', true, AT + 3 * MINUTE);
+
+    const [record] = tracker.getReport().feeds;
+    expect(record.successes).toBe(2);
+    expect(record.failures).toBe(1);
+    expect(record.availability).toBeCloseTo(2 / 3);
+    expect(record.since).toBe(new Date(AT + MINUTE).toISOString());
+    expect(record.lastFailure).toBe(new Date(AT + 2 * MINUTE).toISOString());
+  });
+
+  test('ranks the least available first and shares out the failures', () => {
+    const tracker = new AvailabilityTracker(10, undefined);
+    tracker.recordFeed('BTC / USD', true, AT);
+    tracker.recordFeed('ETH / USD', false, AT);
+    tracker.recordFeed('ETH / USD', true, AT + MINUTE);
+    tracker.recordFeed('AVAX / USD', false, AT);
+
+    const feeds = tracker.getReport().feeds;
+    expect(feeds.map(record => record.key)).toEqual(['AVAX / USD', 'ETH / USD', 'BTC / USD']);
+    expect(feeds.map(record => record.failureShare)).toEqual([0.5, 0.5, 0]);
+  });
+
+  test('reduces RPC URLs to their origin', () => {
+    expect(rpcKey('https://avax-mainnet.g.alchemy.com/v2/SECRET')).toBe('https://avax-mainnet.g.alchemy.com');
+    expect(rpcKey('https://rpc.example.com:8545/ext/bc/C/rpc?apikey=SECRET')).toBe('https://rpc.example.com:8545');
+    expect(rpcKey('not a url')).toBe('invalid-url');
+
+    const tracker = new AvailabilityTracker(10, undefined);
+    tracker.recordRpc('https://avax-mainnet.g.alchemy.com/v2/SECRET', true, AT);
+    tracker.recordRpc('https://avax-mainnet.g.alchemy.com/v2/OTHER?key=SECRET', false, AT + MINUTE);
+
+    const report = tracker.getReport();
+    expect(report.rpcs.map(record => record.key)).toEqual(['https://avax-mainnet.g.alchemy.com']);
+    expect(report.rpcs[0].failures).toBe(1);
+    expect(JSON.stringify(report)).not.toContain('SECRET');
+  });
+
+  test('saved samples survive a restart', () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'availability-'));
+    const file = path.join(dir, 'availability.json');
+    try {
+      const tracker = new AvailabilityTracker(10, file);
+      tracker.recordFeed('BTC / USD', true, AT);
+      tracker.recordFeed('BTC / USD', false, AT + MINUTE);
+      tracker.recordRpc('https://api.avax.network/ext/bc/C/rpc', true, AT);
+      tracker.save();
+
+      expect(fs.readFileSync(file, 'utf8')).not.toContain('/ext/bc/C/rpc');
+      expect(new AvailabilityTracker(10, file).getReport()).toEqual(tracker.getReport());
+    } finally {
+      fs.rmSync(dir, { recursive: true, force: true });
+    }
+  });
+
+  test('an unreadable file starts empty', () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'availability-'));
+    const file = path.join(dir, 'availability.json');
+    fs.writeFileSync(file, '{not json');
+    const warn = jest.spyOn(console, 'warn').mockImplementation(() => undefined);
+    try {
+      expect(new AvailabilityTracker(10, file).getReport()).toEqual({ window: 10, rpcs: [], feeds: [] });
+    } finally {
+      warn.mockRestore();
+      fs.rmSync(dir, { recursive: true, force: true });
+    }
+  });
+});
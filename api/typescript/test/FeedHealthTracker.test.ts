@@ -0,0 +1,95 @@
+// Feed health scoring, grade thresholds and staleness decay
+import { FeedHealthTracker } from '../src/services/FeedHealthTracker';
+import { AvailabilityReport, UpdateFrequencyReport } from '../src/types';
+
+const HEARTBEAT = 3600;
+const NO_FREQUENCY: UpdateFrequencyReport = { tolerance: 1.5, windows: [], feeds: [] };
+const NO_AVAILABILITY: AvailabilityReport = { window: 10, rpcs: [], feeds: [] };
+
+describe('FeedHealthTracker', () => {
+  let tracker: FeedHealthTracker;
+
+  // Staleness component for BTC / USD after a refresh at `now`
+  const stalenessAt = (now: number) => {
+    tracker.refresh(NO_FREQUENCY, NO_AVAILABILITY, now);
+    return tracker.get('BTC / USD')?.components.staleness;
+  };
+
+  beforeEach(() => {
+    tracker = new FeedHealthTracker(3600, 10, undefined);
+  });
+
+  describe('score', () => {
+    test('weights only the measured components', () => {
+      expect(FeedHealthTracker.score({ staleness: 1, regularity: 1, decoding: 1, deviation: 1 })).toBe(100);
+      expect(FeedHealthTracker.score({ staleness: 1, regularity: null, decoding: null, deviation: null })).toBe(100);
+      expect(FeedHealthTracker.score({ staleness: 1, regularity: 0, decoding: null, deviation: null })).toBe(58.3);
+    });
+
+    test('nothing measured scores 0', () => {
+      expect(FeedHealthTracker.score({ staleness: null, regularity: null, decoding: null, deviation: null })).toBe(0);
+    });
+  });
+
+  describe('grade', () => {
+    test.each([
+      [100, 'A'], [90, 'A'], [89.9, 'B'], [75, 'B'], [74.9, 'C'],
+      [50, 'C'], [49.9, 'D'], [25, 'D'], [24.9, 'F'], [0, 'F']
+    ])('%s grades %s', (score, grade) => {
+      expect(FeedHealthTracker.grade(score)).toBe(grade);
+    });
+  });
+
+  describe('staleness', () => {
+    test('scores 1 within a heartbeat, falling to 0 at two', () => {
+      tracker.observe('BTC / USD', HEARTBEAT, 0.5, 100, 0);
+      tracker.observe('ETH / USD', HEARTBEAT, 0.5, 100, -0.5 * HEARTBEAT);
+      tracker.observe('AVAX / USD', HEARTBEAT, 0.5, 100, -2 * HEARTBEAT);
+      tracker.refresh(NO_FREQUENCY, NO_AVAILABILITY, HEARTBEAT);
+
+      expect(tracker.get('BTC / USD')?.components.staleness).toBe(1);
+      expect(tracker.get('ETH / USD')?.components.staleness).toBeCloseTo(0.5);
+      expect(tracker.get('AVAX / USD')?.components.staleness).toBe(0);
+    });
+
+    test('a sample one half-life later moves the average halfway', () => {
+      tracker.observe('BTC / USD', HEARTBEAT, 0.5, 100, 0);
+      expect(stalenessAt(HEARTBEAT)).toBe(1);
+      // Age is now two heartbeats; the mean ratio moves from 1 to 1.5
+      expect(stalenessAt(2 * HEARTBEAT)).toBeCloseTo(0.5);
+    });
+
+    test('refreshing again at the same time changes nothing', () => {
+      tracker.observe('BTC / USD', HEARTBEAT, 0.5, 100, 0);
+      expect(stalenessAt(1.5 * HEARTBEAT)).toBeCloseTo(0.5);
+      expect(stalenessAt(1.5 * HEARTBEAT)).toBeCloseTo(0.5);
+      expect(stalenessAt(HEARTBEAT)).toBeCloseTo(0.5);
+    });
+
+    test('feeds without a heartbeat are not scored on staleness', () => {
+      tracker.observe('BTC / USD', null, 0.5, 100, 0);
+      expect(stalenessAt(10 * HEARTBEAT)).toBeNull();
+    });
+  });
+
+  describe('deviation', () => {
+    test('a move of exactly the anomaly limit is not anomalous', () => {
+      tracker.observe('BTC / USD', HEARTBEAT, 0.5, 100, 0);
+      tracker.observe('BTC / USD', HEARTBEAT, 0.5, 105, 60);
+      tracker.observe('BTC / USD', HEARTBEAT, 0.5, 111.3, 120);
+      tracker.refresh(NO_FREQUENCY, NO_AVAILABILITY, 120);
+
+      expect(tracker.get('BTC / USD')?.anomalies).toBe(1);
+      expect(tracker.get('BTC / USD')?.components.deviation).toBe(0.5);
+    });
+
+    test('small thresholds still allow a 1% move', () => {
+      tracker.observe('BTC / USD', HEARTBEAT, 0.01, 100, 0);
+      tracker.observe('BTC / USD', HEARTBEAT, 0.01, 101, 60);
+      tracker.observe('BTC / USD', HEARTBEAT, 0.01, 103, 120);
+      tracker.refresh(NO_FREQUENCY, NO_AVAILABILITY, 120);
+
+      expect(tracker.get('BTC / USD')?.anomalies).toBe(1);
+    });
+  });
+});
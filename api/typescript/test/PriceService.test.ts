@@ -0,0 +1,94 @@
+// Answer age measured against the sampled block, and round history with gaps in it
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { ethers } from 'ethers';
+import { MULTICALL3_ABI } from '../src/contracts/generated/Multicall3';
+import { AGGREGATOR_V3_INTERFACE_ABI } from '../src/contracts/generated/AggregatorV3Interface';
+import { Multicall3 } from '../src/contracts/Multicall3';
+import { PriceService } from '../src/services/PriceService';
+
+const multicall = new ethers.Interface(MULTICALL3_ABI);
+const aggregator = new ethers.Interface(AGGREGATOR_V3_INTERFACE_ABI);
+const uint = (value: number) => ethers.AbiCoder.defaultAbiCoder().encode(['uint256'], [value]);
+
+const BLOCK_TIMESTAMP = 1753056000;
+const ENV = ['FEEDS_FILE', 'CUSTOM_FEEDS', 'SILENCES_FILE', 'CHAOS'];
+
+describe('PriceService', () => {
+  let dir: string;
+  const saved: Record<string, string | undefined> = {};
+
+  beforeAll(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'price-service-'));
+    ENV.forEach(key => { saved[key] = process.env[key]; });
+    process.env.FEEDS_FILE = path.join(dir, 'feeds.csv');
+    process.env.CUSTOM_FEEDS = path.join(dir, 'missing.json');
+    process.env.SILENCES_FILE = path.join(dir, 'silences.json');
+    delete process.env.CHAOS;
+    fs.writeFileSync(process.env.FEEDS_FILE, [
+      'name,contract_address,proxy_address,deviation_threshold,heartbeat,decimals,asset_class,product_name,ens,path,base_asset,quote_asset',
+      'BTC / USD,0x0000000000000000000000000000000000000001,0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743,0.1,86400,8,Crypto,,,,BTC,USD',
+      'ETH / USD,0x0000000000000000000000000000000000000002,0x976B3D034E162d8bD72D6b9C989d545b839003b0,0.1,86400,8,Crypto,,,,ETH,USD'
+    ].join('\n'));
+  });
+
+  afterAll(() => {
+    ENV.forEach(key => {
+      if (saved[key] === undefined) delete process.env[key]; else process.env[key] = saved[key];
+    });
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('ages answers against the block timestamp, never below zero', async () => {
+    const service = new PriceService();
+    await (service as any).loadFeeds();
+
+    const round = (answer: bigint, updatedAt: number) =>
+      aggregator.encodeFunctionResult('latestRoundData', [7n, answer, updatedAt, updatedAt, 7n]);
+    const result = multicall.encodeFunctionResult('aggregate3', [[
+      [true, uint(1000)],
+      [true, uint(BLOCK_TIMESTAMP)],
+      [true, round(11850012345678n, BLOCK_TIMESTAMP - 7200)],
+      // An answer stamped after the block it was read at
+      [true, round(370012345678n, BLOCK_TIMESTAMP + 30)]
+    ]]);
+    (service as any).multicall = { call: async () => result };
+
+    const { successful } = await service.refreshPrices();
+
+    expect(successful).toBe(2);
+    expect(service.getPrice('BTCUSD')?.blockTimestamp).toBe(new Date(BLOCK_TIMESTAMP * 1000).toISOString());
+    expect(service.getPrice('BTCUSD')?.ageAtBlock).toBe(7200);
+    expect(service.getPrice('ETHUSD')?.ageAtBlock).toBe(0);
+    expect(service.getPrice('ETHUSD')?.updatedAt).toBe(new Date((BLOCK_TIMESTAMP + 30) * 1000).toISOString());
+  });
+
+  test('skips rounds missing from the history instead of failing the series', async () => {
+    const service = new PriceService();
+    await (service as any).loadFeeds();
+
+    // Round 5 of phase 2; round 2 reads as zeros and round 3 reverts
+    const phase = 2n << 64n;
+    const round = (id: bigint, answer: bigint, updatedAt: number) =>
+      [phase | id, answer, updatedAt, updatedAt, phase | id];
+    (service as any).provider = {
+      call: async () => aggregator.encodeFunctionResult('latestRoundData', round(5n, 120n, BLOCK_TIMESTAMP))
+    };
+    const result = multicall.encodeFunctionResult('aggregate3', [[
+      [true, aggregator.encodeFunctionResult('getRoundData', round(1n, 100n, BLOCK_TIMESTAMP - 240))],
+      [true, aggregator.encodeFunctionResult('getRoundData', [0n, 0n, 0n, 0n, 0n])],
+      [false, '0x'],
+      [true, aggregator.encodeFunctionResult('getRoundData', round(4n, 110n, BLOCK_TIMESTAMP - 60))],
+      [true, aggregator.encodeFunctionResult('getRoundData', round(5n, 120n, BLOCK_TIMESTAMP))]
+    ]]);
+    (service as any).multicall = new Multicall3({ call: async () => result } as any);
+
+    const rounds = await service.getRoundHistory('BTCUSD', 5);
+    expect(rounds?.map(entry => entry.roundId)).toEqual([1n, 4n, 5n].map(id => (phase | id).toString()));
+
+    const volatility = await service.getVolatility('BTCUSD', 5);
+    expect(volatility?.observations).toBe(2);
+    expect(volatility?.meanIntervalSeconds).toBe(120);
+  });
+});
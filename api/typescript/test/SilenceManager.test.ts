@@ -0,0 +1,128 @@
+// Silence matching, range parsing and config file loading
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { SilenceManager } from '../src/services/SilenceManager';
+
+const AT = Date.parse('2025-07-21T00:00:00Z');
+const MINUTE = 60_000;
+
+describe('SilenceManager', () => {
+  let manager: SilenceManager;
+
+  beforeEach(() => {
+    manager = new SilenceManager(undefined);
+  });
+
+  describe('range parsing', () => {
+    test('durations start now', () => {
+      const silence = manager.addSilence({ durationMinutes: 30 }, AT);
+      expect(silence).toMatchObject({ startsAt: '2025-07-21T00:00:00.000Z', endsAt: '2025-07-21T00:30:00.000Z' });
+    });
+
+    test('offsets are normalised to UTC', () => {
+      const silence = manager.addSilence({ startsAt: '2025-07-21T02:00:00+02:00', endsAt: '2025-07-21T01:00:00Z' }, AT);
+      expect(silence).toMatchObject({ startsAt: '2025-07-21T00:00:00.000Z', endsAt: '2025-07-21T01:00:00.000Z' });
+    });
+
+    test.each([
+      [{ endsAt: '2025-07-21T01:00:00' }, 'endsAt: must be an RFC 3339'],
+      [{ startsAt: 'tomorrow', durationMinutes: 5 }, 'startsAt: must be an RFC 3339'],
+      [{}, 'endsAt: either endsAt or a positive durationMinutes'],
+      [{ durationMinutes: 0 }, 'endsAt: either endsAt or a positive durationMinutes'],
+      [{ startsAt: '2025-07-21T01:00:00Z', endsAt: '2025-07-21T01:00:00Z' }, 'endsAt: must be after startsAt']
+    ])('rejects %j', (input, message) => {
+      expect(() => manager.addSilence(input, AT)).toThrow(message);
+    });
+  });
+
+  describe('match and apply', () => {
+    test('matches the feed case-insensitively and by alert type', () => {
+      const silence = manager.addSilence({ feed: 'btc / usd', alertType: 'stale', durationMinutes: 10 }, AT);
+
+      expect(manager.match('stale', 'BTC / USD', AT)).toBe(silence.id);
+      expect(manager.match('slow-updates', 'BTC / USD', AT)).toBeNull();
+      expect(manager.match('stale', 'ETH / USD', AT)).toBeNull();
+      expect(manager.match('stale', null, AT)).toBeNull();
+    });
+
+    test('windows include their start and exclude their end', () => {
+      const silence = manager.addSilence({ startsAt: '2025-07-21T00:10:00Z', durationMinutes: 10 }, AT);
+
+      expect(manager.match('rpc-failure', null, AT + 10 * MINUTE - 1)).toBeNull();
+      expect(manager.match('rpc-failure', null, AT + 10 * MINUTE)).toBe(silence.id);
+      expect(manager.match('rpc-failure', null, AT + 20 * MINUTE)).toBeNull();
+    });
+
+    test('maintenance windows win over silences', () => {
+      manager.addSilence({ alertType: 'stale', durationMinutes: 10 }, AT);
+      const window = manager.addMaintenanceWindow({ durationMinutes: 5 }, AT);
+
+      expect(manager.match('stale', 'BTC / USD', AT)).toBe(window.id);
+      expect(manager.list(AT).inMaintenance).toBe(true);
+    });
+
+    test('apply marks the alert with what silenced it', () => {
+      const silence = manager.addSilence({ feed: 'BTC / USD', durationMinutes: 10 }, AT);
+      const alert = { type: 'stale' as const, feed: 'BTC / USD', message: 'stale' };
+
+      expect(manager.apply(alert, AT)).toEqual({ ...alert, silenced: true, silencedBy: silence.id });
+      expect(manager.apply({ ...alert, feed: 'ETH / USD' }, AT)).toMatchObject({ silenced: false, silencedBy: null });
+    });
+
+    test('rejects unknown alert types', () => {
+      expect(() => manager.addSilence({ alertType: 'depeg', durationMinutes: 10 }, AT)).toThrow('alertType');
+    });
+  });
+
+  describe('loading the config file', () => {
+    let dir: string;
+    let file: string;
+
+    beforeEach(() => {
+      dir = fs.mkdtempSync(path.join(os.tmpdir(), 'silences-'));
+      file = path.join(dir, 'silences.json');
+      jest.spyOn(console, 'warn').mockImplementation(() => undefined);
+    });
+
+    afterEach(() => {
+      jest.restoreAllMocks();
+      fs.rmSync(dir, { recursive: true, force: true });
+    });
+
+    test('skips bad and expired entries one at a time', () => {
+      fs.writeFileSync(file, JSON.stringify({
+        silences: [
+          { id: 'expired', endsAt: '2000-01-01T00:00:00Z' },
+          { id: 'bad-type', alertType: 'depeg', durationMinutes: 10 },
+          { id: 'no-end' },
+          'not an entry',
+          { id: 'kept', feed: 'BTC / USD', endsAt: '2999-01-01T00:00:00Z' }
+        ],
+        maintenanceWindows: [{ id: 'window', durationMinutes: 60 }]
+      }));
+      const list = new SilenceManager(file).list();
+
+      expect(list.silences.map(s => s.id)).toEqual(['kept']);
+      expect(list.maintenanceWindows.map(w => w.id)).toEqual(['window']);
+      expect(console.warn).toHaveBeenCalledTimes(3);
+    });
+
+    test('durations are pinned in the file so restarts keep the window', () => {
+      fs.writeFileSync(file, JSON.stringify({ maintenanceWindows: [{ id: 'window', durationMinutes: 60 }] }));
+      const [first] = new SilenceManager(file).list().maintenanceWindows;
+
+      const [stored] = JSON.parse(fs.readFileSync(file, 'utf8')).maintenanceWindows;
+      expect(stored).toEqual(first);
+      expect(stored).not.toHaveProperty('durationMinutes');
+      expect(new SilenceManager(file).list().maintenanceWindows).toEqual([first]);
+    });
+
+    test('an unreadable file is left alone', () => {
+      fs.writeFileSync(file, '{not json');
+
+      expect(new SilenceManager(file).list().silences).toEqual([]);
+      expect(fs.readFileSync(file, 'utf8')).toBe('{not json');
+    });
+  });
+});
@@ -0,0 +1,50 @@
+// Update intervals derived from observed rounds
+import fs from 'fs';
+import os from 'os';
+import path from 'path';
+import { UpdateFrequencyTracker } from '../src/services/UpdateFrequencyTracker';
+
+const PHASE = 3n << 64n;
+
+describe('UpdateFrequencyTracker', () => {
+  let tracker: UpdateFrequencyTracker;
+  const observe = (updatedAt: number, roundId: bigint) =>
+    tracker.recordObservation('BTC / USD', 3600, 0.5, updatedAt, roundId, updatedAt);
+
+  beforeEach(() => {
+    tracker = new UpdateFrequencyTracker(1.5, undefined);
+  });
+
+  test('repeated rounds are not updates', () => {
+    expect(observe(1000, PHASE + 1n)).toBeNull();
+    expect(observe(1000, PHASE + 1n)).toBeNull();
+    expect(observe(2000, PHASE + 2n)).toBe(1000);
+  });
+
+  test('missed rounds split the gap', () => {
+    observe(1000, PHASE + 1n);
+    expect(observe(4000, PHASE + 4n)).toBe(1000);
+  });
+
+  test('phase changes are not intervals', () => {
+    observe(1000, PHASE + 1n);
+    expect(observe(2000, (4n << 64n) + 1n)).toBeNull();
+    expect(observe(3000, (4n << 64n) + 2n)).toBe(1000);
+  });
+
+  test('the first observation after a restart is skipped', () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'update-frequency-'));
+    const file = path.join(dir, 'update_frequency.json');
+    try {
+      tracker = new UpdateFrequencyTracker(1.5, file);
+      observe(1000, PHASE + 1n);
+      tracker.save();
+
+      tracker = new UpdateFrequencyTracker(1.5, file);
+      expect(observe(90000, PHASE + 2n)).toBeNull();
+      expect(observe(93600, PHASE + 3n)).toBe(3600);
+    } finally {
+      fs.rmSync(dir, { recursive: true, force: true });
+    }
+  });
+});
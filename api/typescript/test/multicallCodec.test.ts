@@ -0,0 +1,77 @@
+// Multicall3 result decoding, checked against ethers
+import { ethers } from 'ethers';
+import { MULTICALL3_ABI } from '../src/contracts/generated/Multicall3';
+import { AGGREGATOR_V3_INTERFACE_ABI } from '../src/contracts/generated/AggregatorV3Interface';
+import { decodeAggregateResult, decodeAggregate3Result } from '../src/utils/multicallCodec';
+
+const multicall = new ethers.Interface(MULTICALL3_ABI);
+const aggregator = new ethers.Interface(AGGREGATOR_V3_INTERFACE_ABI);
+
+const ROUND = [18446744073709551617n, 11850012345678n, 1753056000n, 1753056030n, 18446744073709551617n];
+const NEGATIVE = [2n, -5n, 1n, 2n, 2n];
+const roundData = (values: bigint[]) => aggregator.encodeFunctionResult('latestRoundData', values);
+const revert = (reason: string) => ethers.concat(['0x08c379a0', ethers.AbiCoder.defaultAbiCoder().encode(['string'], [reason])]);
+
+// What the codec sees of one latestRoundData entry, words 0-4 with the answer signed
+const readRound = (data: { uint(i: number): bigint; int(i: number): bigint }) =>
+  [data.uint(0), data.int(1), data.uint(2), data.uint(3), data.uint(4)];
+
+describe('multicallCodec', () => {
+  test('aggregate() matches ethers, including a negative answer and empty data', () => {
+    const entries = [roundData(ROUND), roundData(NEGATIVE), '0x'];
+    const result = multicall.encodeFunctionResult('aggregate', [123456n, entries]);
+    const expected = multicall.decodeFunctionResult('aggregate', result);
+
+    const seen: { length: number; values?: bigint[] }[] = [];
+    const blockNumber = decodeAggregateResult(result, (i, data) => {
+      seen[i] = { length: data.length, values: data.length > 0 ? readRound(data) : undefined };
+    });
+
+    expect(blockNumber).toBe(expected[0]);
+    expect(seen).toHaveLength(expected[1].length);
+    expected[1].forEach((bytes: string, i: number) => {
+      expect(seen[i].length).toBe(ethers.dataLength(bytes));
+      if (seen[i].values) {
+        expect(seen[i].values).toEqual([...aggregator.decodeFunctionResult('latestRoundData', bytes)]);
+      }
+    });
+  });
+
+  test('aggregate3() matches ethers, including failed calls and revert reasons', () => {
+    const entries = [
+      [true, roundData(ROUND)],
+      [false, revert('No data present')],
+      [false, '0x'],
+      [true, roundData(NEGATIVE)]
+    ];
+    const result = multicall.encodeFunctionResult('aggregate3', [entries]);
+    const [expected] = multicall.decodeFunctionResult('aggregate3', result);
+
+    const seen: { success: boolean; length: number; reason: string | null; values?: bigint[] }[] = [];
+    decodeAggregate3Result(result, (i, data, success) => {
+      seen[i] = {
+        success,
+        length: data.length,
+        reason: data.revertReason(),
+        values: success ? readRound(data) : undefined
+      };
+    });
+
+    expect(seen).toHaveLength(expected.length);
+    expected.forEach(([success, bytes]: [boolean, string], i: number) => {
+      expect(seen[i].success).toBe(success);
+      expect(seen[i].length).toBe(ethers.dataLength(bytes));
+      if (success) {
+        expect(seen[i].values).toEqual([...aggregator.decodeFunctionResult('latestRoundData', bytes)]);
+      }
+    });
+    expect(seen[1].reason).toBe('No data present');
+    expect(seen[2].reason).toBeNull();
+    expect(seen[0].reason).toBeNull();
+  });
+
+  test('short entries throw instead of reading the next entry', () => {
+    const result = multicall.encodeFunctionResult('aggregate3', [[[true, '0x' + '00'.repeat(64)], [true, roundData(ROUND)]]]);
+    expect(() => decodeAggregate3Result(result, (i, data) => readRound(data))).toThrow('Return data too short');
+  });
+});
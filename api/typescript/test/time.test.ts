@@ -0,0 +1,46 @@
+// Time zone conversion for the ?tz option of report endpoints
+import { isValidTimeZone, toTimeZone, localizeTimestamps } from '../src/utils/time';
+
+describe('time', () => {
+  test('unknown time zones are rejected before any conversion', () => {
+    expect(isValidTimeZone('America/New_York')).toBe(true);
+    expect(isValidTimeZone('UTC')).toBe(true);
+    ['Mars/Olympus', 'America', '', undefined, ['UTC', 'Europe/Berlin']].forEach(value => {
+      expect(isValidTimeZone(value)).toBe(false);
+    });
+  });
+
+  test('carries the offset in force at that instant', () => {
+    expect(toTimeZone('2025-07-21T00:59:55.000Z', 'America/New_York')).toBe('2025-07-20T20:59:55.000-04:00');
+    expect(toTimeZone('2025-01-15T12:00:00.250Z', 'America/New_York')).toBe('2025-01-15T07:00:00.250-05:00');
+    expect(toTimeZone('2025-07-21T00:59:55.000Z', 'Asia/Kolkata')).toBe('2025-07-21T06:29:55.000+05:30');
+    expect(toTimeZone('2025-07-21T00:59:55.000Z', 'UTC')).toBe('2025-07-21T00:59:55.000+00:00');
+  });
+
+  test('converts timestamp fields at any depth and leaves the rest alone', () => {
+    const report = {
+      window: 288,
+      timestamp: '2025-07-21T00:00:00.000Z',
+      feeds: [
+        { key: '2025-07-21T00:00:00.000Z', since: '2025-07-20T12:00:00.000Z', lastSuccess: null, availability: 0.5 }
+      ],
+      health: { gradedAt: '2025-07-21T00:00:00.000Z', grade: 'A' }
+    };
+
+    expect(localizeTimestamps(report, 'Europe/Berlin')).toEqual({
+      window: 288,
+      timestamp: '2025-07-21T02:00:00.000+02:00',
+      feeds: [
+        { key: '2025-07-21T00:00:00.000Z', since: '2025-07-20T14:00:00.000+02:00', lastSuccess: null, availability: 0.5 }
+      ],
+      health: { gradedAt: '2025-07-21T02:00:00.000+02:00', grade: 'A' }
+    });
+    expect(report.timestamp).toBe('2025-07-21T00:00:00.000Z');
+  });
+
+  test('leaves payloads untouched without a time zone or a parseable timestamp', () => {
+    const report = { timestamp: '2025-07-21T00:00:00.000Z' };
+    expect(localizeTimestamps(report, undefined)).toBe(report);
+    expect(localizeTimestamps({ lastUpdate: 'never' }, 'Europe/Berlin')).toEqual({ lastUpdate: 'never' });
+  });
+});
@@ -0,0 +1,40 @@
+// Realized volatility from round answers
+import { computeRealizedVolatility, PricePoint } from '../src/utils/volatility';
+
+const SECONDS_PER_YEAR = 365 * 24 * 60 * 60;
+const series = (prices: number[], interval = 60): PricePoint[] =>
+  prices.map((price, i) => ({ price, timestamp: 1753056000 + i * interval }));
+
+describe('computeRealizedVolatility', () => {
+  test('annualizes the sample standard deviation of log returns by the mean round interval', () => {
+    const result = computeRealizedVolatility(series([100, 200, 100]));
+
+    expect(result?.observations).toBe(2);
+    expect(result?.meanIntervalSeconds).toBe(60);
+    expect(result?.stdDev).toBeCloseTo(Math.LN2 * Math.SQRT2, 12);
+    expect(result?.realizedVolatility).toBeCloseTo(Math.LN2 * Math.SQRT2 * Math.sqrt(SECONDS_PER_YEAR / 60), 6);
+  });
+
+  test('orders rounds by time first', () => {
+    const points = series([100, 105, 98, 110]);
+    expect(computeRealizedVolatility([...points].reverse())).toEqual(computeRealizedVolatility(points));
+  });
+
+  test('a flat series has no volatility', () => {
+    expect(computeRealizedVolatility(series([100, 100, 100]))).toMatchObject({ stdDev: 0, realizedVolatility: 0 });
+  });
+
+  test('skips returns touching non-positive answers but keeps their time in the interval', () => {
+    const result = computeRealizedVolatility(series([100, 0, 100, 200, 100]));
+
+    expect(result?.observations).toBe(2);
+    expect(result?.meanIntervalSeconds).toBe(60);
+  });
+
+  test('needs two usable returns over a positive time span', () => {
+    expect(computeRealizedVolatility(series([100, 200]))).toBeNull();
+    expect(computeRealizedVolatility(series([100, 0, 100, 200]))).toBeNull();
+    expect(computeRealizedVolatility(series([100, 200, 100], 0))).toBeNull();
+    expect(computeRealizedVolatility([])).toBeNull();
+  });
+});
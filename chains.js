@@ -0,0 +1,82 @@
+// Chain registry
+// RPC, Multicall3 and default file locations per chain, selected with CHAIN
+// (default avalanche). Testnets default to their own feed file, metadata file
+// and snapshot directory, and every snapshot is tagged with its chain and a testnet flag so
+// testnet readings never end up in mainnet history.
+
+const fs = require('fs');
+
+const CHAINS = {
+  avalanche: {
+    name: 'avalanche',
+    chainId: 43114,
+    testnet: false,
+    rpcUrl: 'https://api.avax.network/ext/bc/C/rpc',
+    multicall3: '0xcA11bde05977b3631167028862bE2a173976CA11',
+    feedsFile: './avalanche_chainlink_feeds.csv',
+    metadataFile: './feed_metadata.json',
+    snapshotDir: '.'
+  },
+  // Public endpoint, no API key; reads are eth_calls, so no funded account is needed either
+  fuji: {
+    name: 'fuji',
+    chainId: 43113,
+    testnet: true,
+    rpcUrl: 'https://api.avax-test.network/ext/bc/C/rpc',
+    multicall3: '0xcA11bde05977b3631167028862bE2a173976CA11',
+    feedsFile: './fuji_chainlink_feeds.csv',
+    metadataFile: './fuji_feed_metadata.json',
+    snapshotDir: './snapshots/fuji'
+  }
+};
+
+const DEFAULT_CHAIN = 'avalanche';
+
+function resolveChain(name = process.env.CHAIN || DEFAULT_CHAIN) {
+  if (!Object.hasOwn(CHAINS, name)) {
+    throw new Error(`Unknown chain "${name}", expected one of ${Object.keys(CHAINS).join(', ')}`);
+  }
+  return CHAINS[name];
+}
+
+// Only the mainnet feed list ships with the repo; testnet feeds differ and are generated
+function feedsFileFor(chain, file = process.env.FEEDS_FILE || chain.feedsFile) {
+  if (!fs.existsSync(file)) {
+    throw new Error(`No feed list for ${chain.name} at ${file}. Generate one from the proxy addresses in Chainlink's docs ` +
+      `(node scripts/generate-feeds.js --chain ${chain.name} --addresses proxies.txt --out ${file}) or point FEEDS_FILE at an existing list`);
+  }
+  return file;
+}
+
+// Written by refresh-metadata; a missing file just means no refresh has run yet
+function metadataFileFor(chain, file = process.env.FEED_METADATA || chain.metadataFile) {
+  return file;
+}
+
+function chainTag(chain) {
+  return { chain: chain.name, chainId: chain.chainId, testnet: chain.testnet };
+}
+
+// Snapshots written before chains were tagged are mainnet readings
+function snapshotChain(snapshot) {
+  return snapshot.chain || DEFAULT_CHAIN;
+}
+
+function assertSingleChain(snapshots) {
+  const chains = [...new Set(snapshots.map(snapshotChain))];
+  if (chains.length > 1) {
+    throw new Error(`Snapshots from several chains (${chains.join(', ')}) cannot be combined; keep each chain in its own directory or log`);
+  }
+  return snapshots;
+}
+
+module.exports = {
+  CHAINS,
+  DEFAULT_CHAIN,
+  resolveChain,
+  feedsFileFor,
+  metadataFileFor,
+  chainTag,
+  snapshotChain,
+  assertSingleChain
+};
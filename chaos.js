@@ -0,0 +1,120 @@
+// Fault injection for exercising failure paths
+// CHAOS lists faults and the fetch steps they fire on, e.g.
+//   CHAOS="timeout@2,partial@3-4,corrupt/5,storage@6"
+// `@N` fires on step N, `@N-M` on steps N through M, `/K` on every Kth step.
+// Steps count fetches made by the same process, starting at 1.
+
+const FAULT_TYPES = ['timeout', 'partial', 'corrupt', 'storage'];
+
+function parseFaultSchedule(spec) {
+  if (!spec) return [];
+
+  return spec.split(',').map(part => part.trim()).filter(Boolean).map(part => {
+    const match = /^([a-z]+)(?:@(\d+)(?:-(\d+))?|\/(\d+))$/.exec(part);
+    if (!match || !FAULT_TYPES.includes(match[1])) {
+      throw new Error(`Invalid fault "${part}": expected one of ${FAULT_TYPES.join(', ')} followed by @N, @N-M or /K`);
+    }
+
+    const [, type, from, to, every] = match;
+    if (every) {
+      return { type, every: Number(every) };
+    }
+    return { type, from: Number(from), to: Number(to || from) };
+  });
+}
+
+// Small seeded generator so a schedule hits the same entries on every run
+function seededRandom(seed) {
+  let state = seed >>> 0;
+  return () => {
+    state = (state + 0x6D2B79F5) >>> 0;
+    let t = state;
+    t = Math.imul(t ^ (t >>> 15), t | 1);
+    t ^= t + Math.imul(t ^ (t >>> 7), t | 61);
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+}
+
+function createFaultInjector(spec, { seed = 1, rate = 0.25 } = {}) {
+  const schedule = parseFaultSchedule(spec);
+  const random = seededRandom(seed);
+  let step = 0;
+
+  const active = type => schedule.some(fault => fault.type === type && (fault.every
+    ? step % fault.every === 0
+    : step >= fault.from && step <= fault.to));
+
+  // Picks at least one entry so a scheduled fault is never a silent no-op
+  const pick = (count, protectedIndexes) => {
+    const candidates = [...Array(count).keys()].filter(i => !protectedIndexes.includes(i));
+    const chosen = candidates.filter(() => random() < rate);
+    if (chosen.length === 0 && candidates.length > 0) {
+      chosen.push(candidates[Math.floor(random() * candidates.length)]);
+    }
+    return new Set(chosen);
+  };
+
+  return {
+    enabled: schedule.length > 0,
+
+    get step() {
+      return step;
+    },
+
+    nextStep() {
+      step++;
+      return step;
+    },
+
+    active,
+
+    // Simulated RPC timeout, shaped like the error ethers raises
+    async call(fn) {
+      if (active('timeout')) {
+        throw Object.assign(new Error(`Injected RPC timeout (step ${step})`), { code: 'TIMEOUT' });
+      }
+      return fn();
+    },
+
+    // Failed calls come back empty; corrupted ones are cut short mid-word
+    mangleReturnData(returnData, protectedIndexes = []) {
+      const entries = Array.from(returnData);
+      if (active('partial')) {
+        pick(entries.length, protectedIndexes).forEach(i => {
+          entries[i] = '0x';
+        });
+      }
+      if (active('corrupt')) {
+        pick(entries.length, protectedIndexes).forEach(i => {
+          const bytes = (entries[i].length - 2) / 2;
+          entries[i] = entries[i].slice(0, 2 + 2 * Math.floor(random() * bytes));
+        });
+      }
+      return entries;
+    },
+
+    write(fn) {
+      if (active('storage')) {
+        throw new Error(`Injected storage outage (step ${step})`);
+      }
+      return fn();
+    }
+  };
+}
+
+// CHAOS enables injection; CHAOS_SEED and CHAOS_RATE tune which entries are hit
+function chaosOptions(env = process.env) {
+  return {
+    faults: createFaultInjector(env.CHAOS, {
+      seed: env.CHAOS_SEED ? Number(env.CHAOS_SEED) : 1,
+      rate: env.CHAOS_RATE ? Number(env.CHAOS_RATE) : 0.25
+    })
+  };
+}
+
+module.exports = {
+  FAULT_TYPES,
+  parseFaultSchedule,
+  createFaultInjector,
+  chaosOptions
+};
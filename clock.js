@@ -0,0 +1,52 @@
+// Clock sources for the price fetcher
+// Everything that stamps output reads time through a clock so runs can be made
+// reproducible: deterministic mode pins both the wall clock and the block read
+
+const systemClock = {
+  now: () => Date.now()
+};
+
+function fixedClock(at) {
+  // An offset-less date-time would be read as local time, which defeats determinism
+  if (typeof at === 'string' && at.includes('T') && !/(Z|[+-]\d{2}:\d{2})$/i.test(at)) {
+    throw new Error(`Fixed clock time ${at} needs an explicit UTC offset`);
+  }
+  const ms = typeof at === 'number' ? at : Date.parse(at);
+  if (!Number.isFinite(ms)) {
+    throw new Error(`Invalid fixed clock time: ${at}`);
+  }
+  return {
+    now: () => ms
+  };
+}
+
+// Number() alone would hand 1.5, -1, NaN (from "latest") or 1000 (from "1e3") on to eth_call
+function parseFixedBlock(value) {
+  if (!/^\d+$/.test(value) || !Number.isSafeInteger(Number(value))) {
+    throw new Error(`FIXED_BLOCK must be a non-negative integer block number, got "${value}"`);
+  }
+  return Number(value);
+}
+
+// DETERMINISTIC=1 pins time to FIXED_TIME (ISO or unix ms) and reads at FIXED_BLOCK
+function deterministicOptions(env = process.env) {
+  if (env.DETERMINISTIC !== '1' && env.DETERMINISTIC !== 'true') {
+    return { clock: systemClock, blockTag: env.FIXED_BLOCK ? parseFixedBlock(env.FIXED_BLOCK) : undefined };
+  }
+
+  if (!env.FIXED_TIME || !env.FIXED_BLOCK) {
+    throw new Error('Deterministic mode requires FIXED_TIME and FIXED_BLOCK');
+  }
+
+  const time = /^\d+$/.test(env.FIXED_TIME) ? Number(env.FIXED_TIME) : env.FIXED_TIME;
+  return {
+    clock: fixedClock(time),
+    blockTag: parseFixedBlock(env.FIXED_BLOCK)
+  };
+}
+
+module.exports = {
+  systemClock,
+  fixedClock,
+  deterministicOptions
+};
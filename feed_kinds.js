@@ -0,0 +1,67 @@
+// Feed kinds
+// Not every Chainlink feed is an asset price: exchange rates and macro rates can
+// be zero or negative, indices are unitless levels, and PoR feeds report reserve
+// amounts. The kind decides which answers are valid, how they are displayed and
+// which analyses apply (the APIs skip volatility for rates and PoR).
+
+const { ethers } = require('ethers');
+
+const FEED_KIND_RULES = {
+  price: { allowNegative: false, allowZero: false, volatility: true },
+  rate: { allowNegative: true, allowZero: true, volatility: false },
+  index: { allowNegative: false, allowZero: true, volatility: true },
+  por: { allowNegative: false, allowZero: true, volatility: false }
+};
+
+// An explicit kind (the feed list's optional kind column) wins over the name;
+// otherwise order matters: "PCE Price Index — Percent Change" is a rate, not an index
+function classifyFeed(name, kind) {
+  if (kind) {
+    if (!Object.hasOwn(FEED_KIND_RULES, kind)) {
+      throw new Error(`Unknown kind "${kind}" for ${name}, expected one of ${Object.keys(FEED_KIND_RULES).join(', ')}`);
+    }
+    return kind;
+  }
+  if (/reserve/i.test(name)) return 'por';
+  if (/exchange[\s-]rate|percent change|\bnav\b/i.test(name)) return 'rate';
+  if (/\bindex\b|\blevel\b|emergency count/i.test(name)) return 'index';
+  return 'price';
+}
+
+// Returns why the answer is invalid for this kind, or null if it is acceptable
+function validateAnswer(kind, answer) {
+  const rules = FEED_KIND_RULES[kind];
+  const value = BigInt(answer);
+  if (value < 0n && !rules.allowNegative) {
+    return `Negative answer ${value} is not valid for a ${kind} feed`;
+  }
+  if (value === 0n && !rules.allowZero) {
+    return `Zero answer is not valid for a ${kind} feed`;
+  }
+  return null;
+}
+
+// Exact decimal rendering of an answer; unlike Number() it never loses precision
+function formatAnswer(answer, decimals) {
+  return ethers.formatUnits(answer, decimals);
+}
+
+// Console rendering per kind: prices in dollars, PoR as amounts, rates and indices as plain values
+function displayValue(kind, exact) {
+  switch (kind) {
+    case 'price':
+      return `$${exact}`;
+    case 'por':
+      return `${exact} reserves`;
+    default:
+      return exact;
+  }
+}
+
+module.exports = {
+  FEED_KIND_RULES,
+  classifyFeed,
+  validateAnswer,
+  formatAnswer,
+  displayValue
+};
@@ -6,88 +6,59 @@
 const { ethers } = require('ethers');
 const fs = require('fs');
 const csv = require('csv-parser');
+const { parseArgs } = require('util');
+const { deterministicOptions, fixedClock } = require('./clock');
+const { chaosOptions } = require('./chaos');
+const { shardOptions, validateShard, inShard } = require('./sharding');
+const { CHAINS, resolveChain, feedsFileFor, metadataFileFor, chainTag, snapshotChain } = require('./chains');
+const { binarySink } = require('./snapshot_log');
+const {
+  createVerificationStats,
+  verifyWrite,
+  verifyOptions,
+  writeSynced,
+  exportVerificationMetrics,
+  WriteVerificationError
+} = require('./write_verification');
+const { classifyFeed, validateAnswer, formatAnswer, displayValue } = require('./feed_kinds');
+const { applyProfile } = require('./profiles');
 
 // Contract addresses
-const MULTICALL3_ADDRESS = '0xcA11bde05977b3631167028862bE2a173976CA11';
-const AVALANCHE_RPC = 'https://api.avax.network/ext/bc/C/rpc';
-
-// ABI definitions
-const MULTICALL3_ABI = [
-  {
-    "inputs": [
-      {
-        "components": [
-          { "internalType": "address", "name": "target", "type": "address" },
-          { "internalType": "bytes", "name": "callData", "type": "bytes" }
-        ],
-        "internalType": "struct Multicall3.Call[]",
-        "name": "calls",
-        "type": "tuple[]"
-      }
-    ],
-    "name": "aggregate",
-    "outputs": [
-      { "internalType": "uint256", "name": "blockNumber", "type": "uint256" },
-      { "internalType": "bytes[]", "name": "returnData", "type": "bytes[]" }
-    ],
-    "stateMutability": "payable",
-    "type": "function"
-  },
-  {
-    "inputs": [
-      {
-        "components": [
-          { "internalType": "address", "name": "target", "type": "address" },
-          { "internalType": "bytes", "name": "callData", "type": "bytes" }
-        ],
-        "internalType": "struct Multicall3.Call[]",
-        "name": "calls",
-        "type": "tuple[]"
-      }
-    ],
-    "name": "aggregate.staticcall",
-    "outputs": [
-      { "internalType": "uint256", "name": "blockNumber", "type": "uint256" },
-      { "internalType": "bytes[]", "name": "returnData", "type": "bytes[]" }
-    ],
-    "stateMutability": "view",
-    "type": "function"
-  }
-];
-
-const CHAINLINK_ABI = [
-  {
-    "inputs": [],
-    "name": "latestRoundData",
-    "outputs": [
-      { "internalType": "uint80", "name": "roundId", "type": "uint80" },
-      { "internalType": "int256", "name": "answer", "type": "int256" },
-      { "internalType": "uint256", "name": "startedAt", "type": "uint256" },
-      { "internalType": "uint256", "name": "updatedAt", "type": "uint256" },
-      { "internalType": "uint80", "name": "answeredInRound", "type": "uint80" }
-    ],
-    "stateMutability": "view",
-    "type": "function"
-  },
-  {
-    "inputs": [],
-    "name": "decimals",
-    "outputs": [{ "internalType": "uint8", "name": "", "type": "uint8" }],
-    "stateMutability": "view",
-    "type": "function"
-  }
-];
-
-async function loadFeedData() {
+const MULTICALL3_ADDRESS = CHAINS.avalanche.multicall3;
+
+// Source ABIs, shared with the generated API bindings (scripts/generate-bindings.js)
+const MULTICALL3_ABI = require('./abi/Multicall3.json');
+const CHAINLINK_ABI = require('./abi/AggregatorV3Interface.json');
+
+// Parsed once and shared by every fetch
+const MULTICALL3_INTERFACE = new ethers.Interface(MULTICALL3_ABI);
+const CHAINLINK_INTERFACE = new ethers.Interface(CHAINLINK_ABI);
+const ERROR_STRING_SELECTOR = '0x08c379a0';
+
+async function loadFeedData(file = process.env.FEEDS_FILE || './avalanche_chainlink_feeds.csv') {
   const feeds = [];
   return new Promise((resolve, reject) => {
-    fs.createReadStream('./avalanche_chainlink_feeds.csv')
+    fs.createReadStream(file)
       .pipe(csv())
       .on('data', (row) => {
+        let kind;
+        try {
+          kind = classifyFeed(row.name, row.kind);
+        } catch (error) {
+          reject(new Error(`${file}: ${error.message}`));
+          return;
+        }
         feeds.push({
           name: row.name,
+          kind,
+          contractAddress: row.contract_address,
           proxyAddress: row.proxy_address,
-          decimals: parseInt(row.decimals)
+          decimals: parseInt(row.decimals),
+          heartbeat: parseInt(row.heartbeat),
+          deviationThreshold: parseFloat(row.deviation_threshold),
+          assetClass: row.asset_class,
+          baseAsset: row.base_asset,
+          quoteAsset: row.quote_asset
         });
       })
       .on('end', () => {
@@ -98,86 +69,754 @@ async function loadFeedData() {
   });
 }
 
-async function getAllPrices() {
+// Built-in exchange-rate types: ERC-4626 vaults and LSTs, quoted for one whole share
+const ONE_SHARE = (10n ** 18n).toString();
+const RATE_METHODS = {
+  convertToAssets: { signature: 'function convertToAssets(uint256 shares) view returns (uint256)', takesShares: true },
+  getPooledAvaxByShares: { signature: 'function getPooledAvaxByShares(uint256 shareAmount) view returns (uint256)', takesShares: true },
+  getExchangeRate: { signature: 'function getExchangeRate() view returns (uint256)', takesShares: false },
+  exchangeRate: { signature: 'function exchangeRate() view returns (uint256)', takesShares: false },
+  getRate: { signature: 'function getRate() view returns (uint256)', takesShares: false }
+};
+const DEFAULT_RATE_METHOD = { erc4626: 'convertToAssets' };
+
+// ERC-2362 generic oracles serve many values from one contract, keyed by a bytes32 id
+const ERC2362_SIGNATURE = 'function valueFor(bytes32 _id) view returns (int256 value, uint256 timestamp, uint256 statusCode)';
+const ERC2362_STATUS_OK = 200;
+
+// Ids are given as bytes32 or as the "<base>-<quote>-<decimals>" string the standard hashes,
+// in which case the decimals come from the id unless decode.decimals overrides them
+function resolveOracleId(entry) {
+  if (typeof entry.id !== 'string' || entry.id.length === 0) {
+    throw new Error(`ERC-2362 feed ${entry.name} needs an id`);
+  }
+  if (/^0x[0-9a-fA-F]{64}$/.test(entry.id)) {
+    return { id: entry.id, decimals: entry.decode?.decimals };
+  }
+
+  const match = /^[a-z0-9]+-[a-z0-9]+-(\d+)$/.exec(entry.id);
+  if (!match) {
+    throw new Error(`ERC-2362 feed ${entry.name} id ${entry.id} is neither bytes32 nor <base>-<quote>-<decimals>`);
+  }
+  return { id: ethers.id(entry.id), decimals: entry.decode?.decimals ?? Number(match[1]) };
+}
+
+// Expand a typed entry into the signature/args/decode form used by custom feeds
+function resolveFeedType(entry) {
+  if (!entry.type) {
+    return entry;
+  }
+
+  if (entry.type === 'erc2362') {
+    const { id, decimals } = resolveOracleId(entry);
+    if (decimals === undefined) {
+      throw new Error(`ERC-2362 feed ${entry.name} with a bytes32 id needs decode.decimals`);
+    }
+    return {
+      ...entry,
+      kind: entry.kind || 'price',
+      signature: ERC2362_SIGNATURE,
+      args: [id],
+      decode: { index: 0, decimals, timestampIndex: 1, statusIndex: 2 },
+      oracleId: id
+    };
+  }
+
+  if (entry.type !== 'erc4626' && entry.type !== 'lst') {
+    throw new Error(`Unknown custom feed type ${entry.type} for ${entry.name}`);
+  }
+
+  const method = entry.method || DEFAULT_RATE_METHOD[entry.type];
+  const rateMethod = RATE_METHODS[method];
+  if (!rateMethod) {
+    throw new Error(`Custom feed ${entry.name} has unsupported ${entry.type} method ${method}`);
+  }
+
+  return {
+    ...entry,
+    signature: rateMethod.signature,
+    args: rateMethod.takesShares ? [ONE_SHARE] : [],
+    decode: { index: 0, decimals: 18, ...entry.decode }
+  };
+}
+
+// Custom feeds: non-Chainlink contracts read with their own method and decoding rule
+function loadCustomFeeds(file = process.env.CUSTOM_FEEDS || './custom_feeds.json') {
+  if (!fs.existsSync(file)) {
+    return [];
+  }
+
+  const config = JSON.parse(fs.readFileSync(file, 'utf8'));
+
+  return (config.feeds || []).map(resolveFeedType).map(entry => {
+    if (!entry.name || !ethers.isAddress(entry.target) || !entry.signature) {
+      throw new Error(`Invalid custom feed entry: ${JSON.stringify(entry)}`);
+    }
+
+    const iface = new ethers.Interface([entry.signature]);
+    const fragment = iface.fragments.find(f => f.type === 'function');
+    if (!fragment) {
+      throw new Error(`Custom feed ${entry.name} has no function signature`);
+    }
+
+    const decode = entry.decode || {};
+    const index = decode.index || 0;
+    if (index >= fragment.outputs.length) {
+      throw new Error(`Custom feed ${entry.name} decodes output ${index} but ${fragment.name} returns ${fragment.outputs.length}`);
+    }
+
+    return {
+      name: entry.name,
+      proxyAddress: entry.target,
+      source: entry.type || 'custom',
+      kind: entry.kind ? classifyFeed(entry.name, entry.kind) : (entry.type ? 'rate' : 'price'),
+      decimals: decode.decimals || 0,
+      method: fragment.format('sighash'),
+      outputIndex: index,
+      timestampIndex: decode.timestampIndex,
+      statusIndex: decode.statusIndex,
+      oracleId: entry.oracleId,
+      // Oracle ids sharing one contract spread across shards individually
+      shardKey: entry.oracleId ? `${entry.target}:${entry.oracleId}` : entry.target,
+      iface,
+      fragment,
+      callData: iface.encodeFunctionData(fragment, entry.args || [])
+    };
+  });
+}
+
+// Oracles that report a status (ERC-2362) only yield a price on 200, and the value must be
+// valid for the feed's kind, as for Chainlink feeds. A timestamp output becomes updatedAt,
+// aged against the sampled block when its timestamp is known
+function decodeCustomResult(feed, data, blockTimestamp) {
+  const outputs = feed.iface.decodeFunctionResult(feed.fragment, data);
+  const value = outputs[feed.outputIndex];
+
+  if (feed.statusIndex !== undefined && Number(outputs[feed.statusIndex]) !== ERC2362_STATUS_OK) {
+    throw new Error(`Oracle returned status ${outputs[feed.statusIndex]}`);
+  }
+  const invalid = validateAnswer(feed.kind, value);
+  if (invalid) {
+    throw new Error(invalid);
+  }
+
+  const result = {
+    name: feed.name,
+    proxy: feed.proxyAddress,
+    source: feed.source,
+    kind: feed.kind,
+    method: feed.method,
+    price: Number(value) / Math.pow(10, feed.decimals),
+    decimals: feed.decimals,
+    raw: {
+      value: value.toString()
+    }
+  };
+  if (feed.oracleId) {
+    result.oracleId = feed.oracleId;
+  }
+  if (feed.timestampIndex !== undefined) {
+    const updatedAt = Number(outputs[feed.timestampIndex]);
+    result.updatedAt = new Date(updatedAt * 1000).toISOString();
+    if (blockTimestamp !== undefined) {
+      result.ageAtBlock = ageAtBlock(blockTimestamp, updatedAt);
+    }
+  }
+  return result;
+}
+
+// Snapshot written after each fetch; all wall-clock values come from the clock
+function buildSnapshot(results, blockNumber, blockTimestamp, clock) {
+  return {
+    blockNumber: blockNumber.toString(),
+    blockTimestamp: new Date(Number(blockTimestamp) * 1000).toISOString(),
+    timestamp: new Date(clock.now()).toISOString(),
+    totalFeeds: results.length,
+    prices: results
+  };
+}
+
+// Split the batch so very large feed lists stay under RPC response limits
+function chunkCalls(calls, chunkSize) {
+  if (!Number.isFinite(chunkSize)) {
+    return [calls];
+  }
+  const chunks = [];
+  for (let i = 0; i < calls.length; i += chunkSize) {
+    chunks.push(calls.slice(i, i + chunkSize));
+  }
+  return chunks;
+}
+
+// eth_call state override set: { [address]: { balance?, nonce?, code?, state? | stateDiff? } }
+const STATE_OVERRIDE_FIELDS = ['balance', 'nonce', 'code', 'state', 'stateDiff'];
+
+function validateStateOverride(override) {
+  if (!override || typeof override !== 'object' || Array.isArray(override)) {
+    throw new Error('stateOverride must be an object keyed by address');
+  }
+
+  Object.entries(override).forEach(([address, account]) => {
+    if (!ethers.isAddress(address)) {
+      throw new Error(`stateOverride key ${address} is not an address`);
+    }
+    const unknown = Object.keys(account || {}).filter(field => !STATE_OVERRIDE_FIELDS.includes(field));
+    if (unknown.length > 0) {
+      throw new Error(`stateOverride for ${address} has unsupported field(s) ${unknown.join(', ')}`);
+    }
+    if (account.state && account.stateDiff) {
+      throw new Error(`stateOverride for ${address} sets both state and stateDiff`);
+    }
+  });
+  return override;
+}
+
+// Decimal digits only: BigInt() would take hex and fail on anything else with a bare SyntaxError
+function parseGasLimit(value) {
+  if (!/^\d+$/.test(value) || BigInt(value) === 0n) {
+    throw new Error(`GAS_LIMIT must be a positive integer, got "${value}"`);
+  }
+  return BigInt(value);
+}
+
+// GAS_LIMIT and STATE_OVERRIDE (path to a JSON override set) configure the eth_call
+function callOptions(env = process.env) {
+  const options = {};
+  if (env.GAS_LIMIT) {
+    options.gasLimit = parseGasLimit(env.GAS_LIMIT);
+  }
+  if (env.STATE_OVERRIDE) {
+    options.stateOverride = JSON.parse(fs.readFileSync(env.STATE_OVERRIDE, 'utf8'));
+  }
+  return options;
+}
+
+// On-chain metadata saved by `npm run refresh-metadata`, keyed by lowercased proxy address
+function loadOnChainMetadata(file = metadataFileFor(resolveChain())) {
+  if (!fs.existsSync(file)) {
+    return {};
+  }
+  return JSON.parse(fs.readFileSync(file, 'utf8')).feeds || {};
+}
+
+function feedMetadata(feed, onChain) {
+  const stored = onChain[feed.proxyAddress.toLowerCase()] || {};
+  return {
+    heartbeat: feed.heartbeat,
+    deviationThreshold: feed.deviationThreshold,
+    assetClass: feed.assetClass,
+    baseAsset: feed.baseAsset,
+    quoteAsset: feed.quoteAsset,
+    description: stored.description ?? null,
+    aggregator: stored.aggregator ?? null,
+    phaseId: stored.phaseId ?? null
+  };
+}
+
+// A call that reverted inside aggregate3; Error(string) reverts keep their reason
+function revertMessage(data) {
+  if (typeof data === 'string' && data.startsWith(ERROR_STRING_SELECTOR)) {
+    try {
+      const [reason] = ethers.AbiCoder.defaultAbiCoder().decode(['string'], `0x${data.slice(10)}`);
+      return `Call reverted: ${reason}`;
+    } catch {
+      // fall through to the generic message
+    }
+  }
+  return 'Call reverted';
+}
+
+// Seconds between an answer's updatedAt and the sampled block. An oracle that stamps its own
+// time can run ahead of the chain; such answers count as fresh rather than negatively aged
+function ageAtBlock(blockTimestamp, updatedAt) {
+  return Math.max(0, Number(blockTimestamp) - Number(updatedAt));
+}
+
+function decodeFeedResult(feed, data, blockTimestamp) {
   try {
-    // Setup provider and contracts
-    const provider = new ethers.JsonRpcProvider(AVALANCHE_RPC);
-    const multicall = new ethers.Contract(MULTICALL3_ADDRESS, MULTICALL3_ABI, provider);
-    const chainlinkInterface = new ethers.Interface(CHAINLINK_ABI);
+    const [roundId, answer, startedAt, updatedAt, answeredInRound] = 
+      CHAINLINK_INTERFACE.decodeFunctionResult('latestRoundData', data);
+    
+    const invalid = validateAnswer(feed.kind, answer);
+    if (invalid) {
+      throw new Error(invalid);
+    }
+    const price = Number(answer) / Math.pow(10, feed.decimals);
     
-    // Load feed data
-    const feeds = await loadFeedData();
+    return {
+      name: feed.name,
+      proxy: feed.proxyAddress,
+      source: 'chainlink',
+      kind: feed.kind,
+      price: price,
+      exactPrice: formatAnswer(answer, feed.decimals),
+      decimals: feed.decimals,
+      roundId: roundId.toString(),
+      updatedAt: new Date(Number(updatedAt) * 1000).toISOString(),
+      ageAtBlock: ageAtBlock(blockTimestamp, updatedAt),
+      raw: {
+        answer: answer.toString(),
+        startedAt: startedAt.toString(),
+        updatedAt: updatedAt.toString(),
+        answeredInRound: answeredInRound.toString()
+      }
+    };
+  } catch (error) {
+    return {
+      name: feed.name,
+      proxy: feed.proxyAddress,
+      error: error.message
+    };
+  }
+}
+
+// Sinks receive every snapshot a fetcher produces; write() may return a promise
+function consoleSink() {
+  return {
+    name: 'console',
+    write(snapshot) {
+      console.log('\n=== AVALANCHE CHAINLINK FEED PRICES ===');
+      snapshot.prices.forEach(result => {
+        if (result.error) {
+          console.log(`❌ ${result.name}: ERROR - ${result.error}`);
+        } else if (result.source !== 'chainlink') {
+          console.log(`🔧 ${result.name}: ${result.price.toFixed(8)} (via ${result.method})`);
+        } else {
+          console.log(`📈 ${result.name}: ${displayValue(result.kind, result.exactPrice)} (Updated: ${result.updatedAt})`);
+        }
+      });
+    }
+  };
+}
+
+// With verify, the saved file is synced, read back and its checksum compared before the write counts
+function fileSink({ dir = '.', verify = false } = {}) {
+  const verification = createVerificationStats();
+  return {
+    name: 'file',
+    verification,
+    write(snapshot) {
+      // Shards writing to one directory at the same moment must not overwrite each other
+      const suffix = shardSuffix(snapshot.shard);
+      const outputFile = `${dir}/avalanche_prices_${Date.parse(snapshot.timestamp)}${suffix}.json`;
+      const contents = Buffer.from(JSON.stringify(snapshot, null, 2));
+      fs.mkdirSync(dir, { recursive: true });
+      if (verify) {
+        writeSynced(outputFile, contents);
+        verifyWrite(outputFile, contents, () => fs.readFileSync(outputFile), verification);
+      } else {
+        fs.writeFileSync(outputFile, contents);
+      }
+      console.log(`\n✅ Results saved to ${outputFile}`);
+    },
+
+    // Newest snapshot this shard saved in dir, for catch-up after downtime
+    last({ shard } = {}) {
+      if (!fs.existsSync(dir)) return null;
+      const pattern = new RegExp(`^avalanche_prices_(\\d+)${shardSuffix(shard)}\\.json$`);
+      const newest = fs.readdirSync(dir)
+        .map(file => pattern.exec(file))
+        .filter(Boolean)
+        .reduce((best, match) => (!best || Number(match[1]) > Number(best[1]) ? match : best), null);
+      return newest ? JSON.parse(fs.readFileSync(`${dir}/${newest[0]}`, 'utf8')) : null;
+    }
+  };
+}
+
+function shardSuffix(shard) {
+  return shard ? `_shard${shard.index}of${shard.count}` : '';
+}
+
+// Catch-up never replays more than a day of 5-minute samples unless asked to
+const DEFAULT_MAX_CATCH_UP = 288;
+
+// A zero, negative or NaN interval would never advance the sample loop or the run timer
+function validateInterval(intervalMs) {
+  if (!Number.isInteger(intervalMs) || intervalMs < 1) {
+    throw new Error(`intervalMs must be a positive integer, got ${intervalMs}`);
+  }
+}
+
+/**
+ * Sample times and estimated blocks for the gap between the newest stored snapshot
+ * and the chain head, one every intervalMs. Blocks are interpolated between the two
+ * known points; each backfilled snapshot still records the real block timestamp it read.
+ * Only the newest maxSamples slots are kept, since older state may be pruned by the RPC.
+ */
+function planCatchUp(last, head, intervalMs, maxSamples = DEFAULT_MAX_CATCH_UP) {
+  validateInterval(intervalMs);
+  const lastBlock = Number(last.blockNumber);
+  const lastTime = Date.parse(last.blockTimestamp);
+  const headTime = head.timestamp * 1000;
+  if (!(headTime > lastTime) || !(head.number > lastBlock)) {
+    return { samples: [], skipped: 0 };
+  }
+
+  const slots = [];
+  for (let at = lastTime + intervalMs; at < headTime; at += intervalMs) {
+    slots.push(at);
+  }
+
+  const kept = slots.slice(Math.max(0, slots.length - maxSamples));
+  const blocksPerMs = (head.number - lastBlock) / (headTime - lastTime);
+  return {
+    samples: kept.map(at => ({ at, blockTag: lastBlock + Math.round((at - lastTime) * blocksPerMs) })),
+    skipped: slots.length - kept.length
+  };
+}
+
+/**
+ * Library entrypoint. Options:
+ *   chunkSize  - max calls per aggregate3() batch (default: one batch)
+ *   blockTag   - block number to read at; chunks always share one block
+ *   enrich     - attach CSV and refresh-metadata details to each Chainlink result
+ *   sinks      - objects with write(snapshot), called after every fetch; fileSink and
+ *                binarySink take verify: true to read each write back and compare checksums
+ *   gasLimit   - gas for the eth_call, for providers with a low default cap
+ *   stateOverride - eth_call state override set, e.g. to simulate a proxy upgrade
+ *   shard      - { index, count }: only fetch feeds whose proxy address hashes to this shard
+ *   chain      - chains.js registry name; picks the default RPC, Multicall3 and feed file,
+ *                and every snapshot is tagged with chain, chainId and testnet
+ *   verificationMetricsFile - Prometheus textfile that verifying sinks' counters are added to
+ *                after every fetch
+ *   clock, faults, provider, rpcUrl, customFeedsFile, metadataFile
+ * Snapshots read through rpcUrl (rather than a caller's provider) record its origin as rpcUrl.
+ * Snapshots record the hash of the block they were read at as blockHash, when the RPC can supply it.
+ * Unset clock/blockTag/faults/gasLimit/stateOverride/shard fall back to the DETERMINISTIC, CHAOS,
+ * GAS_LIMIT, STATE_OVERRIDE and SHARD_INDEX/SHARD_COUNT environment settings, chain to CHAIN,
+ * rpcUrl to RPC_URL and verificationMetricsFile to VERIFY_METRICS_FILE.
+ */
+function createFetcher(options = {}) {
+  const {
+    clock,
+    blockTag,
+    faults,
+    chunkSize = Infinity,
+    enrich = false,
+    sinks = [],
+    chain = process.env.CHAIN,
+    rpcUrl = process.env.RPC_URL || resolveChain(chain).rpcUrl,
+    provider = new ethers.JsonRpcProvider(rpcUrl),
+    customFeedsFile,
+    metadataFile,
+    gasLimit,
+    stateOverride,
+    shard,
+    verificationMetricsFile = process.env.VERIFY_METRICS_FILE
+  } = { ...deterministicOptions(), ...chaosOptions(), ...callOptions(), ...shardOptions(), ...options };
+
+  if (chunkSize !== Infinity && (!Number.isInteger(chunkSize) || chunkSize < 1)) {
+    throw new Error(`chunkSize must be a positive integer, got ${chunkSize}`);
+  }
+  sinks.forEach(sink => {
+    if (!sink || typeof sink.write !== 'function') {
+      throw new Error('Every sink needs a write(snapshot) method');
+    }
+  });
+
+  if (stateOverride !== undefined) {
+    validateStateOverride(stateOverride);
+  }
+  if (shard) {
+    validateShard(shard);
+  }
+
+  const network = resolveChain(chain);
+  // Only scheme and host are kept: API keys often live in the path or query
+  const endpoint = options.provider ? null : new URL(rpcUrl).origin;
+  const multicall = new ethers.Contract(network.multicall3, MULTICALL3_INTERFACE, provider);
+  let feedsPromise = null;
+
+  // aggregate3 lets each call fail on its own but reports no block, so every batch
+  // leads with getBlockNumber. Resolves to [blockNumber, [{ success, returnData }]].
+  // ethers has no stateOverride parameter, so overridden reads go through a raw eth_call
+  const aggregate = async (calls, tag) => {
+    const batch = [
+      { target: network.multicall3, allowFailure: false, callData: MULTICALL3_INTERFACE.encodeFunctionData('getBlockNumber', []) },
+      ...calls
+    ];
+
+    let results;
+    if (stateOverride === undefined) {
+      const overrides = {};
+      if (tag !== undefined) overrides.blockTag = tag;
+      if (gasLimit !== undefined) overrides.gasLimit = gasLimit;
+      results = await multicall.aggregate3.staticCall(batch, overrides);
+    } else {
+      const tx = { to: network.multicall3, data: MULTICALL3_INTERFACE.encodeFunctionData('aggregate3', [batch]) };
+      if (gasLimit !== undefined) tx.gas = ethers.toQuantity(gasLimit);
+      const block = tag === undefined ? 'latest' : ethers.toQuantity(tag);
+      const result = await provider.send('eth_call', [tx, block, stateOverride]);
+      [results] = MULTICALL3_INTERFACE.decodeFunctionResult('aggregate3', result);
+    }
+
+    const [blockNumber] = MULTICALL3_INTERFACE.decodeFunctionResult('getBlockNumber', results[0].returnData);
+    return [blockNumber, Array.from(results).slice(1)];
+  };
+
+  // Multicall3 can't report the sampled block's hash (blockhash(block.number) is zero inside
+  // eth_call), so it is read separately. A block whose timestamp no longer matches was replaced
+  // between the two reads and its hash would vouch for other state, so none is recorded.
+  const blockHashAt = async (blockNumber, blockTimestamp) => {
+    try {
+      const block = await provider.getBlock(Number(blockNumber));
+      if (block && BigInt(block.timestamp) === BigInt(blockTimestamp)) {
+        return block.hash;
+      }
+      console.error(`⚠️ Block ${blockNumber} changed while it was read; snapshot has no block hash`);
+    } catch (error) {
+      console.error(`⚠️ Could not read the hash of block ${blockNumber}:`, error.message);
+    }
+    return null;
+  };
+
+  // Feed lists are read and their calldata encoded once per fetcher, so run() doesn't redo either every cycle
+  const loadFeeds = () => {
+    const latestRoundData = CHAINLINK_INTERFACE.encodeFunctionData('latestRoundData', []);
+    feedsPromise ??= Promise.all([loadFeedData(feedsFileFor(network)), loadCustomFeeds(customFeedsFile)])
+      .then(([feeds, customFeeds]) => [feeds.map(feed => ({ ...feed, callData: latestRoundData })), customFeeds])
+      .then(lists => lists.map(list => list.filter(feed => inShard(feed.shardKey ?? feed.proxyAddress, shard))))
+      .catch(error => {
+        feedsPromise = null;
+        throw error;
+      });
+    return feedsPromise;
+  };
+
+  // One snapshot at `tag`; catch-up passes `at`, the sample time the snapshot stands in for
+  async function fetchAt({ signal, tag = blockTag, at } = {}) {
+    signal?.throwIfAborted();
+    const [feeds, customFeeds] = await loadFeeds();
     
-    // Prepare multicall data for latestRoundData()
-    const calls = feeds.map(feed => ({
+    // One call per feed, custom feeds after the Chainlink ones; a feed that reverts only fails its own entry
+    const calls = [...feeds, ...customFeeds].map(feed => ({
       target: feed.proxyAddress,
-      callData: chainlinkInterface.encodeFunctionData('latestRoundData', [])
+      allowFailure: true,
+      callData: feed.callData
     }));
     
-    console.log(`Fetching prices for ${calls.length} feeds via Multicall3...`);
-    const startTime = Date.now();
+    // Read the block timestamp in the same call so answer age is measured at the sampled block
+    calls.push({
+      target: network.multicall3,
+      allowFailure: false,
+      callData: MULTICALL3_INTERFACE.encodeFunctionData('getCurrentBlockTimestamp', [])
+    });
+    
+    console.log(`Fetching prices for ${feeds.length + customFeeds.length} feeds via Multicall3...`);
+    const startTime = clock.now();
+    if (faults.enabled) {
+      console.log(`🧪 Fault injection active (step ${faults.nextStep()})`);
+    }
     
-    // Execute multicall as static call (read-only)
-    const [blockNumber, returnData] = await multicall.aggregate.staticCall(calls);
+    // Execute multicall as static call (read-only); later chunks are pinned to the first chunk's block
+    let blockNumber;
+    const callResults = [];
+    for (const chunk of chunkCalls(calls, chunkSize)) {
+      signal?.throwIfAborted();
+      const chunkTag = tag ?? blockNumber;
+      const [chunkBlock, chunkResults] = await faults.call(() => aggregate(chunk, chunkTag));
+      blockNumber ??= chunkBlock;
+      callResults.push(...chunkResults);
+    }
+    const succeeded = callResults.map(result => result.success);
+    const returnData = faults.mangleReturnData(callResults.map(result => result.returnData), [callResults.length - 1]);
     
-    const endTime = Date.now();
+    const endTime = clock.now();
     console.log(`Fetched all prices in ${endTime - startTime}ms at block ${blockNumber}`);
     
+    const [blockTimestamp] = MULTICALL3_INTERFACE.decodeFunctionResult(
+      'getCurrentBlockTimestamp', returnData[returnData.length - 1]
+    );
+    
     // Decode results
-    const results = returnData.map((data, index) => {
+    const onChain = enrich ? loadOnChainMetadata(metadataFileFor(network, metadataFile)) : null;
+    const results = returnData.slice(0, feeds.length).map((data, index) => {
+      const feed = feeds[index];
+      const result = succeeded[index]
+        ? decodeFeedResult(feed, data, blockTimestamp)
+        : { name: feed.name, proxy: feed.proxyAddress, error: revertMessage(data) };
+      return enrich ? { ...result, metadata: feedMetadata(feed, onChain) } : result;
+    });
+    
+    returnData.slice(feeds.length, feeds.length + customFeeds.length).forEach((data, index) => {
+      const feed = customFeeds[index];
       try {
-        const [roundId, answer, startedAt, updatedAt, answeredInRound] = 
-          chainlinkInterface.decodeFunctionResult('latestRoundData', data);
-        
-        const feed = feeds[index];
-        const price = Number(answer) / Math.pow(10, feed.decimals);
-        
-        return {
+        if (!succeeded[feeds.length + index]) {
+          throw new Error(revertMessage(data));
+        }
+        results.push(decodeCustomResult(feed, data, blockTimestamp));
+      } catch (error) {
+        results.push({
           name: feed.name,
           proxy: feed.proxyAddress,
-          price: price,
-          decimals: feed.decimals,
-          roundId: roundId.toString(),
-          updatedAt: new Date(Number(updatedAt) * 1000).toISOString(),
-          raw: {
-            answer: answer.toString(),
-            startedAt: startedAt.toString(),
-            updatedAt: updatedAt.toString(),
-            answeredInRound: answeredInRound.toString()
-          }
-        };
-      } catch (error) {
-        return {
-          name: feeds[index].name,
-          proxy: feeds[index].proxyAddress,
+          source: feed.source,
           error: error.message
-        };
+        });
       }
     });
     
-    // Display results
-    console.log('\n=== AVALANCHE CHAINLINK FEED PRICES ===');
-    results.forEach(result => {
-      if (result.error) {
-        console.log(`❌ ${result.name}: ERROR - ${result.error}`);
-      } else {
-        console.log(`📈 ${result.name}: $${result.price.toFixed(8)} (Updated: ${result.updatedAt})`);
+    const snapshot = {
+      ...buildSnapshot(results, blockNumber, blockTimestamp, at === undefined ? clock : fixedClock(at)),
+      ...chainTag(network)
+    };
+    const blockHash = await blockHashAt(blockNumber, blockTimestamp);
+    if (blockHash) {
+      snapshot.blockHash = blockHash;
+    }
+    if (endpoint) {
+      snapshot.rpcUrl = endpoint;
+    }
+    if (shard) {
+      snapshot.shard = { index: shard.index, count: shard.count };
+    }
+    if (at !== undefined) {
+      snapshot.backfill = true;
+    }
+    try {
+      for (const sink of sinks) {
+        await faults.write(() => sink.write(snapshot));
       }
-    });
-    
-    // Save to JSON
-    const outputFile = `./avalanche_prices_${Date.now()}.json`;
-    fs.writeFileSync(outputFile, JSON.stringify({
-      blockNumber: blockNumber.toString(),
-      timestamp: new Date().toISOString(),
-      totalFeeds: results.length,
-      prices: results
-    }, null, 2));
-    
-    console.log(`\n✅ Results saved to ${outputFile}`);
-    
-    return results;
+    } finally {
+      exportVerification();
+    }
     
+    return snapshot;
+  }
+
+  // Adds what each verifying sink counted since the previous export to the metrics file;
+  // a failed export is reported but never fails the fetch
+  const exported = new Map();
+  function exportVerification() {
+    if (!verificationMetricsFile) return;
+    const verifying = sinks.filter(sink => sink.verification);
+    const counts = Object.fromEntries(verifying.map(sink => {
+      const previous = exported.get(sink) ?? { verified: 0, failures: 0 };
+      return [sink.name ?? 'sink', {
+        verified: sink.verification.verified - previous.verified,
+        failures: sink.verification.failures - previous.failures
+      }];
+    }));
+
+    try {
+      exportVerificationMetrics(verificationMetricsFile, network.name, counts);
+      verifying.forEach(sink => exported.set(sink, { verified: sink.verification.verified, failures: sink.verification.failures }));
+    } catch (error) {
+      console.error(`⚠️ Could not write verification metrics to ${verificationMetricsFile}:`, error.message);
+    }
+  }
+
+  function fetch({ signal } = {}) {
+    return fetchAt({ signal });
+  }
+
+  // Newest snapshot any sink can report back
+  async function lastStored() {
+    let newest = null;
+    for (const sink of sinks.filter(s => typeof s.last === 'function')) {
+      const last = await sink.last({ shard });
+      // A shared directory or log may hold another chain's snapshots; never resume from those
+      if (last && snapshotChain(last) !== network.name) continue;
+      if (last && (!newest || Date.parse(last.blockTimestamp) > Date.parse(newest.blockTimestamp))) {
+        newest = last;
+      }
+    }
+    return newest;
+  }
+
+  // Backfill the gap since the newest stored snapshot, one sample per intervalMs
+  async function catchUp({ signal, intervalMs, maxSamples = DEFAULT_MAX_CATCH_UP }) {
+    validateInterval(intervalMs);
+    if (blockTag !== undefined) {
+      console.log('⏭️  Catch-up skipped: reads are pinned to a fixed block');
+      return [];
+    }
+    const last = await lastStored();
+    if (!last) {
+      console.log('⏭️  Catch-up skipped: no stored snapshot to resume from');
+      return [];
+    }
+
+    const head = await provider.getBlock('latest');
+    const { samples, skipped } = planCatchUp(last, head, intervalMs, maxSamples);
+    if (samples.length === 0) return [];
+
+    console.log(`⏪ Catching up ${samples.length} missed samples since block ${last.blockNumber}` +
+      (skipped > 0 ? ` (${skipped} older samples beyond the limit left unfilled)` : ''));
+    const snapshots = [];
+    for (const sample of samples) {
+      signal?.throwIfAborted();
+      try {
+        snapshots.push(await fetchAt({ signal, tag: sample.blockTag, at: sample.at }));
+      } catch (error) {
+        if (signal?.aborted) throw error;
+        console.error(`❌ Catch-up sample at block ${sample.blockTag} failed:`, error.message);
+      }
+    }
+    return snapshots;
+  }
+
+  // Fetch every intervalMs until the signal aborts; a failed cycle is logged and retried next interval.
+  // With catchUp, first backfills the gap since the newest stored snapshot (at most maxCatchUp samples).
+  async function run({ signal, intervalMs = 5 * 60 * 1000, catchUp: backfill = false, maxCatchUp = DEFAULT_MAX_CATCH_UP } = {}) {
+    validateInterval(intervalMs);
+    if (backfill) {
+      try {
+        await catchUp({ signal, intervalMs, maxSamples: maxCatchUp });
+      } catch (error) {
+        if (signal?.aborted) return;
+        console.error('❌ Catch-up failed:', error.message);
+      }
+    }
+    while (!signal?.aborted) {
+      try {
+        await fetch({ signal });
+      } catch (error) {
+        if (signal?.aborted) break;
+        console.error('❌ Fetch cycle failed:', error.message);
+        if (error instanceof WriteVerificationError) {
+          console.error('🔍 Write verification failures so far:', JSON.stringify(verification()));
+        }
+      }
+      await sleep(intervalMs, signal);
+    }
+  }
+
+  // Read-your-writes counters per verifying sink, keyed by sink name
+  function verification() {
+    return Object.fromEntries(sinks
+      .filter(sink => sink.verification)
+      .map(sink => [sink.name ?? 'sink', { ...sink.verification }]));
+  }
+
+  return { fetch, run, catchUp, verification };
+}
+
+function sleep(ms, signal) {
+  return new Promise(resolve => {
+    if (signal?.aborted) return resolve();
+    const timer = setTimeout(done, ms);
+    function done() {
+      clearTimeout(timer);
+      signal?.removeEventListener('abort', done);
+      resolve();
+    }
+    signal?.addEventListener('abort', done, { once: true });
+  });
+}
+
+// One fetch printed to the console and saved as avalanche_prices_<time>.json in
+// SNAPSHOT_DIR (by default the chain's own directory, so testnet files stay apart),
+// also appended to the binary snapshot log when SNAPSHOT_LOG is set.
+// VERIFY_WRITES=1 reads both back and fails the fetch on a checksum mismatch.
+async function getAllPrices(options = {}) {
+  try {
+    const sinks = [consoleSink(), fileSink({ dir: process.env.SNAPSHOT_DIR || resolveChain().snapshotDir, ...verifyOptions(process.env, 'file') })];
+    if (process.env.SNAPSHOT_LOG) {
+      sinks.push(binarySink({ file: process.env.SNAPSHOT_LOG, ...verifyOptions(process.env, 'binary') }));
+    }
+    const fetcher = createFetcher({ sinks, ...options });
+    const snapshot = await fetcher.fetch();
+    return snapshot.prices;
   } catch (error) {
     console.error('Error fetching prices:', error);
     throw error;
@@ -186,6 +825,18 @@ async function getAllPrices() {
 
 // Execute if run directly
 if (require.main === module) {
+  // --profile or PROFILE picks a named deployment from profiles.json
+  try {
+    const { values } = parseArgs({ options: { profile: { type: 'string', default: process.env.PROFILE } } });
+    if (values.profile) {
+      applyProfile(values.profile);
+      console.log(`🧭 Using profile ${values.profile}`);
+    }
+  } catch (err) {
+    console.error('❌ Invalid profile:', err.message);
+    process.exit(1);
+  }
+
   getAllPrices()
     .then(() => console.log('✅ Price fetch complete'))
     .catch(err => {
@@ -194,4 +845,26 @@ if (require.main === module) {
     });
 }
 
-module.exports = { getAllPrices, loadFeedData };
\ No newline at end of file
+module.exports = {
+  createFetcher,
+  consoleSink,
+  fileSink,
+  binarySink,
+  WriteVerificationError,
+  planCatchUp,
+  chunkCalls,
+  validateStateOverride,
+  callOptions,
+  getAllPrices,
+  buildSnapshot,
+  loadFeedData,
+  loadCustomFeeds,
+  loadOnChainMetadata,
+  feedMetadata,
+  decodeCustomResult,
+  MULTICALL3_ADDRESS,
+  MULTICALL3_ABI,
+  CHAINLINK_ABI,
+  MULTICALL3_INTERFACE,
+  CHAINLINK_INTERFACE
+};
\ No newline at end of file
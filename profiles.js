@@ -0,0 +1,105 @@
+// Named deployment profiles
+// profiles.json groups the settings one deployment needs (chain, RPC, feed files,
+// sinks and alerting) under a name such as "prod-avalanche". Applying a profile
+// sets the environment variables those settings map to, so a single --profile
+// flag or PROFILE variable stands in for a dozen of them. Variables that are
+// already set win, so one-off overrides still work from the environment.
+// Relative paths in a profile are taken relative to profiles.json itself.
+
+const fs = require('fs');
+const path = require('path');
+
+const DEFAULT_PROFILES_FILE = './profiles.json';
+
+// Profile keys and the environment variables they set
+const PROFILE_SETTINGS = {
+  chain: 'CHAIN',
+  rpcUrl: 'RPC_URL',
+  feedsFile: 'FEEDS_FILE',
+  customFeedsFile: 'CUSTOM_FEEDS',
+  metadataFile: 'FEED_METADATA'
+};
+const SINK_SETTINGS = {
+  file: { dir: 'SNAPSHOT_DIR' },
+  binary: { file: 'SNAPSHOT_LOG' }
+};
+const ALERTING_SETTINGS = {
+  silencesFile: 'SILENCES_FILE',
+  updateTolerance: 'UPDATE_TOLERANCE'
+};
+const PATH_VARIABLES = ['FEEDS_FILE', 'CUSTOM_FEEDS', 'FEED_METADATA', 'SNAPSHOT_DIR', 'SNAPSHOT_LOG', 'SILENCES_FILE'];
+
+function mapSettings(name, section, settings, mapping, vars) {
+  for (const [key, value] of Object.entries(settings)) {
+    if (!(key in mapping)) {
+      throw new Error(`Profile ${name}: unknown ${section} setting "${key}"`);
+    }
+    vars[mapping[key]] = String(value);
+  }
+}
+
+// Environment variables a profile sets. Sinks marked verify: true are listed in
+// VERIFY_WRITES; "env" passes any other variable through unchanged.
+function profileEnv(name, profile) {
+  const vars = {};
+  const verified = [];
+
+  for (const [key, value] of Object.entries(profile)) {
+    if (key in PROFILE_SETTINGS) {
+      vars[PROFILE_SETTINGS[key]] = String(value);
+    } else if (key === 'sinks') {
+      for (const [sink, settings] of Object.entries(value)) {
+        if (!(sink in SINK_SETTINGS)) {
+          throw new Error(`Profile ${name}: unknown sink "${sink}", expected one of ${Object.keys(SINK_SETTINGS).join(', ')}`);
+        }
+        const { verify, ...rest } = settings;
+        mapSettings(name, `${sink} sink`, rest, SINK_SETTINGS[sink], vars);
+        if (verify) verified.push(sink);
+      }
+    } else if (key === 'alerting') {
+      mapSettings(name, 'alerting', value, ALERTING_SETTINGS, vars);
+    } else if (key === 'env') {
+      Object.entries(value).forEach(([variable, setting]) => { vars[variable] = String(setting); });
+    } else {
+      throw new Error(`Profile ${name}: unknown setting "${key}"`);
+    }
+  }
+
+  if (verified.length > 0) {
+    vars.VERIFY_WRITES = verified.join(',');
+  }
+  return vars;
+}
+
+function loadProfile(name, file = process.env.PROFILES_FILE || DEFAULT_PROFILES_FILE) {
+  const { profiles = {} } = JSON.parse(fs.readFileSync(file, 'utf8'));
+  if (!Object.hasOwn(profiles, name)) {
+    const available = Object.keys(profiles).join(', ') || 'none';
+    throw new Error(`Unknown profile "${name}" in ${file} (available: ${available})`);
+  }
+  const vars = profileEnv(name, profiles[name]);
+  PATH_VARIABLES
+    .filter(variable => vars[variable] !== undefined)
+    .forEach(variable => { vars[variable] = path.resolve(path.dirname(file), vars[variable]); });
+  return vars;
+}
+
+// Fills env from the profile without touching variables that are already set.
+// Returns the variables it set.
+function applyProfile(name, env = process.env, file = env.PROFILES_FILE || DEFAULT_PROFILES_FILE) {
+  const applied = {};
+  for (const [variable, value] of Object.entries(loadProfile(name, file))) {
+    if (env[variable] === undefined) {
+      env[variable] = value;
+      applied[variable] = value;
+    }
+  }
+  return applied;
+}
+
+module.exports = {
+  DEFAULT_PROFILES_FILE,
+  profileEnv,
+  loadProfile,
+  applyProfile
+};
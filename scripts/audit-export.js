@@ -0,0 +1,275 @@
+#!/usr/bin/env node
+
+// Audit Export
+// Packages the snapshots stored for a time range into one signed, self-contained
+// bundle for external auditors. A bundle holds feed metadata, the raw answers,
+// a hash for each block read (checked against the hash recorded at fetch time),
+// the RPC endpoints used and the software version.
+// It is gzipped JSON; its payload is signed with an Ed25519 key, and `verify`
+// checks a bundle against that signature.
+//   audit-export --since 2025-07-01T00:00:00Z --until 2025-08-01T00:00:00Z --key audit.pem
+//   audit-export verify audit_avalanche_....json.gz [--public-key trusted.pem]
+
+const fs = require('fs');
+const path = require('path');
+const zlib = require('zlib');
+const crypto = require('crypto');
+const { execFileSync } = require('child_process');
+const { parseArgs } = require('util');
+const { ethers } = require('ethers');
+const { loadFeedData, loadOnChainMetadata, feedMetadata } = require('../multicall_price_fetcher.js');
+const { resolveChain, feedsFileFor, metadataFileFor, chainTag, snapshotChain, assertSingleChain } = require('../chains');
+const { checksum } = require('../write_verification');
+const { applyProfile } = require('../profiles');
+const { parseSince, loadStoredSnapshots } = require('./query');
+const { parseTime } = require('./export');
+
+const BUNDLE_FORMAT = 'cchainlink-audit/1';
+const BLOCK_LOOKUP_BATCH = 25;
+
+// Package version plus the git commit when run from a checkout
+function softwareVersion() {
+    const { name, version } = require('../package.json');
+    let commit = null;
+    try {
+        commit = execFileSync('git', ['rev-parse', 'HEAD'], { cwd: path.join(__dirname, '..'), stdio: ['ignore', 'pipe', 'ignore'] })
+            .toString().trim();
+    } catch {
+        commit = null;
+    }
+    return { name, version, commit, node: process.version };
+}
+
+// Every distinct block the snapshots were read at, with its hash as the chain reports it now.
+// A hash recorded at fetch time that differs, or for older snapshots without one a stored
+// block timestamp that disagrees with the chain, means the block was reorged away.
+async function lookupBlocks(provider, snapshots) {
+    // Several snapshots can share a block; keep one that recorded a hash if any did
+    const stored = new Map();
+    snapshots.forEach(snapshot => {
+        if (!stored.get(snapshot.blockNumber)?.blockHash) {
+            stored.set(snapshot.blockNumber, snapshot);
+        }
+    });
+    const numbers = [...stored.keys()];
+    const blocks = [];
+
+    for (let i = 0; i < numbers.length; i += BLOCK_LOOKUP_BATCH) {
+        const batch = await Promise.all(numbers.slice(i, i + BLOCK_LOOKUP_BATCH).map(number => provider.getBlock(Number(number))));
+        batch.forEach((block, j) => {
+            const number = numbers[i + j];
+            const { blockHash, blockTimestamp } = stored.get(number);
+            if (!block) {
+                throw new Error(`Block ${number} not found on the RPC`);
+            }
+            if (blockHash && block.hash.toLowerCase() !== blockHash.toLowerCase()) {
+                throw new Error(`Block ${number} has hash ${block.hash} on chain but ${blockHash} in the snapshot`);
+            }
+            const timestamp = new Date(Number(block.timestamp) * 1000).toISOString();
+            if (timestamp !== blockTimestamp) {
+                throw new Error(`Block ${number} has timestamp ${timestamp} on chain but ${blockTimestamp} in the snapshot`);
+            }
+            blocks.push({ number, hash: block.hash, timestamp, recordedHash: Boolean(blockHash) });
+        });
+    }
+    return blocks;
+}
+
+// Configured details for each feed in the snapshots; custom feeds only carry what the snapshot records
+async function collectFeedMetadata(snapshots, { feedsFile, metadataFile }) {
+    const configured = new Map((await loadFeedData(feedsFile)).map(feed => [feed.proxyAddress.toLowerCase(), feed]));
+    const onChain = loadOnChainMetadata(metadataFile);
+
+    const seen = new Map();
+    snapshots.forEach(snapshot => snapshot.prices.forEach(entry => {
+        if (!seen.has(entry.proxy.toLowerCase()) || seen.get(entry.proxy.toLowerCase()).error) {
+            seen.set(entry.proxy.toLowerCase(), entry);
+        }
+    }));
+
+    return [...seen.values()].map(entry => {
+        const feed = configured.get(entry.proxy.toLowerCase());
+        const base = { name: entry.name, proxy: entry.proxy, source: entry.source || 'chainlink' };
+        if (!feed) {
+            return { ...base, kind: entry.kind, decimals: entry.decimals, method: entry.method, oracleId: entry.oracleId };
+        }
+        return { ...base, kind: feed.kind, contractAddress: feed.contractAddress, decimals: feed.decimals, ...feedMetadata(feed, onChain) };
+    });
+}
+
+async function buildBundle({ snapshots, from = null, to = null, provider, rpcUrl, feedsFile, metadataFile, now = Date.now() }) {
+    if (snapshots.length === 0) {
+        throw new Error('No snapshots found in the requested range');
+    }
+    assertSingleChain(snapshots);
+
+    return {
+        format: BUNDLE_FORMAT,
+        generatedAt: new Date(now).toISOString(),
+        range: {
+            from: from === null ? null : new Date(from).toISOString(),
+            to: to === null ? null : new Date(to).toISOString()
+        },
+        software: softwareVersion(),
+        chain: chainTag(resolveChain(snapshotChain(snapshots[0]))),
+        rpcEndpoints: {
+            snapshots: [...new Set(snapshots.map(snapshot => snapshot.rpcUrl).filter(Boolean))],
+            blockHashes: rpcUrl ? new URL(rpcUrl).origin : null
+        },
+        feeds: await collectFeedMetadata(snapshots, { feedsFile, metadataFile }),
+        blocks: await lookupBlocks(provider, snapshots),
+        snapshots: snapshots.map(({ at, ...snapshot }) => snapshot)
+    };
+}
+
+// The signature covers the payload exactly as serialized in the bundle
+function signBundle(payload, privateKeyPem) {
+    const privateKey = crypto.createPrivateKey(privateKeyPem);
+    if (privateKey.asymmetricKeyType !== 'ed25519') {
+        throw new Error(`Audit bundles are signed with an Ed25519 key, got ${privateKey.asymmetricKeyType}`);
+    }
+
+    const body = Buffer.from(JSON.stringify(payload));
+    return {
+        payload,
+        signature: {
+            algorithm: 'ed25519',
+            sha256: checksum(body),
+            publicKey: crypto.createPublicKey(privateKey).export({ type: 'spki', format: 'pem' }),
+            value: crypto.sign(null, body, privateKey).toString('base64')
+        }
+    };
+}
+
+// Without a trusted key this only proves the bundle is intact, not who signed it
+function verifyBundle(bundle, trustedPublicKeyPem) {
+    if (bundle.payload?.format !== BUNDLE_FORMAT || bundle.signature?.algorithm !== 'ed25519') {
+        return { valid: false, reason: `not a ${BUNDLE_FORMAT} bundle` };
+    }
+
+    const body = Buffer.from(JSON.stringify(bundle.payload));
+    if (checksum(body) !== bundle.signature.sha256) {
+        return { valid: false, reason: 'payload checksum mismatch' };
+    }
+
+    const embedded = crypto.createPublicKey(bundle.signature.publicKey);
+    if (trustedPublicKeyPem) {
+        const trusted = crypto.createPublicKey(trustedPublicKeyPem);
+        if (!trusted.export({ type: 'spki', format: 'der' }).equals(embedded.export({ type: 'spki', format: 'der' }))) {
+            return { valid: false, reason: 'signed by a different key' };
+        }
+    }
+
+    if (!crypto.verify(null, body, embedded, Buffer.from(bundle.signature.value, 'base64'))) {
+        return { valid: false, reason: 'signature does not match the payload' };
+    }
+    return { valid: true, reason: null, trustedKey: Boolean(trustedPublicKeyPem) };
+}
+
+function writeBundle(bundle, file) {
+    fs.writeFileSync(file, zlib.gzipSync(JSON.stringify(bundle)));
+}
+
+function readBundle(file) {
+    return JSON.parse(zlib.gunzipSync(fs.readFileSync(file)).toString('utf8'));
+}
+
+async function verifyCommand(file, publicKeyFile) {
+    if (!file) {
+        throw new Error('Usage: audit-export verify <bundle> [--public-key file]');
+    }
+    const bundle = readBundle(file);
+    const result = verifyBundle(bundle, publicKeyFile ? fs.readFileSync(publicKeyFile, 'utf8') : undefined);
+    if (!result.valid) {
+        throw new Error(`${file} failed verification: ${result.reason}`);
+    }
+
+    const { payload } = bundle;
+    console.log(`✅ ${file} is intact and correctly signed${result.trustedKey ? ' by the trusted key' : ' (signer not checked: pass --public-key)'}`);
+    console.log(`   ${payload.snapshots.length} snapshots, ${payload.feeds.length} feeds, ${payload.blocks.length} blocks on ${payload.chain.chain}`);
+    console.log(`   Range ${payload.range.from ?? 'start'} to ${payload.range.to ?? 'end'}, made by ${payload.software.name} ${payload.software.version}`);
+    return result;
+}
+
+async function auditExport(argv = process.argv.slice(2), { provider } = {}) {
+    const { values, positionals } = parseArgs({
+        args: argv,
+        allowPositionals: true,
+        options: {
+            since: { type: 'string' },
+            until: { type: 'string' },
+            dir: { type: 'string' },
+            log: { type: 'string' },
+            chain: { type: 'string' },
+            rpc: { type: 'string' },
+            key: { type: 'string', default: process.env.AUDIT_SIGNING_KEY },
+            'public-key': { type: 'string' },
+            profile: { type: 'string', default: process.env.PROFILE },
+            out: { type: 'string' }
+        }
+    });
+
+    if (positionals[0] === 'verify') {
+        return verifyCommand(positionals[1], values['public-key']);
+    }
+    if (positionals.length > 0) {
+        throw new Error(`Unknown command ${positionals[0]}; run audit-export --since <time> or audit-export verify <bundle>`);
+    }
+    if (!values.since) {
+        throw new Error('audit-export needs a range: --since <time> [--until <time>]');
+    }
+    if (!values.key) {
+        throw new Error('audit-export needs an Ed25519 signing key: --key audit.pem or AUDIT_SIGNING_KEY (openssl genpkey -algorithm ed25519 -out audit.pem)');
+    }
+
+    if (values.profile) {
+        applyProfile(values.profile);
+    }
+    const chain = resolveChain(values.chain);
+    const dir = values.dir ?? process.env.SNAPSHOT_DIR ?? chain.snapshotDir;
+    const log = values.log ?? process.env.SNAPSHOT_LOG;
+    const from = parseSince(values.since);
+    const to = parseTime(values.until, 'until') ?? Date.now();
+    const rpcUrl = values.rpc || process.env.RPC_URL || chain.rpcUrl;
+
+    console.error(`📂 Reading snapshots from ${log || path.resolve(dir)}...`);
+    const snapshots = await loadStoredSnapshots({ dir, log, from, to, chain: chain.name });
+
+    console.error(`🔗 Looking up block hashes for ${snapshots.length} snapshots...`);
+    const payload = await buildBundle({
+        snapshots,
+        from,
+        to,
+        provider: provider ?? new ethers.JsonRpcProvider(rpcUrl),
+        rpcUrl,
+        feedsFile: feedsFileFor(chain),
+        metadataFile: metadataFileFor(chain)
+    });
+    const bundle = signBundle(payload, fs.readFileSync(values.key, 'utf8'));
+
+    const outputFile = values.out || `./audit_${chain.name}_${from}-${to}.json.gz`;
+    writeBundle(bundle, outputFile);
+    console.log(`✅ Wrote signed audit bundle to ${outputFile}`);
+    console.log(`   ${payload.snapshots.length} snapshots, ${payload.feeds.length} feeds, ${payload.blocks.length} blocks, sha256 ${bundle.signature.sha256}`);
+    return bundle;
+}
+
+// Execute if run directly
+if (require.main === module) {
+    auditExport().catch(err => {
+        console.error('❌ Audit export failed:', err.message);
+        process.exit(1);
+    });
+}
+
+module.exports = {
+    auditExport,
+    buildBundle,
+    signBundle,
+    verifyBundle,
+    writeBundle,
+    readBundle,
+    lookupBlocks,
+    collectFeedMetadata,
+    BUNDLE_FORMAT
+};
@@ -0,0 +1,147 @@
+#!/usr/bin/env node
+
+// Canary Feed Cross-Validation
+// Reads the same pair from Chainlink feeds on several chains and alerts when they diverge
+
+const fs = require('fs');
+const { ethers } = require('ethers');
+const { MULTICALL3_ADDRESS, MULTICALL3_INTERFACE, CHAINLINK_INTERFACE } = require('../multicall_price_fetcher.js');
+
+const CANARY_CONFIG = process.env.CANARY_CONFIG || './canary_pairs.json';
+
+function loadCanaryConfig(configPath = CANARY_CONFIG) {
+    const config = JSON.parse(fs.readFileSync(configPath, 'utf8'));
+
+    if (!config.chains || !Array.isArray(config.pairs)) {
+        throw new Error(`Invalid canary config ${configPath}: expected "chains" and "pairs"`);
+    }
+
+    config.pairs.forEach(pair => {
+        const chains = Object.keys(pair.feeds || {});
+        if (chains.length < 2) {
+            throw new Error(`Canary pair ${pair.name} needs feeds on at least two chains`);
+        }
+        chains.forEach(chain => {
+            if (!config.chains[chain]) {
+                throw new Error(`Canary pair ${pair.name} references unknown chain ${chain}`);
+            }
+        });
+    });
+
+    return config;
+}
+
+async function readChainFeeds(chain, rpc, addresses) {
+    const provider = new ethers.JsonRpcProvider(rpc);
+    const multicall = new ethers.Contract(MULTICALL3_ADDRESS, MULTICALL3_INTERFACE, provider);
+
+    // Two calls per feed: latestRoundData() and decimals()
+    const calls = addresses.flatMap(address => [
+        { target: address, callData: CHAINLINK_INTERFACE.encodeFunctionData('latestRoundData', []) },
+        { target: address, callData: CHAINLINK_INTERFACE.encodeFunctionData('decimals', []) }
+    ]);
+
+    const [blockNumber, returnData] = await multicall.aggregate.staticCall(calls);
+    console.log(`🔗 ${chain}: read ${addresses.length} canary feeds at block ${blockNumber}`);
+
+    const readings = new Map();
+    addresses.forEach((address, index) => {
+        const [, answer, , updatedAt] =
+            CHAINLINK_INTERFACE.decodeFunctionResult('latestRoundData', returnData[index * 2]);
+        const [decimals] = CHAINLINK_INTERFACE.decodeFunctionResult('decimals', returnData[index * 2 + 1]);
+
+        readings.set(address.toLowerCase(), {
+            price: Number(answer) / Math.pow(10, Number(decimals)),
+            updatedAt: new Date(Number(updatedAt) * 1000).toISOString()
+        });
+    });
+
+    return readings;
+}
+
+function evaluatePairs(config, readingsByChain) {
+    return config.pairs.map(pair => {
+        const threshold = pair.thresholdPercent ?? config.defaultThresholdPercent ?? 1;
+
+        const observations = Object.entries(pair.feeds).map(([chain, address]) => ({
+            chain,
+            address,
+            ...readingsByChain.get(chain)?.get(address.toLowerCase())
+        }));
+
+        const prices = observations.map(o => o.price).filter(price => price > 0);
+        if (prices.length < observations.length) {
+            return { name: pair.name, threshold, observations, divergencePercent: null, diverged: true };
+        }
+
+        const min = Math.min(...prices);
+        const max = Math.max(...prices);
+        const divergencePercent = ((max - min) / min) * 100;
+
+        return {
+            name: pair.name,
+            threshold,
+            observations,
+            divergencePercent,
+            diverged: divergencePercent > threshold
+        };
+    });
+}
+
+async function runCanaryCheck(configPath = CANARY_CONFIG) {
+    console.log('🐤 Running canary cross-chain validation...\n');
+
+    const config = loadCanaryConfig(configPath);
+
+    // Collect the addresses to read on each chain
+    const addressesByChain = new Map();
+    config.pairs.forEach(pair => {
+        Object.entries(pair.feeds).forEach(([chain, address]) => {
+            if (!addressesByChain.has(chain)) addressesByChain.set(chain, []);
+            addressesByChain.get(chain).push(address);
+        });
+    });
+
+    const readingsByChain = new Map();
+    await Promise.all([...addressesByChain.entries()].map(async ([chain, addresses]) => {
+        readingsByChain.set(chain, await readChainFeeds(chain, config.chains[chain].rpc, addresses));
+    }));
+
+    const results = evaluatePairs(config, readingsByChain);
+
+    console.log('\n=== CANARY PAIRS ===');
+    results.forEach(result => {
+        const prices = result.observations
+            .map(o => `${o.chain}=${o.price ?? 'n/a'} (${o.updatedAt ?? 'n/a'})`)
+            .join(', ');
+
+        if (result.divergencePercent === null) {
+            console.log(`🚨 ${result.name}: missing or invalid answer - ${prices}`);
+        } else if (result.diverged) {
+            console.log(`🚨 ${result.name}: diverged ${result.divergencePercent.toFixed(4)}% (threshold ${result.threshold}%) - ${prices}`);
+        } else {
+            console.log(`✅ ${result.name}: ${result.divergencePercent.toFixed(4)}% - ${prices}`);
+        }
+    });
+
+    return results;
+}
+
+// Execute if run directly
+if (require.main === module) {
+    runCanaryCheck()
+        .then(results => {
+            const diverged = results.filter(r => r.diverged);
+            if (diverged.length > 0) {
+                console.log(`\n🚨 ${diverged.length} canary pair(s) diverged beyond threshold`);
+                process.exit(1);
+            }
+            console.log('\n✅ All canary pairs within threshold');
+        })
+        .catch(err => {
+            console.error('❌ Canary check failed:', err);
+            process.exit(2);
+        });
+}
+
+module.exports = { runCanaryCheck, loadCanaryConfig, evaluatePairs };
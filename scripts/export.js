@@ -0,0 +1,214 @@
+#!/usr/bin/env node
+
+// Export Saved Price Snapshots
+// Reads the avalanche_prices_*.json snapshots written by the fetcher and exports
+// them for a time range, as an XLSX workbook (summary + per-feed sheets) or CSV.
+// --chain / CHAIN (default avalanche) picks the chain: its snapshot directory is the
+// default --dir, and only that chain's snapshots are exported.
+
+const fs = require('fs');
+const path = require('path');
+const { parseArgs } = require('util');
+const ExcelJS = require('exceljs');
+const { createObjectCsvWriter } = require('csv-writer');
+const { resolveChain, snapshotChain, assertSingleChain } = require('../chains');
+
+// Sharded fetchers add _shard<index>of<count> to the name
+const SNAPSHOT_PATTERN = /^avalanche_prices_\d+(_shard\d+of\d+)?\.json$/;
+const FORMATS = ['xlsx', 'csv'];
+
+// Load snapshots whose sampled block falls within [from, to], oldest first,
+// optionally only those of one chain
+function loadSnapshots(dir, from, to, chain = null) {
+    const snapshots = fs.readdirSync(dir)
+        .filter(file => SNAPSHOT_PATTERN.test(file))
+        .map(file => JSON.parse(fs.readFileSync(path.join(dir, file), 'utf8')))
+        .map(snapshot => ({ ...snapshot, at: Date.parse(snapshot.blockTimestamp || snapshot.timestamp) }))
+        .filter(snapshot => (!from || snapshot.at >= from) && (!to || snapshot.at <= to))
+        .filter(snapshot => !chain || snapshotChain(snapshot) === chain);
+
+    return snapshots.sort((a, b) => a.at - b.at);
+}
+
+// Regroup snapshots into one time series per feed, keyed by feed name.
+// Mainnet and testnet snapshots never share a series.
+function buildHistory(snapshots) {
+    assertSingleChain(snapshots);
+    const history = new Map();
+
+    snapshots.forEach(snapshot => {
+        snapshot.prices.forEach(entry => {
+            if (entry.error) return;
+
+            if (!history.has(entry.name)) {
+                history.set(entry.name, { name: entry.name, proxy: entry.proxy, source: entry.source || 'chainlink', rows: [] });
+            }
+            history.get(entry.name).rows.push({
+                sampledAt: new Date(snapshot.at).toISOString(),
+                blockNumber: snapshot.blockNumber,
+                price: entry.price,
+                roundId: entry.roundId ?? '',
+                updatedAt: entry.updatedAt ?? '',
+                ageAtBlock: entry.ageAtBlock ?? ''
+            });
+        });
+    });
+
+    return [...history.values()].sort((a, b) => a.name.localeCompare(b.name));
+}
+
+function summarize(feed) {
+    const prices = feed.rows.map(row => row.price);
+    const first = feed.rows[0];
+    const last = feed.rows[feed.rows.length - 1];
+
+    return {
+        name: feed.name,
+        source: feed.source,
+        proxy: feed.proxy,
+        observations: feed.rows.length,
+        from: first.sampledAt,
+        to: last.sampledAt,
+        min: Math.min(...prices),
+        max: Math.max(...prices),
+        latest: last.price,
+        changePercent: first.price !== 0 ? ((last.price - first.price) / first.price) * 100 : null
+    };
+}
+
+// Excel sheet names: max 31 chars, no []:*?/\ and unique within the workbook
+function sheetName(name, used) {
+    const base = name.replace(/[[\]:*?/\\]/g, '-').replace(/\s+/g, ' ').trim().slice(0, 31) || 'Feed';
+    let candidate = base;
+    for (let i = 2; used.has(candidate.toLowerCase()); i++) {
+        const suffix = ` (${i})`;
+        candidate = base.slice(0, 31 - suffix.length) + suffix;
+    }
+    used.add(candidate.toLowerCase());
+    return candidate;
+}
+
+async function writeXlsx(history, outputFile) {
+    const workbook = new ExcelJS.Workbook();
+    workbook.created = new Date();
+
+    const used = new Set(['summary']);
+    const summary = workbook.addWorksheet('Summary');
+    summary.columns = [
+        { header: 'Feed', key: 'name', width: 36 },
+        { header: 'Sheet', key: 'sheet', width: 32 },
+        { header: 'Source', key: 'source', width: 10 },
+        { header: 'Proxy', key: 'proxy', width: 44 },
+        { header: 'Observations', key: 'observations', width: 13 },
+        { header: 'From', key: 'from', width: 26 },
+        { header: 'To', key: 'to', width: 26 },
+        { header: 'Min', key: 'min', width: 18 },
+        { header: 'Max', key: 'max', width: 18 },
+        { header: 'Latest', key: 'latest', width: 18 },
+        { header: 'Change %', key: 'changePercent', width: 10 }
+    ];
+    summary.getRow(1).font = { bold: true };
+    summary.views = [{ state: 'frozen', ySplit: 1 }];
+
+    history.forEach(feed => {
+        const name = sheetName(feed.name, used);
+        summary.addRow({ ...summarize(feed), sheet: name });
+
+        const sheet = workbook.addWorksheet(name);
+        sheet.columns = [
+            { header: 'Sampled At', key: 'sampledAt', width: 26 },
+            { header: 'Block', key: 'blockNumber', width: 12 },
+            { header: 'Price', key: 'price', width: 18 },
+            { header: 'Round ID', key: 'roundId', width: 24 },
+            { header: 'Updated At', key: 'updatedAt', width: 26 },
+            { header: 'Age At Block (s)', key: 'ageAtBlock', width: 16 }
+        ];
+        sheet.getRow(1).font = { bold: true };
+        sheet.views = [{ state: 'frozen', ySplit: 1 }];
+        sheet.addRows(feed.rows);
+    });
+
+    await workbook.xlsx.writeFile(outputFile);
+}
+
+async function writeCsv(history, outputFile) {
+    const writer = createObjectCsvWriter({
+        path: outputFile,
+        header: [
+            { id: 'name', title: 'feed' },
+            { id: 'sampledAt', title: 'sampled_at' },
+            { id: 'blockNumber', title: 'block_number' },
+            { id: 'price', title: 'price' },
+            { id: 'roundId', title: 'round_id' },
+            { id: 'updatedAt', title: 'updated_at' },
+            { id: 'ageAtBlock', title: 'age_at_block' }
+        ]
+    });
+
+    await writer.writeRecords(history.flatMap(feed => feed.rows.map(row => ({ name: feed.name, ...row }))));
+}
+
+// RFC 3339 times must carry an explicit offset; a bare date means midnight UTC.
+// Date.parse would otherwise read an offset-less date-time as local time.
+function parseTime(value, flag) {
+    if (!value) return null;
+    if (value.includes('T') && !/(Z|[+-]\d{2}:\d{2})$/i.test(value)) {
+        throw new Error(`--${flag} time ${value} needs an explicit UTC offset (e.g. Z or +02:00)`);
+    }
+    const ms = Date.parse(value);
+    if (!Number.isFinite(ms)) {
+        throw new Error(`Invalid --${flag} time: ${value}`);
+    }
+    return ms;
+}
+
+async function exportSnapshots(argv = process.argv.slice(2)) {
+    const { values } = parseArgs({
+        args: argv,
+        options: {
+            format: { type: 'string', default: 'xlsx' },
+            from: { type: 'string' },
+            to: { type: 'string' },
+            dir: { type: 'string' },
+            chain: { type: 'string' },
+            out: { type: 'string' }
+        }
+    });
+
+    if (!FORMATS.includes(values.format)) {
+        throw new Error(`Unsupported --format ${values.format} (expected ${FORMATS.join(' or ')})`);
+    }
+
+    const chain = resolveChain(values.chain);
+    const dir = values.dir ?? process.env.SNAPSHOT_DIR ?? chain.snapshotDir;
+    const from = parseTime(values.from, 'from');
+    const to = parseTime(values.to, 'to');
+
+    console.log(`📂 Reading snapshots from ${path.resolve(dir)}...`);
+    const snapshots = loadSnapshots(dir, from, to, chain.name);
+    if (snapshots.length === 0) {
+        throw new Error('No snapshots found in the requested range');
+    }
+
+    const history = buildHistory(snapshots);
+    const outputFile = values.out || `./${chain.name}_prices_export_${Date.now()}.${values.format}`;
+
+    if (values.format === 'xlsx') {
+        await writeXlsx(history, outputFile);
+    } else {
+        await writeCsv(history, outputFile);
+    }
+
+    console.log(`✅ Exported ${history.length} feeds across ${snapshots.length} snapshots to ${outputFile}`);
+    return outputFile;
+}
+
+// Execute if run directly
+if (require.main === module) {
+    exportSnapshots().catch(err => {
+        console.error('❌ Export failed:', err.message);
+        process.exit(1);
+    });
+}
+
+module.exports = { exportSnapshots, loadSnapshots, buildHistory, summarize, sheetName, parseTime, writeXlsx };
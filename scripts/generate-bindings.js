@@ -0,0 +1,194 @@
+#!/usr/bin/env node
+
+// Generate Contract Bindings
+// abi/*.json holds the source ABI of every contract the fetcher and APIs call.
+// This writes the typed TypeScript bindings (api/typescript/src/contracts/generated)
+// and the Python ABI module (api/python/contract_abis.py) from those files, so
+// each API image carries its ABIs without reaching outside its build context.
+// The root fetcher requires the JSON directly. --check fails when any generated
+// file is out of date instead of writing it.
+
+const fs = require('fs');
+const path = require('path');
+const { parseArgs } = require('util');
+
+const ROOT = path.join(__dirname, '..');
+const ABI_DIR = path.join(ROOT, 'abi');
+const TS_DIR = path.join(ROOT, 'api/typescript/src/contracts/generated');
+const PY_FILE = path.join(ROOT, 'api/python/contract_abis.py');
+
+// AggregatorV3Interface -> AGGREGATOR_V3_INTERFACE
+function constantName(contract) {
+    return contract.replace(/([a-z0-9])([A-Z])/g, '$1_$2').toUpperCase();
+}
+
+function typeName(name) {
+    return name.charAt(0).toUpperCase() + name.slice(1);
+}
+
+// "struct Multicall3.Call3[]" -> Call3Struct
+function structName(param) {
+    const match = /^struct (?:\w+\.)?(\w+)/.exec(param.internalType ?? '');
+    return match ? `${match[1]}Struct` : null;
+}
+
+// TypeScript type of a decoded value, as ethers v6 returns it
+function tsType(param) {
+    const array = /^(.*)\[\d*\]$/.exec(param.type);
+    if (array) {
+        const element = { ...param, type: array[1], internalType: param.internalType?.replace(/\[\d*\]$/, '') };
+        return `${tsType(element)}[]`;
+    }
+    if (param.type === 'tuple') {
+        return structName(param) ?? `{ ${param.components.map(c => `${c.name}: ${tsType(c)}`).join('; ')} }`;
+    }
+    if (/^u?int\d*$/.test(param.type)) return 'bigint';
+    if (param.type === 'bool') return 'boolean';
+    return 'string';
+}
+
+// Named structs used anywhere in the ABI, once each
+function collectStructs(abi) {
+    const structs = new Map();
+    const visit = param => {
+        if (param.components) {
+            const name = structName(param);
+            if (name && !structs.has(name)) {
+                structs.set(name, param.components);
+            }
+            param.components.forEach(visit);
+        }
+    };
+    abi.forEach(entry => [...(entry.inputs ?? []), ...(entry.outputs ?? [])].forEach(visit));
+    return structs;
+}
+
+function generateTypeScript(contract, abi, source) {
+    const constant = constantName(contract);
+    const lines = [
+        `// Generated by scripts/generate-bindings.js from ${source}. Do not edit;`,
+        '// change the ABI and run npm run generate-bindings instead.',
+        '',
+        `export const ${constant}_ABI = ${JSON.stringify(abi, null, 2)} as const;`
+    ];
+
+    collectStructs(abi).forEach((components, name) => {
+        lines.push('', `export interface ${name} {`, ...components.map(c => `  ${c.name}: ${tsType(c)};`), '}');
+    });
+
+    abi.filter(entry => entry.type === 'function' && entry.outputs.length > 0).forEach(fn => {
+        const output = `${typeName(fn.name)}Output`;
+        if (fn.outputs.length === 1) {
+            lines.push('', `export type ${output} = ${tsType(fn.outputs[0])};`);
+            return;
+        }
+        lines.push('', `export interface ${output} {`,
+            ...fn.outputs.map((param, i) => `  ${param.name || `output${i}`}: ${tsType(param)};`), '}');
+    });
+
+    return `${lines.join('\n')}\n`;
+}
+
+// JSON as a Python literal, indented like the rest of the API
+function pythonLiteral(value, indent = '') {
+    const inner = `${indent}    `;
+    if (Array.isArray(value)) {
+        if (value.length === 0) return '[]';
+        return `[\n${value.map(item => `${inner}${pythonLiteral(item, inner)},`).join('\n')}\n${indent}]`;
+    }
+    if (value && typeof value === 'object') {
+        const entries = Object.entries(value);
+        if (entries.length === 0) return '{}';
+        return `{\n${entries.map(([key, item]) => `${inner}${JSON.stringify(key)}: ${pythonLiteral(item, inner)},`).join('\n')}\n${indent}}`;
+    }
+    if (value === true) return 'True';
+    if (value === false) return 'False';
+    if (value === null) return 'None';
+    return JSON.stringify(value);
+}
+
+function generatePython(contracts) {
+    const lines = [
+        '"""',
+        'Contract ABIs',
+        `Generated by scripts/generate-bindings.js from ${contracts.map(c => c.source).join(' and ')}.`,
+        'Do not edit; change the ABI and run npm run generate-bindings instead.',
+        '"""',
+        '',
+        'from typing import Any, Dict, Final, List'
+    ];
+    contracts.forEach(({ contract, abi }) => {
+        lines.push('', `${constantName(contract)}_ABI: Final[List[Dict[str, Any]]] = ${pythonLiteral(abi)}`);
+    });
+    return `${lines.join('\n')}\n`;
+}
+
+function loadAbis(dir = ABI_DIR) {
+    return fs.readdirSync(dir)
+        .filter(file => file.endsWith('.json'))
+        .sort()
+        .map(file => {
+            const abi = JSON.parse(fs.readFileSync(path.join(dir, file), 'utf8'));
+            if (!Array.isArray(abi)) {
+                throw new Error(`${file} is not an ABI: expected a JSON array`);
+            }
+            return { contract: path.basename(file, '.json'), abi, source: `abi/${file}` };
+        });
+}
+
+// Every generated file with the contents it should have
+function generateBindings(contracts = loadAbis()) {
+    return [
+        ...contracts.map(({ contract, abi, source }) => ({
+            file: path.join(TS_DIR, `${contract}.ts`),
+            contents: generateTypeScript(contract, abi, source)
+        })),
+        { file: PY_FILE, contents: generatePython(contracts) }
+    ];
+}
+
+// Generated files that are missing or differ from what the ABIs produce
+function staleBindings(outputs = generateBindings()) {
+    return outputs.filter(({ file, contents }) => !fs.existsSync(file) || fs.readFileSync(file, 'utf8') !== contents);
+}
+
+function main(argv = process.argv.slice(2)) {
+    const { values } = parseArgs({ args: argv, options: { check: { type: 'boolean', default: false } } });
+    const stale = staleBindings();
+
+    if (values.check) {
+        if (stale.length > 0) {
+            throw new Error(`Out of date: ${stale.map(({ file }) => path.relative(ROOT, file)).join(', ')}; run npm run generate-bindings`);
+        }
+        console.log('✅ Generated bindings match abi/');
+        return [];
+    }
+
+    stale.forEach(({ file, contents }) => {
+        fs.mkdirSync(path.dirname(file), { recursive: true });
+        fs.writeFileSync(file, contents);
+        console.log(`📝 Wrote ${path.relative(ROOT, file)}`);
+    });
+    console.log(stale.length > 0 ? `✅ Regenerated ${stale.length} file(s)` : '✅ Bindings already up to date');
+    return stale;
+}
+
+// Execute if run directly
+if (require.main === module) {
+    try {
+        main();
+    } catch (err) {
+        console.error('❌ Binding generation failed:', err.message);
+        process.exit(1);
+    }
+}
+
+module.exports = {
+    main,
+    loadAbis,
+    generateBindings,
+    staleBindings,
+    generateTypeScript,
+    generatePython,
+    constantName
+};
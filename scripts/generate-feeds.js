@@ -0,0 +1,253 @@
+#!/usr/bin/env node
+
+// Generate a Feed File from On-Chain Discovery
+// Reads description(), decimals() and aggregator() for a list of proxy addresses
+// (given directly, from a file, or the feeds a Feed Registry serves for the pairs in its FeedConfirmed events)
+// in one Multicall and writes a feed CSV or YAML in the avalanche_chainlink_feeds.csv layout.
+// --chain fuji reads testnet feeds through the Fuji RPC and Multicall3
+
+const fs = require('fs');
+const { parseArgs } = require('util');
+const csv = require('csv-parser');
+const { ethers } = require('ethers');
+const { MULTICALL3_ADDRESS } = require('../multicall_price_fetcher.js');
+const { resolveChain } = require('../chains');
+const { MULTICALL3_AGGREGATE3_INTERFACE, METADATA_INTERFACE } = require('./refresh-metadata.js');
+
+const FORMATS = ['csv', 'yaml'];
+const COLUMNS = [
+    'name', 'contract_address', 'proxy_address', 'deviation_threshold', 'heartbeat', 'decimals',
+    'asset_class', 'product_name', 'ens', 'path', 'base_asset', 'quote_asset'
+];
+
+const FEED_REGISTRY_INTERFACE = new ethers.Interface([
+    'event FeedConfirmed(address indexed asset, address indexed denomination, address indexed latestAggregator, address previousAggregator, uint16 nextPhaseId, address sender)',
+    'function getFeed(address base, address quote) view returns (address aggregator)'
+]);
+
+// Public RPCs cap eth_getLogs ranges, so registry history is read in pages of this many blocks
+const DEFAULT_LOG_PAGE_BLOCKS = 5000;
+
+const DISCOVERY_FIELDS = ['description', 'decimals', 'aggregator'];
+
+// Addresses from positional args and/or a file with one address per line (# comments allowed)
+function readAddresses(args, file) {
+    const fromFile = file
+        ? fs.readFileSync(file, 'utf8').split('\n').map(line => line.replace(/#.*/, '').trim()).filter(Boolean)
+        : [];
+    const addresses = [...args, ...fromFile];
+
+    addresses.forEach(address => {
+        if (!ethers.isAddress(address)) {
+            throw new Error(`Not an address: ${address}`);
+        }
+    });
+    return [...new Map(addresses.map(address => [address.toLowerCase(), ethers.getAddress(address)])).values()];
+}
+
+// Every base/quote pair in the registry's FeedConfirmed history, with the feed the registry
+// serves for it now (getFeed) rather than the aggregator an old event named. Pairs whose
+// feed has since been removed no longer resolve and are left out.
+async function scrapeRegistry(provider, registry, { fromBlock = 0, pageSize = DEFAULT_LOG_PAGE_BLOCKS, multicallAddress = MULTICALL3_ADDRESS } = {}) {
+    if (!Number.isInteger(pageSize) || pageSize < 1) {
+        throw new Error(`Log page size must be a positive number of blocks, got ${pageSize}`);
+    }
+
+    const event = FEED_REGISTRY_INTERFACE.getEvent('FeedConfirmed');
+    const head = await provider.getBlockNumber();
+    const pairs = new Map();
+    for (let start = fromBlock; start <= head; start += pageSize) {
+        const toBlock = Math.min(start + pageSize - 1, head);
+        const logs = await provider.getLogs({ address: registry, topics: [event.topicHash], fromBlock: start, toBlock });
+        logs.forEach(log => {
+            const { asset, denomination } = FEED_REGISTRY_INTERFACE.parseLog(log).args;
+            pairs.set(`${asset}/${denomination}`.toLowerCase(), { base: asset, quote: denomination });
+        });
+    }
+    if (pairs.size === 0) {
+        return [];
+    }
+
+    const entries = [...pairs.values()];
+    const multicall = new ethers.Contract(multicallAddress, MULTICALL3_AGGREGATE3_INTERFACE, provider);
+    const results = await multicall.aggregate3.staticCall(entries.map(({ base, quote }) => ({
+        target: registry,
+        allowFailure: true,
+        callData: FEED_REGISTRY_INTERFACE.encodeFunctionData('getFeed', [base, quote])
+    })));
+
+    return entries
+        .map((entry, i) => {
+            const [success, returnData] = results[i];
+            try {
+                const [feed] = success ? FEED_REGISTRY_INTERFACE.decodeFunctionResult('getFeed', returnData) : [ethers.ZeroAddress];
+                return { ...entry, feed };
+            } catch (error) {
+                return { ...entry, feed: ethers.ZeroAddress };
+            }
+        })
+        .filter(entry => entry.feed !== ethers.ZeroAddress);
+}
+
+// Existing rows keyed by lowercased proxy, so off-chain columns (heartbeat, deviation, ...) carry over
+function loadExistingFeeds(file) {
+    if (!file || !fs.existsSync(file)) {
+        return Promise.resolve(new Map());
+    }
+    return new Promise((resolve, reject) => {
+        const rows = new Map();
+        fs.createReadStream(file)
+            .pipe(csv())
+            .on('data', row => rows.set(row.proxy_address.toLowerCase(), row))
+            .on('end', () => resolve(rows))
+            .on('error', reject);
+    });
+}
+
+async function discoverFeeds(provider, addresses, multicallAddress = MULTICALL3_ADDRESS) {
+    const multicall = new ethers.Contract(multicallAddress, MULTICALL3_AGGREGATE3_INTERFACE, provider);
+    const calls = addresses.flatMap(target => DISCOVERY_FIELDS.map(field => ({
+        target,
+        allowFailure: true,
+        callData: METADATA_INTERFACE.encodeFunctionData(field, [])
+    })));
+
+    const results = await multicall.aggregate3.staticCall(calls);
+
+    return addresses.map((proxy, i) => {
+        const feed = { proxy };
+        DISCOVERY_FIELDS.forEach((field, j) => {
+            const [success, returnData] = results[i * DISCOVERY_FIELDS.length + j];
+            try {
+                feed[field] = success && returnData !== '0x'
+                    ? METADATA_INTERFACE.decodeFunctionResult(field, returnData)[0]
+                    : null;
+            } catch (error) {
+                feed[field] = null;
+            }
+        });
+        return feed;
+    });
+}
+
+// "BTC / USD" -> btc-usd; other descriptions are slugged as-is
+function slug(description) {
+    return description.toLowerCase().replace(/\s*\/\s*/g, '-').replace(/\s+/g, '-');
+}
+
+function buildRow(feed, existing) {
+    const [base, quote] = feed.description.includes(' / ') ? feed.description.split(' / ') : ['', ''];
+
+    return {
+        name: feed.description,
+        contract_address: feed.aggregator || existing?.contract_address || '',
+        proxy_address: feed.proxy,
+        deviation_threshold: existing?.deviation_threshold ?? '',
+        heartbeat: existing?.heartbeat ?? '',
+        decimals: Number(feed.decimals).toString(),
+        asset_class: existing?.asset_class ?? '',
+        product_name: existing?.product_name ?? '',
+        ens: existing?.ens || slug(feed.description).replace(/[^a-z0-9-]/g, ''),
+        path: existing?.path || slug(feed.description),
+        base_asset: existing?.base_asset || base,
+        quote_asset: existing?.quote_asset || quote,
+        kind: existing?.kind ?? ''
+    };
+}
+
+// The optional kind column is only written when some feed overrides its kind
+function columnsFor(rows) {
+    return rows.some(row => row.kind) ? [...COLUMNS, 'kind'] : COLUMNS;
+}
+
+function csvField(value) {
+    return /[",\n]/.test(value) ? `"${value.replace(/"/g, '""')}"` : value;
+}
+
+function toCsv(rows) {
+    const columns = columnsFor(rows);
+    return [columns.join(','), ...rows.map(row => columns.map(column => csvField(String(row[column]))).join(','))].join('\n') + '\n';
+}
+
+// JSON strings are valid YAML scalars, which keeps names like "GLV [AVAX-USDC] / USD" safe
+function toYaml(rows) {
+    const columns = columnsFor(rows);
+    return 'feeds:\n' + rows.map(row => columns
+        .map((column, i) => `${i === 0 ? '  - ' : '    '}${column}: ${JSON.stringify(String(row[column]))}`)
+        .join('\n')).join('\n') + '\n';
+}
+
+async function generateFeeds(argv = process.argv.slice(2)) {
+    const { values, positionals } = parseArgs({
+        args: argv,
+        allowPositionals: true,
+        options: {
+            addresses: { type: 'string' },
+            registry: { type: 'string' },
+            'from-block': { type: 'string', default: '0' },
+            'block-range': { type: 'string', default: String(DEFAULT_LOG_PAGE_BLOCKS) },
+            chain: { type: 'string', default: process.env.CHAIN || 'avalanche' },
+            rpc: { type: 'string' },
+            merge: { type: 'string' },
+            format: { type: 'string', default: 'csv' },
+            out: { type: 'string' }
+        }
+    });
+
+    if (!FORMATS.includes(values.format)) {
+        throw new Error(`Unsupported --format ${values.format} (expected ${FORMATS.join(' or ')})`);
+    }
+
+    const chain = resolveChain(values.chain);
+    const provider = new ethers.JsonRpcProvider(values.rpc || chain.rpcUrl);
+    const addresses = readAddresses(positionals, values.addresses);
+
+    if (values.registry) {
+        console.log(`🔍 Scraping FeedConfirmed events from registry ${values.registry}...`);
+        const scraped = await scrapeRegistry(provider, values.registry, {
+            fromBlock: Number(values['from-block']),
+            pageSize: Number(values['block-range']),
+            multicallAddress: chain.multicall3
+        });
+        console.log(`📋 Registry serves ${scraped.length} feeds`);
+        const feeds = scraped.map(entry => entry.feed);
+        addresses.push(...readAddresses(feeds.filter(a => !addresses.some(b => b.toLowerCase() === a.toLowerCase()))));
+    }
+
+    if (addresses.length === 0) {
+        throw new Error('No feeds to discover: pass proxy addresses, --addresses <file> or --registry <address>');
+    }
+
+    console.log(`🔗 Reading description, decimals and aggregator for ${addresses.length} feeds...`);
+    const feeds = await discoverFeeds(provider, addresses, chain.multicall3);
+    const existing = await loadExistingFeeds(values.merge || chain.feedsFile);
+
+    const unreadable = feeds.filter(feed => feed.description === null || feed.decimals === null);
+    unreadable.forEach(feed => console.log(`⚠️  Skipping ${feed.proxy}: not a readable price feed`));
+
+    const rows = feeds
+        .filter(feed => !unreadable.includes(feed))
+        .map(feed => buildRow(feed, existing.get(feed.proxy.toLowerCase())));
+
+    const missing = rows.filter(row => row.heartbeat === '' || row.deviation_threshold === '');
+    if (missing.length > 0) {
+        console.log(`📝 ${missing.length} new feed(s) need heartbeat and deviation_threshold filled in; they aren't readable on-chain`);
+    }
+
+    const output = values.format === 'csv' ? toCsv(rows) : toYaml(rows);
+    const outputFile = values.out || `./generated_feeds.${values.format}`;
+    fs.writeFileSync(outputFile, output);
+    console.log(`✅ Wrote ${rows.length} feeds to ${outputFile}`);
+
+    return rows;
+}
+
+// Execute if run directly
+if (require.main === module) {
+    generateFeeds().catch(err => {
+        console.error('❌ Feed generation failed:', err.message);
+        process.exit(1);
+    });
+}
+
+module.exports = { generateFeeds, readAddresses, scrapeRegistry, discoverFeeds, buildRow, toCsv, toYaml, FEED_REGISTRY_INTERFACE };
@@ -0,0 +1,167 @@
+#!/usr/bin/env node
+
+// Query Stored Price Snapshots
+// Answers quick questions from a shell against the snapshots the fetcher stored:
+//   latest [feed]               newest reading per feed
+//   history <feed> --since 24h  every reading of one feed in a range
+//   stats <feed> --since 7d     range, mean, volatility and freshness of one feed
+// Reads the avalanche_prices_*.json files in --dir / SNAPSHOT_DIR, or the binary
+// log given by --log / SNAPSHOT_LOG; --profile takes both from profiles.json.
+// --chain / CHAIN (default avalanche) picks the chain: its snapshot directory is the
+// default --dir, and only its snapshots are read when a directory or log holds several.
+
+const path = require('path');
+const { parseArgs } = require('util');
+const { loadSnapshots, buildHistory, summarize, parseTime } = require('./export');
+const { readSnapshotLog } = require('../snapshot_log');
+const { applyProfile } = require('../profiles');
+const { resolveChain, snapshotChain } = require('../chains');
+
+const COMMANDS = ['latest', 'history', 'stats'];
+const DURATION_UNITS = { m: 60 * 1000, h: 60 * 60 * 1000, d: 24 * 60 * 60 * 1000, w: 7 * 24 * 60 * 60 * 1000 };
+
+// --since takes a duration back from now (30m, 24h, 7d, 2w) or an RFC 3339 time
+function parseSince(value, now = Date.now()) {
+    if (!value) return null;
+    const match = /^(\d+)([mhdw])$/.exec(value);
+    if (match) {
+        return now - Number(match[1]) * DURATION_UNITS[match[2]];
+    }
+    return parseTime(value, 'since');
+}
+
+async function loadStoredSnapshots({ dir, log, from = null, to = null, chain }) {
+    const snapshots = [];
+    if (!log) {
+        snapshots.push(...loadSnapshots(dir, from, to));
+    } else {
+        for await (const snapshot of readSnapshotLog(log, { from: from ?? -Infinity, to: to ?? Infinity })) {
+            snapshots.push({ ...snapshot, at: Date.parse(snapshot.blockTimestamp) });
+        }
+    }
+    return chain ? snapshots.filter(snapshot => snapshotChain(snapshot) === chain) : snapshots;
+}
+
+// Feeds match by name ignoring case and punctuation ("btc/usd", "BTC / USD") or by proxy address
+function findFeed(feeds, query) {
+    const normalize = value => value.toLowerCase().replace(/[^a-z0-9]/g, '');
+    const wanted = normalize(query);
+    const feed = feeds.find(entry => normalize(entry.name) === wanted || entry.proxy.toLowerCase() === query.toLowerCase());
+    if (!feed) {
+        throw new Error(`No stored readings for feed ${query}`);
+    }
+    return feed;
+}
+
+function latest(feeds, query) {
+    const selected = query ? [findFeed(feeds, query)] : feeds;
+    return selected.map(feed => {
+        const row = feed.rows[feed.rows.length - 1];
+        return { feed: feed.name, price: row.price, updatedAt: row.updatedAt, sampledAt: row.sampledAt, block: row.blockNumber };
+    });
+}
+
+function history(feeds, query) {
+    return findFeed(feeds, query).rows.map(row => ({
+        sampledAt: row.sampledAt,
+        block: row.blockNumber,
+        price: row.price,
+        roundId: row.roundId,
+        updatedAt: row.updatedAt
+    }));
+}
+
+// Readings repeat until the feed updates, so rounds are counted once each
+function stats(feeds, query) {
+    const feed = findFeed(feeds, query);
+    const prices = feed.rows.map(row => row.price);
+    const mean = prices.reduce((sum, price) => sum + price, 0) / prices.length;
+    const variance = prices.reduce((sum, price) => sum + (price - mean) ** 2, 0) / prices.length;
+    const ages = feed.rows.map(row => row.ageAtBlock).filter(age => age !== '');
+
+    return {
+        ...summarize(feed),
+        mean,
+        stdDev: Math.sqrt(variance),
+        rounds: new Set(feed.rows.map(row => row.roundId).filter(Boolean)).size,
+        meanAgeAtBlock: ages.length > 0 ? Math.round(ages.reduce((sum, age) => sum + age, 0) / ages.length) : null,
+        maxAgeAtBlock: ages.length > 0 ? Math.max(...ages) : null
+    };
+}
+
+function formatTable(rows) {
+    if (rows.length === 0) return '(no rows)';
+    const columns = Object.keys(rows[0]);
+    const cells = rows.map(row => columns.map(column => String(row[column] ?? '')));
+    const widths = columns.map((column, i) => Math.max(column.length, ...cells.map(line => line[i].length)));
+    const line = values => values.map((value, i) => value.padEnd(widths[i])).join('  ').trimEnd();
+
+    return [line(columns), line(widths.map(width => '-'.repeat(width))), ...cells.map(line)].join('\n');
+}
+
+function formatRecord(record) {
+    const width = Math.max(...Object.keys(record).map(key => key.length));
+    return Object.entries(record).map(([key, value]) => `${key.padEnd(width)}  ${value ?? ''}`).join('\n');
+}
+
+async function query(argv = process.argv.slice(2)) {
+    const { values, positionals } = parseArgs({
+        args: argv,
+        allowPositionals: true,
+        options: {
+            since: { type: 'string' },
+            until: { type: 'string' },
+            dir: { type: 'string' },
+            log: { type: 'string' },
+            chain: { type: 'string' },
+            profile: { type: 'string', default: process.env.PROFILE },
+            json: { type: 'boolean', default: false }
+        }
+    });
+    if (values.profile) {
+        applyProfile(values.profile);
+    }
+    const chain = resolveChain(values.chain);
+    const dir = values.dir ?? process.env.SNAPSHOT_DIR ?? chain.snapshotDir;
+    const log = values.log ?? process.env.SNAPSHOT_LOG;
+
+    const [command, feed] = positionals;
+    if (!COMMANDS.includes(command)) {
+        throw new Error(`Usage: query <${COMMANDS.join('|')}> [feed] [--since 24h] [--until <time>] [--dir .] [--log file] [--chain name] [--profile name] [--json]`);
+    }
+    if (command !== 'latest' && !feed) {
+        throw new Error(`${command} needs a feed, e.g. query ${command} "BTC / USD"`);
+    }
+
+    const from = parseSince(values.since);
+    const to = parseTime(values.until, 'until');
+    const source = log ? log : path.resolve(dir);
+    console.error(`📂 Reading snapshots from ${source}...`);
+
+    const snapshots = await loadStoredSnapshots({ dir, log, from, to, chain: chain.name });
+    if (snapshots.length === 0) {
+        throw new Error('No snapshots found in the requested range');
+    }
+    const feeds = buildHistory(snapshots);
+
+    const result = command === 'latest' ? latest(feeds, feed)
+        : command === 'history' ? history(feeds, feed)
+        : stats(feeds, feed);
+
+    if (values.json) {
+        console.log(JSON.stringify(result, null, 2));
+    } else {
+        console.log(Array.isArray(result) ? formatTable(result) : formatRecord(result));
+    }
+    return result;
+}
+
+// Execute if run directly
+if (require.main === module) {
+    query().catch(err => {
+        console.error('❌ Query failed:', err.message);
+        process.exit(1);
+    });
+}
+
+module.exports = { query, parseSince, loadStoredSnapshots, findFeed, latest, history, stats, formatTable };
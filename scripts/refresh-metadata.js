@@ -0,0 +1,201 @@
+#!/usr/bin/env node
+
+// Refresh On-Chain Feed Metadata
+// Re-reads decimals, description, aggregator, phase and answer bounds for every
+// configured feed (two Multicalls: proxies, then their current aggregators),
+// stores them in the chain's metadata file (feed_metadata.json on mainnet) and
+// reports what changed since the last refresh
+
+const fs = require('fs');
+const { ethers } = require('ethers');
+const { loadFeedData } = require('../multicall_price_fetcher.js');
+const { resolveChain, feedsFileFor, metadataFileFor } = require('../chains');
+
+// aggregate3 lets individual reads fail: PoR and custom aggregators don't all expose min/max bounds
+const MULTICALL3_AGGREGATE3_INTERFACE = new ethers.Interface(require('../abi/Multicall3.json'));
+
+// Proxy reads, then bounds from the underlying aggregator
+const METADATA_INTERFACE = new ethers.Interface([
+    'function decimals() view returns (uint8)',
+    'function description() view returns (string)',
+    'function aggregator() view returns (address)',
+    'function phaseId() view returns (uint16)',
+    'function minAnswer() view returns (int192)',
+    'function maxAnswer() view returns (int192)'
+]);
+
+const PROXY_FIELDS = ['decimals', 'description', 'aggregator', 'phaseId'];
+const AGGREGATOR_FIELDS = ['minAnswer', 'maxAnswer'];
+const FIELDS = [...PROXY_FIELDS, ...AGGREGATOR_FIELDS];
+
+function fieldCalls(target, fields) {
+    return fields.map(field => ({
+        target,
+        allowFailure: true,
+        callData: METADATA_INTERFACE.encodeFunctionData(field, [])
+    }));
+}
+
+// Values are stored as strings/numbers so the file diffs cleanly; failed reads become null
+function decodeFields(fields, results, offset) {
+    const entry = {};
+    fields.forEach((field, fieldIndex) => {
+        const [success, returnData] = results[offset + fieldIndex];
+        if (!success || returnData === '0x') {
+            entry[field] = null;
+            return;
+        }
+
+        try {
+            const [value] = METADATA_INTERFACE.decodeFunctionResult(field, returnData);
+            entry[field] = typeof value === 'bigint'
+                ? (field === 'decimals' || field === 'phaseId' ? Number(value) : value.toString())
+                : value;
+        } catch (error) {
+            entry[field] = null;
+        }
+    });
+    return entry;
+}
+
+// First pass: everything read from the proxy, including the aggregator it points at now
+function buildProxyCalls(feeds) {
+    return feeds.flatMap(feed => fieldCalls(feed.proxyAddress, PROXY_FIELDS));
+}
+
+// Second pass: bounds from the aggregator the first pass found. The CSV address is only
+// used for proxies whose aggregator() couldn't be read, since it goes stale on every upgrade
+function buildBoundsCalls(feeds, proxyResults) {
+    return feeds.flatMap((feed, feedIndex) => {
+        const { aggregator } = decodeFields(PROXY_FIELDS, proxyResults, feedIndex * PROXY_FIELDS.length);
+        return fieldCalls(aggregator || feed.contractAddress, AGGREGATOR_FIELDS);
+    });
+}
+
+function decodeMetadata(feeds, proxyResults, boundsResults) {
+    const metadata = {};
+
+    feeds.forEach((feed, feedIndex) => {
+        metadata[feed.proxyAddress.toLowerCase()] = {
+            name: feed.name,
+            ...decodeFields(PROXY_FIELDS, proxyResults, feedIndex * PROXY_FIELDS.length),
+            ...decodeFields(AGGREGATOR_FIELDS, boundsResults, feedIndex * AGGREGATOR_FIELDS.length)
+        };
+    });
+
+    return metadata;
+}
+
+function diffMetadata(previous, current) {
+    const changes = [];
+
+    Object.entries(current).forEach(([proxy, entry]) => {
+        const before = previous[proxy];
+        if (!before) {
+            changes.push({ proxy, name: entry.name, type: 'added' });
+            return;
+        }
+
+        FIELDS.forEach(field => {
+            if (before[field] !== entry[field]) {
+                changes.push({ proxy, name: entry.name, type: 'changed', field, from: before[field], to: entry[field] });
+            }
+        });
+    });
+
+    Object.entries(previous).forEach(([proxy, entry]) => {
+        if (!current[proxy]) {
+            changes.push({ proxy, name: entry.name, type: 'removed' });
+        }
+    });
+
+    return changes;
+}
+
+// The CSV records each feed's aggregator; a different on-chain value means the proxy was repointed
+function findAggregatorMismatches(feeds, metadata) {
+    return feeds.filter(feed => {
+        const aggregator = metadata[feed.proxyAddress.toLowerCase()]?.aggregator;
+        return aggregator && aggregator.toLowerCase() !== feed.contractAddress.toLowerCase();
+    });
+}
+
+function loadPreviousMetadata(file) {
+    if (!fs.existsSync(file)) {
+        return { feeds: {} };
+    }
+    return JSON.parse(fs.readFileSync(file, 'utf8'));
+}
+
+// Each chain keeps its own file, so a testnet refresh never diffs against or overwrites mainnet metadata
+async function refreshMetadata(file, { provider } = {}) {
+    console.log('🔄 Refreshing on-chain feed metadata...\n');
+
+    const chain = resolveChain();
+    file = metadataFileFor(chain, file);
+    const feeds = await loadFeedData(feedsFileFor(chain));
+    provider ??= new ethers.JsonRpcProvider(process.env.RPC_URL || chain.rpcUrl);
+    const multicall = new ethers.Contract(chain.multicall3, MULTICALL3_AGGREGATE3_INTERFACE, provider);
+
+    // Both passes read the same block, so bounds always belong to the aggregator reported beside them
+    const blockNumber = await provider.getBlockNumber();
+    const proxyCalls = buildProxyCalls(feeds);
+    const proxyResults = await multicall.aggregate3.staticCall(proxyCalls, { blockTag: blockNumber });
+    const boundsCalls = buildBoundsCalls(feeds, proxyResults);
+    const boundsResults = await multicall.aggregate3.staticCall(boundsCalls, { blockTag: blockNumber });
+    console.log(`🔗 Read ${proxyCalls.length + boundsCalls.length} metadata fields for ${feeds.length} feeds at block ${blockNumber}`);
+
+    const previous = loadPreviousMetadata(file);
+    const current = decodeMetadata(feeds, proxyResults, boundsResults);
+    const changes = diffMetadata(previous.feeds, current);
+
+    fs.writeFileSync(file, JSON.stringify({
+        refreshedAt: new Date().toISOString(),
+        blockNumber: blockNumber.toString(),
+        feeds: current
+    }, null, 2));
+
+    if (!previous.refreshedAt) {
+        console.log(`📋 First metadata snapshot saved to ${file}`);
+    } else if (changes.length === 0) {
+        console.log(`✅ No metadata changes since ${previous.refreshedAt}`);
+    } else {
+        console.log(`🆕 ${changes.length} metadata change(s) since ${previous.refreshedAt}:`);
+        changes.forEach(change => {
+            if (change.type === 'changed') {
+                console.log(`   ~ ${change.name}: ${change.field} ${change.from} → ${change.to}`);
+            } else {
+                console.log(`   ${change.type === 'added' ? '+' : '-'} ${change.name}`);
+            }
+        });
+    }
+
+    const mismatches = findAggregatorMismatches(feeds, current);
+    if (mismatches.length > 0) {
+        console.log(`\n⚠️  ${mismatches.length} feed(s) point at a different aggregator than the CSV records:`);
+        mismatches.forEach(feed => {
+            console.log(`   ${feed.name}: CSV ${feed.contractAddress}, on-chain ${current[feed.proxyAddress.toLowerCase()].aggregator}`);
+        });
+    }
+
+    return { changes, mismatches, blockNumber };
+}
+
+// Execute if run directly
+if (require.main === module) {
+    refreshMetadata().catch(err => {
+        console.error('❌ Metadata refresh failed:', err);
+        process.exit(1);
+    });
+}
+
+module.exports = {
+    refreshMetadata,
+    buildProxyCalls,
+    buildBoundsCalls,
+    decodeMetadata,
+    diffMetadata,
+    findAggregatorMismatches,
+    MULTICALL3_AGGREGATE3_INTERFACE,
+    METADATA_INTERFACE
+};
@@ -0,0 +1,45 @@
+// Feed sharding across fetcher instances
+// Each feed is assigned to a shard by hashing its proxy address, so every
+// instance computes the same split independently and adding a feed never moves
+// the others. Shards are numbered 0..count-1.
+
+const crypto = require('crypto');
+
+function validateShard(shard) {
+  const { index, count } = shard;
+  if (!Number.isInteger(count) || count < 1) {
+    throw new Error(`Shard count must be a positive integer, got ${count}`);
+  }
+  if (!Number.isInteger(index) || index < 0 || index >= count) {
+    throw new Error(`Shard index must be an integer from 0 to ${count - 1}, got ${index}`);
+  }
+  return shard;
+}
+
+// Addresses are compared case-insensitively, so checksummed and lowercase entries agree
+function shardOf(address, count) {
+  const digest = crypto.createHash('sha256').update(address.toLowerCase()).digest();
+  return digest.readUInt32BE(0) % count;
+}
+
+function inShard(address, shard) {
+  return !shard || shardOf(address, shard.count) === shard.index;
+}
+
+// SHARD_INDEX and SHARD_COUNT select this instance's share of the feed list
+function shardOptions(env = process.env) {
+  if (env.SHARD_COUNT === undefined && env.SHARD_INDEX === undefined) {
+    return {};
+  }
+  if (env.SHARD_COUNT === undefined || env.SHARD_INDEX === undefined) {
+    throw new Error('Sharding requires both SHARD_INDEX and SHARD_COUNT');
+  }
+  return { shard: validateShard({ index: Number(env.SHARD_INDEX), count: Number(env.SHARD_COUNT) }) };
+}
+
+module.exports = {
+  validateShard,
+  shardOf,
+  inShard,
+  shardOptions
+};
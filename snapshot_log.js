@@ -0,0 +1,205 @@
+// Binary snapshot log for fast local replay
+// Each fetch cycle is appended as a varint length prefix followed by a protobuf
+// Snapshot (see snapshot.proto); readSnapshotLog streams them back in order
+
+const fs = require('fs');
+const path = require('path');
+const protobuf = require('protobufjs');
+const { formatAnswer } = require('./feed_kinds');
+const { createVerificationStats, verifyWrite, writeSynced } = require('./write_verification');
+
+const root = protobuf.loadSync(path.join(__dirname, 'snapshot.proto'));
+const Snapshot = root.lookupType('cchainlink.Snapshot');
+
+function toSeconds(iso) {
+  return Math.floor(Date.parse(iso) / 1000);
+}
+
+function encodeSnapshot(snapshot) {
+  const message = Snapshot.fromObject({
+    blockNumber: snapshot.blockNumber,
+    blockHash: snapshot.blockHash || '',
+    blockTimestamp: toSeconds(snapshot.blockTimestamp),
+    timestampMs: Date.parse(snapshot.timestamp),
+    chain: snapshot.chain || '',
+    chainId: snapshot.chainId || 0,
+    testnet: snapshot.testnet || false,
+    rpcUrl: snapshot.rpcUrl || '',
+    backfill: snapshot.backfill || false,
+    shard: snapshot.shard || null,
+    prices: snapshot.prices.map(result => result.error
+      ? { name: result.name, proxy: result.proxy, source: result.source || 'chainlink', error: result.error }
+      : {
+          name: result.name,
+          proxy: result.proxy,
+          source: result.source,
+          kind: result.kind,
+          answer: result.raw.answer ?? result.raw.value,
+          decimals: result.decimals,
+          roundId: result.roundId || '',
+          updatedAt: result.updatedAt ? toSeconds(result.updatedAt) : 0,
+          method: result.method || '',
+          oracleId: result.oracleId || ''
+        })
+  });
+  return Snapshot.encodeDelimited(message).finish();
+}
+
+// Rebuilds the JSON snapshot shape written by fileSink, minus startedAt/answeredInRound
+function decodeSnapshot(bytes) {
+  const message = Snapshot.toObject(Snapshot.decode(bytes), { longs: String, defaults: true });
+  const blockTimestamp = Number(message.blockTimestamp);
+  // Untagged records predate chain tagging; leave the fields off rather than guess
+  const tag = message.chain
+    ? { chain: message.chain, chainId: Number(message.chainId), testnet: message.testnet }
+    : {};
+
+  return {
+    blockNumber: message.blockNumber,
+    blockTimestamp: new Date(blockTimestamp * 1000).toISOString(),
+    timestamp: new Date(Number(message.timestampMs)).toISOString(),
+    ...tag,
+    ...(message.blockHash ? { blockHash: message.blockHash } : {}),
+    ...(message.rpcUrl ? { rpcUrl: message.rpcUrl } : {}),
+    ...(message.shard ? { shard: { index: message.shard.index, count: message.shard.count } } : {}),
+    ...(message.backfill ? { backfill: true } : {}),
+    totalFeeds: message.prices.length,
+    prices: message.prices.map(entry => {
+      if (entry.error) {
+        return { name: entry.name, proxy: entry.proxy, source: entry.source, error: entry.error };
+      }
+
+      const exactPrice = formatAnswer(BigInt(entry.answer), entry.decimals);
+      const result = {
+        name: entry.name,
+        proxy: entry.proxy,
+        source: entry.source,
+        kind: entry.kind,
+        price: Number(exactPrice),
+        exactPrice,
+        decimals: entry.decimals
+      };
+      if (entry.source === 'chainlink') {
+        const updatedAt = Number(entry.updatedAt);
+        result.roundId = entry.roundId;
+        result.updatedAt = new Date(updatedAt * 1000).toISOString();
+        result.ageAtBlock = blockTimestamp - updatedAt;
+        result.raw = { answer: entry.answer };
+      } else {
+        result.method = entry.method;
+        result.raw = { value: entry.answer };
+        if (entry.oracleId) {
+          result.oracleId = entry.oracleId;
+        }
+        // Oracles reporting their own timestamp (ERC-2362) keep it
+        if (Number(entry.updatedAt) > 0) {
+          result.updatedAt = new Date(Number(entry.updatedAt) * 1000).toISOString();
+          result.ageAtBlock = blockTimestamp - Number(entry.updatedAt);
+        }
+      }
+      return result;
+    })
+  };
+}
+
+// Bytes [offset, offset + length) of file
+function readRange(file, offset, length) {
+  const fd = fs.openSync(file, 'r');
+  try {
+    const bytes = Buffer.alloc(length);
+    const read = fs.readSync(fd, bytes, 0, length, offset);
+    return bytes.subarray(0, read);
+  } finally {
+    fs.closeSync(fd);
+  }
+}
+
+// Fetcher sink appending one record per cycle. With verify, the appended record is synced
+// and read back from where it should have landed; on a mismatch the log is truncated back
+// to its previous end, so readers never meet a corrupt record. Assumes a single writer per log.
+function binarySink({ file = process.env.SNAPSHOT_LOG || './snapshots.pb', verify = false } = {}) {
+  const verification = createVerificationStats();
+  return {
+    name: 'binary',
+    verification,
+    write(snapshot) {
+      const record = encodeSnapshot(snapshot);
+      if (!verify) {
+        fs.appendFileSync(file, record);
+        return;
+      }
+
+      const offset = fs.existsSync(file) ? fs.statSync(file).size : 0;
+      writeSynced(file, record, 'a');
+      try {
+        verifyWrite(file, record, () => readRange(file, offset, record.length), verification);
+      } catch (error) {
+        fs.truncateSync(file, offset);
+        throw error;
+      }
+    },
+
+    // Newest snapshot this shard appended, for catch-up after downtime
+    async last({ shard } = {}) {
+      if (!fs.existsSync(file)) return null;
+      let newest = null;
+      for await (const snapshot of readSnapshotLog(file)) {
+        if (sameShard(snapshot.shard, shard)) {
+          newest = snapshot;
+        }
+      }
+      return newest;
+    }
+  };
+}
+
+function sameShard(a, b) {
+  return a && b ? a.index === b.index && a.count === b.count : !a && !b;
+}
+
+// Varint length prefix at `offset`; null when the buffer ends mid-prefix
+function readLength(buffer, offset) {
+  let length = 0;
+  for (let shift = 0, i = offset; i < buffer.length; shift += 7, i++) {
+    length += (buffer[i] & 0x7f) * 2 ** shift;
+    if ((buffer[i] & 0x80) === 0) {
+      return { length, start: i + 1 };
+    }
+  }
+  return null;
+}
+
+// Streams snapshots without loading the whole log; optional from/to (ms) filter on block time
+async function* readSnapshotLog(file, { from = -Infinity, to = Infinity } = {}) {
+  let buffer = Buffer.alloc(0);
+
+  for await (const chunk of fs.createReadStream(file)) {
+    buffer = buffer.length === 0 ? chunk : Buffer.concat([buffer, chunk]);
+
+    let offset = 0;
+    for (;;) {
+      const prefix = readLength(buffer, offset);
+      if (!prefix || prefix.start + prefix.length > buffer.length) break;
+
+      const snapshot = decodeSnapshot(buffer.subarray(prefix.start, prefix.start + prefix.length));
+      offset = prefix.start + prefix.length;
+
+      const blockTime = Date.parse(snapshot.blockTimestamp);
+      if (blockTime >= from && blockTime <= to) {
+        yield snapshot;
+      }
+    }
+    buffer = buffer.subarray(offset);
+  }
+
+  if (buffer.length > 0) {
+    throw new Error(`Snapshot log ${file} ends with a truncated record (${buffer.length} bytes)`);
+  }
+}
+
+module.exports = {
+  encodeSnapshot,
+  decodeSnapshot,
+  binarySink,
+  readSnapshotLog
+};
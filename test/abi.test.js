@@ -1,12 +1,13 @@
 // ABI and interface tests
 const fs = require('fs');
 const { ethers } = require('ethers');
+const { staleBindings, generateBindings, constantName } = require('../scripts/generate-bindings');
 
 describe('Chainlink ABI Interface', () => {
   let abi;
   
   beforeAll(() => {
-    const abiFile = fs.readFileSync('./chainlink_abi_interface.json', 'utf8');
+    const abiFile = fs.readFileSync('./abi/AggregatorV3Interface.json', 'utf8');
     abi = JSON.parse(abiFile);
   });
 
@@ -78,4 +79,17 @@ describe('Chainlink ABI Interface', () => {
       expect(['view', 'pure']).toContain(func.stateMutability);
     });
   });
-});
\ No newline at end of file
+
+  test('generated bindings match the source ABIs', () => {
+    expect(staleBindings().map(({ file }) => file)).toEqual([]);
+  });
+
+  test('generated TypeScript types each function result', () => {
+    const multicall = generateBindings().find(({ file }) => file.endsWith('Multicall3.ts')).contents;
+
+    expect(constantName('AggregatorV3Interface')).toBe('AGGREGATOR_V3_INTERFACE');
+    expect(multicall).toContain('export const MULTICALL3_ABI = [');
+    expect(multicall).toContain('export interface Call3Struct {\n  target: string;\n  allowFailure: boolean;\n  callData: string;\n}');
+    expect(multicall).toContain('export type Aggregate3Output = ResultStruct[];');
+  });
+});
@@ -0,0 +1,131 @@
+// Audit export tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const crypto = require('crypto');
+const { auditExport, buildBundle, signBundle, verifyBundle, readBundle, BUNDLE_FORMAT } = require('../scripts/audit-export');
+
+describe('Audit Export', () => {
+  let dir;
+  let keyFile;
+  let privateKey;
+  let publicKey;
+  const at = Date.parse('2025-07-21T00:00:00Z');
+
+  const snapshot = (ms, blockNumber) => ({
+    blockNumber: String(blockNumber),
+    blockTimestamp: new Date(ms).toISOString(),
+    timestamp: new Date(ms).toISOString(),
+    chain: 'avalanche',
+    chainId: 43114,
+    testnet: false,
+    rpcUrl: 'https://api.avax.network',
+    totalFeeds: 2,
+    prices: [
+      {
+        name: 'BTC / USD',
+        proxy: '0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743',
+        source: 'chainlink',
+        price: 118500.12345678,
+        roundId: '1',
+        raw: { answer: '11850012345678' }
+      },
+      { name: 'ggAVAX', proxy: '0xA25EaF2906FA1a3a13EdAc9B9657108Af7B703e3', source: 'erc4626', decimals: 18, method: 'convertToAssets(uint256)', raw: { value: '1' } }
+    ]
+  });
+
+  // Serves each block at the timestamp the snapshots recorded
+  const provider = {
+    getBlock: async number => ({ number, hash: `0x${String(number).padStart(64, '0')}`, timestamp: (at + (number - 100) * 60000) / 1000 })
+  };
+
+  beforeAll(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'audit-'));
+    ({ privateKey, publicKey } = crypto.generateKeyPairSync('ed25519', {
+      privateKeyEncoding: { type: 'pkcs8', format: 'pem' },
+      publicKeyEncoding: { type: 'spki', format: 'pem' }
+    }));
+    keyFile = path.join(dir, 'audit.pem');
+    fs.writeFileSync(keyFile, privateKey);
+
+    [0, 1, 2].forEach(i => {
+      const ms = at + i * 60000;
+      fs.writeFileSync(path.join(dir, `avalanche_prices_${ms}.json`), JSON.stringify(snapshot(ms, 100 + i)));
+    });
+  });
+
+  afterAll(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('bundles metadata, raw answers, block hashes, endpoints and version', async () => {
+    const payload = await buildBundle({
+      snapshots: [snapshot(at, 100), snapshot(at + 60000, 101)],
+      provider,
+      rpcUrl: 'https://api.avax.network/ext/bc/C/rpc',
+      now: at
+    });
+
+    expect(payload.format).toBe(BUNDLE_FORMAT);
+    expect(payload.chain).toEqual({ chain: 'avalanche', chainId: 43114, testnet: false });
+    expect(payload.software.name).toBe('avalanche-chainlink-prices');
+    expect(payload.rpcEndpoints).toEqual({ snapshots: ['https://api.avax.network'], blockHashes: 'https://api.avax.network' });
+    expect(payload.blocks.map(block => block.number)).toEqual(['100', '101']);
+    expect(payload.blocks[0].hash).toBe(`0x${'100'.padStart(64, '0')}`);
+    expect(payload.snapshots[0].prices[0].raw.answer).toBe('11850012345678');
+
+    const btc = payload.feeds.find(feed => feed.name === 'BTC / USD');
+    expect(btc.contractAddress).toBeDefined();
+    expect(btc.heartbeat).toBeDefined();
+    expect(payload.feeds.find(feed => feed.name === 'ggAVAX')).toMatchObject({ source: 'erc4626', decimals: 18 });
+  });
+
+  test('refuses blocks whose timestamp moved on chain', async () => {
+    const reorged = { getBlock: async number => ({ number, hash: '0x01', timestamp: 1 }) };
+    const error = await buildBundle({ snapshots: [snapshot(at, 100)], provider: reorged }).catch(err => err);
+    expect(error.message).toContain('Block 100 has timestamp');
+  });
+
+  test('checks block hashes recorded at fetch time', async () => {
+    const hash = `0x${'100'.padStart(64, '0')}`;
+    const payload = await buildBundle({ snapshots: [{ ...snapshot(at, 100), blockHash: hash }, snapshot(at + 60000, 101)], provider });
+    expect(payload.blocks.map(block => block.recordedHash)).toEqual([true, false]);
+
+    const replaced = { ...snapshot(at, 100), blockHash: `0x${'ff'.repeat(32)}` };
+    const error = await buildBundle({ snapshots: [replaced], provider }).catch(err => err);
+    expect(error.message).toContain(`Block 100 has hash ${hash} on chain`);
+  });
+
+  test('signatures detect tampering and a different signer', () => {
+    const bundle = signBundle({ format: BUNDLE_FORMAT, snapshots: [] }, privateKey);
+    expect(verifyBundle(bundle, publicKey)).toEqual({ valid: true, reason: null, trustedKey: true });
+
+    const tampered = JSON.parse(JSON.stringify(bundle));
+    tampered.payload.snapshots.push({ blockNumber: '1' });
+    expect(verifyBundle(tampered).reason).toBe('payload checksum mismatch');
+
+    const other = crypto.generateKeyPairSync('ed25519').publicKey.export({ type: 'spki', format: 'pem' });
+    expect(verifyBundle(bundle, other).reason).toBe('signed by a different key');
+
+    const rsa = crypto.generateKeyPairSync('rsa', { modulusLength: 1024 }).privateKey.export({ type: 'pkcs8', format: 'pem' });
+    expect(() => signBundle({}, rsa)).toThrow('Ed25519');
+  });
+
+  test('writes a gzipped bundle for the range that verifies', async () => {
+    const out = path.join(dir, 'bundle.json.gz');
+    await auditExport(['--since', '2025-07-21T00:00:30Z', '--until', '2025-07-21T00:05:00Z', '--dir', dir, '--key', keyFile, '--out', out], { provider });
+
+    const bundle = readBundle(out);
+    expect(bundle.payload.snapshots.map(s => s.blockNumber)).toEqual(['101', '102']);
+    expect(bundle.payload.range).toEqual({ from: '2025-07-21T00:00:30.000Z', to: '2025-07-21T00:05:00.000Z' });
+
+    const publicKeyFile = path.join(dir, 'audit.pub');
+    fs.writeFileSync(publicKeyFile, publicKey);
+    expect((await auditExport(['verify', out, '--public-key', publicKeyFile])).valid).toBe(true);
+  });
+
+  test('requires a range and a signing key', async () => {
+    expect((await auditExport(['--dir', dir, '--key', keyFile]).catch(err => err)).message).toContain('needs a range');
+    expect((await auditExport(['--since', '24h', '--dir', dir, '--key', '']).catch(err => err)).message).toContain('signing key');
+  });
+});
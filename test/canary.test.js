@@ -0,0 +1,73 @@
+// Canary cross-validation tests
+const { loadCanaryConfig, evaluatePairs } = require('../scripts/canary-check');
+
+describe('Canary Cross-Validation', () => {
+  const config = {
+    defaultThresholdPercent: 1,
+    chains: { avalanche: {}, ethereum: {} },
+    pairs: [
+      {
+        name: 'BTC / USD',
+        thresholdPercent: 0.5,
+        feeds: { avalanche: '0xAAA', ethereum: '0xBBB' }
+      },
+      {
+        name: 'LINK / USD',
+        feeds: { avalanche: '0xCCC', ethereum: '0xDDD' }
+      }
+    ]
+  };
+
+  function readings(entries) {
+    const byChain = new Map();
+    entries.forEach(([chain, address, price]) => {
+      if (!byChain.has(chain)) byChain.set(chain, new Map());
+      byChain.get(chain).set(address.toLowerCase(), { price, updatedAt: '2025-07-21T00:00:00.000Z' });
+    });
+    return byChain;
+  }
+
+  test('shipped canary config is valid', () => {
+    const shipped = loadCanaryConfig('./canary_pairs.json');
+    expect(shipped.pairs.length).toBeGreaterThan(0);
+    shipped.pairs.forEach(pair => {
+      expect(Object.keys(pair.feeds).length).toBeGreaterThanOrEqual(2);
+    });
+  });
+
+  test('pairs within threshold are not flagged', () => {
+    const results = evaluatePairs(config, readings([
+      ['avalanche', '0xAAA', 100000],
+      ['ethereum', '0xBBB', 100100],
+      ['avalanche', '0xCCC', 15],
+      ['ethereum', '0xDDD', 15.1]
+    ]));
+
+    expect(results.map(r => r.diverged)).toEqual([false, false]);
+    expect(results[0].divergencePercent).toBeCloseTo(0.1);
+  });
+
+  test('divergence beyond the pair threshold is flagged', () => {
+    const results = evaluatePairs(config, readings([
+      ['avalanche', '0xAAA', 100000],
+      ['ethereum', '0xBBB', 101000],
+      ['avalanche', '0xCCC', 15],
+      ['ethereum', '0xDDD', 15.1]
+    ]));
+
+    expect(results[0].diverged).toBe(true);
+    expect(results[0].threshold).toBe(0.5);
+    expect(results[1].threshold).toBe(1);
+  });
+
+  test('missing answers are flagged', () => {
+    const results = evaluatePairs(config, readings([
+      ['avalanche', '0xAAA', 100000],
+      ['avalanche', '0xCCC', 15],
+      ['ethereum', '0xDDD', 0]
+    ]));
+
+    expect(results.every(r => r.diverged)).toBe(true);
+    expect(results[0].divergencePercent).toBeNull();
+  });
+});
@@ -0,0 +1,52 @@
+// Chain registry tests
+const { CHAINS, resolveChain, feedsFileFor, metadataFileFor, chainTag, snapshotChain, assertSingleChain } = require('../chains');
+
+describe('Chain Registry', () => {
+  test('defaults to Avalanche mainnet', () => {
+    expect(resolveChain('avalanche')).toBe(CHAINS.avalanche);
+    expect(chainTag(resolveChain('avalanche'))).toEqual({ chain: 'avalanche', chainId: 43114, testnet: false });
+  });
+
+  test('knows Fuji as a testnet with its own defaults', () => {
+    const fuji = resolveChain('fuji');
+    expect(chainTag(fuji)).toEqual({ chain: 'fuji', chainId: 43113, testnet: true });
+    expect(fuji.rpcUrl).toBe('https://api.avax-test.network/ext/bc/C/rpc');
+    expect(fuji.feedsFile).not.toBe(CHAINS.avalanche.feedsFile);
+    expect(fuji.snapshotDir).not.toBe(CHAINS.avalanche.snapshotDir);
+  });
+
+  test('a missing feed list names the command that generates it', () => {
+    expect(feedsFileFor(CHAINS.avalanche, CHAINS.avalanche.feedsFile)).toBe(CHAINS.avalanche.feedsFile);
+    expect(() => feedsFileFor(CHAINS.fuji, './missing_feeds.csv'))
+      .toThrow('No feed list for fuji at ./missing_feeds.csv. Generate one from the proxy addresses in Chainlink\'s docs (node scripts/generate-feeds.js --chain fuji');
+  });
+
+  test('each chain keeps its own metadata file', () => {
+    const metadata = process.env.FEED_METADATA;
+    delete process.env.FEED_METADATA;
+    try {
+      expect(metadataFileFor(CHAINS.avalanche)).toBe('./feed_metadata.json');
+      expect(metadataFileFor(CHAINS.fuji)).toBe('./fuji_feed_metadata.json');
+      expect(metadataFileFor(CHAINS.fuji, './other.json')).toBe('./other.json');
+
+      process.env.FEED_METADATA = './override.json';
+      expect(metadataFileFor(CHAINS.fuji)).toBe('./override.json');
+    } finally {
+      if (metadata === undefined) delete process.env.FEED_METADATA; else process.env.FEED_METADATA = metadata;
+    }
+  });
+
+  test('rejects unknown chains', () => {
+    expect(() => resolveChain('sepolia')).toThrow('Unknown chain "sepolia", expected one of avalanche, fuji');
+  });
+
+  test('untagged snapshots count as mainnet', () => {
+    expect(snapshotChain({ prices: [] })).toBe('avalanche');
+    expect(snapshotChain({ chain: 'fuji', prices: [] })).toBe('fuji');
+  });
+
+  test('refuses to combine snapshots from several chains', () => {
+    expect(assertSingleChain([{ prices: [] }, { chain: 'avalanche', prices: [] }])).toHaveLength(2);
+    expect(() => assertSingleChain([{ prices: [] }, { chain: 'fuji', prices: [] }])).toThrow('several chains (avalanche, fuji)');
+  });
+});
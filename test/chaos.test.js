@@ -0,0 +1,70 @@
+// Fault injection tests
+const { parseFaultSchedule, createFaultInjector, chaosOptions } = require('../chaos');
+
+describe('Fault Injection', () => {
+  const returnData = ['0x' + '11'.repeat(160), '0x' + '22'.repeat(160), '0x' + '33'.repeat(160), '0x' + '44'.repeat(32)];
+
+  test('parses step, range and periodic schedules', () => {
+    expect(parseFaultSchedule('timeout@2, partial@3-4,corrupt/5')).toEqual([
+      { type: 'timeout', from: 2, to: 2 },
+      { type: 'partial', from: 3, to: 4 },
+      { type: 'corrupt', every: 5 }
+    ]);
+    expect(parseFaultSchedule(undefined)).toEqual([]);
+  });
+
+  test('rejects unknown faults and malformed steps', () => {
+    expect(() => parseFaultSchedule('mongo@1')).toThrow('Invalid fault "mongo@1"');
+    expect(() => parseFaultSchedule('timeout')).toThrow('Invalid fault');
+  });
+
+  test('is a no-op when no schedule is configured', async () => {
+    const faults = chaosOptions({}).faults;
+    faults.nextStep();
+
+    expect(faults.enabled).toBe(false);
+    expect(await faults.call(async () => 'ok')).toBe('ok');
+    expect(faults.mangleReturnData(returnData)).toEqual(returnData);
+    expect(faults.write(() => 'written')).toBe('written');
+  });
+
+  test('injects RPC timeouts only on scheduled steps', async () => {
+    const faults = createFaultInjector('timeout@2');
+
+    faults.nextStep();
+    expect(await faults.call(async () => 'ok')).toBe('ok');
+
+    faults.nextStep();
+    const error = await faults.call(async () => 'ok').catch(err => err);
+    expect(error.code).toBe('TIMEOUT');
+  });
+
+  test('partial failures empty some entries but never protected ones', () => {
+    const faults = createFaultInjector('partial/1', { rate: 1 });
+    faults.nextStep();
+
+    const mangled = faults.mangleReturnData(returnData, [3]);
+    expect(mangled.slice(0, 3)).toEqual(['0x', '0x', '0x']);
+    expect(mangled[3]).toBe(returnData[3]);
+  });
+
+  test('corruption truncates entries and repeats for the same seed', () => {
+    const run = () => {
+      const faults = createFaultInjector('corrupt@1', { seed: 7 });
+      faults.nextStep();
+      return faults.mangleReturnData(returnData, [3]);
+    };
+
+    const mangled = run();
+    expect(mangled.filter((data, i) => data !== returnData[i]).length).toBeGreaterThan(0);
+    mangled.forEach((data, i) => expect(data.length).toBeLessThanOrEqual(returnData[i].length));
+    expect(run()).toEqual(mangled);
+  });
+
+  test('storage outages fail the write', () => {
+    const faults = createFaultInjector('storage@1');
+    faults.nextStep();
+
+    expect(() => faults.write(() => 'written')).toThrow('Injected storage outage (step 1)');
+  });
+});
@@ -0,0 +1,57 @@
+// Clock and deterministic mode tests
+const { systemClock, fixedClock, deterministicOptions } = require('../clock');
+const { buildSnapshot } = require('../multicall_price_fetcher');
+
+describe('Clock Sources', () => {
+  test('fixed clock accepts ISO strings and unix milliseconds', () => {
+    expect(fixedClock('2025-07-21T00:00:00.000Z').now()).toBe(1753056000000);
+    expect(fixedClock(1753056000000).now()).toBe(1753056000000);
+    expect(() => fixedClock('not a date')).toThrow('Invalid fixed clock time');
+  });
+
+  test('system clock is used outside deterministic mode', () => {
+    const options = deterministicOptions({});
+    expect(options.clock).toBe(systemClock);
+    expect(options.blockTag).toBeUndefined();
+  });
+
+  test('deterministic mode pins time and block', () => {
+    const options = deterministicOptions({
+      DETERMINISTIC: '1',
+      FIXED_TIME: '2025-07-21T00:00:00Z',
+      FIXED_BLOCK: '65814031'
+    });
+    expect(options.clock.now()).toBe(1753056000000);
+    expect(options.blockTag).toBe(65814031);
+  });
+
+  test('deterministic mode requires both a time and a block', () => {
+    expect(() => deterministicOptions({ DETERMINISTIC: '1', FIXED_TIME: '1753056000000' }))
+      .toThrow('requires FIXED_TIME and FIXED_BLOCK');
+  });
+
+  test('FIXED_BLOCK must be a non-negative integer', () => {
+    ['1.5', '-1', 'latest', '1e3', '0x10'].forEach(block => {
+      expect(() => deterministicOptions({ DETERMINISTIC: '1', FIXED_TIME: '1753056000000', FIXED_BLOCK: block }))
+        .toThrow(`FIXED_BLOCK must be a non-negative integer block number, got "${block}"`);
+      expect(() => deterministicOptions({ FIXED_BLOCK: block })).toThrow('FIXED_BLOCK must be');
+    });
+    expect(deterministicOptions({ FIXED_BLOCK: '0' }).blockTag).toBe(0);
+  });
+
+  test('snapshots are stable under a fixed clock', () => {
+    const clock = fixedClock('2025-07-21T00:00:00Z');
+    const results = [{ name: 'BTC / USD', price: 117402.79073738 }];
+
+    const golden = JSON.stringify({
+      blockNumber: '65814031',
+      blockTimestamp: '2025-07-21T00:00:12.000Z',
+      timestamp: '2025-07-21T00:00:00.000Z',
+      totalFeeds: 1,
+      prices: results
+    });
+
+    expect(JSON.stringify(buildSnapshot(results, 65814031n, 1753056012n, clock))).toBe(golden);
+    expect(JSON.stringify(buildSnapshot(results, 65814031n, 1753056012n, clock))).toBe(golden);
+  });
+});
@@ -0,0 +1,175 @@
+// Custom feed call tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { ethers } = require('ethers');
+const { loadCustomFeeds, decodeCustomResult } = require('../multicall_price_fetcher');
+
+describe('Custom Feed Calls', () => {
+  function writeConfig(feeds) {
+    const file = path.join(os.tmpdir(), `custom_feeds_${process.pid}_${Date.now()}.json`);
+    fs.writeFileSync(file, JSON.stringify({ feeds }));
+    return file;
+  }
+
+  test('shipped custom feed config is valid', () => {
+    const feeds = loadCustomFeeds('./custom_feeds.json');
+    expect(feeds.length).toBeGreaterThan(0);
+    feeds.forEach(feed => {
+      expect(ethers.isAddress(feed.proxyAddress)).toBe(true);
+      expect(feed.callData.startsWith('0x')).toBe(true);
+    });
+  });
+
+  test('missing config yields no custom feeds', () => {
+    expect(loadCustomFeeds('./does_not_exist.json')).toEqual([]);
+  });
+
+  test('encodes arguments and decodes the selected output', () => {
+    const file = writeConfig([{
+      name: 'Vault Share Price',
+      target: '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE',
+      signature: 'function pricePerShare() view returns (uint256 shares, uint256 price)',
+      decode: { index: 1, decimals: 6 }
+    }]);
+    const [feed] = loadCustomFeeds(file);
+    fs.unlinkSync(file);
+
+    expect(feed.method).toBe('pricePerShare()');
+    expect(feed.callData).toBe(feed.iface.getFunction('pricePerShare').selector);
+
+    const data = feed.iface.encodeFunctionResult('pricePerShare', [7n, 1234567n]);
+    const result = decodeCustomResult(feed, data);
+
+    expect(result.source).toBe('custom');
+    expect(result.price).toBeCloseTo(1.234567);
+    expect(result.raw.value).toBe('1234567');
+  });
+
+  test('erc4626 entries read convertToAssets for one share', () => {
+    const file = writeConfig([{
+      name: 'Vault / USDC Exchange Rate',
+      type: 'erc4626',
+      target: '0xA25EaF2906FA1a3a13EdAc9B9657108Af7B703e3',
+      decode: { decimals: 6 }
+    }]);
+    const [feed] = loadCustomFeeds(file);
+    fs.unlinkSync(file);
+
+    expect(feed.source).toBe('erc4626');
+    expect(feed.method).toBe('convertToAssets(uint256)');
+    expect(feed.callData).toBe(feed.iface.encodeFunctionData('convertToAssets', [10n ** 18n]));
+
+    const data = feed.iface.encodeFunctionResult('convertToAssets', [1050000n]);
+    expect(decodeCustomResult(feed, data).price).toBeCloseTo(1.05);
+  });
+
+  test('erc2362 entries hash a pair id and take decimals from it', () => {
+    const file = writeConfig([{
+      name: 'ETH / USD (Generic Oracle)',
+      type: 'erc2362',
+      target: '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE',
+      id: 'eth-usd-18'
+    }]);
+    const [feed] = loadCustomFeeds(file);
+    fs.unlinkSync(file);
+
+    expect(feed.source).toBe('erc2362');
+    expect(feed.kind).toBe('price');
+    expect(feed.method).toBe('valueFor(bytes32)');
+    expect(feed.decimals).toBe(18);
+    expect(feed.oracleId).toBe(ethers.id('eth-usd-18'));
+    expect(feed.callData).toBe(feed.iface.encodeFunctionData('valueFor', [ethers.id('eth-usd-18')]));
+
+    const data = feed.iface.encodeFunctionResult('valueFor', [3000n * 10n ** 18n, 1753055940n, 200n]);
+    const result = decodeCustomResult(feed, data, 1753056000n);
+    expect(result.price).toBe(3000);
+    expect(result.oracleId).toBe(ethers.id('eth-usd-18'));
+    expect(result.updatedAt).toBe('2025-07-20T23:59:00.000Z');
+    expect(result.ageAtBlock).toBe(60);
+  });
+
+  test('erc2362 results without status 200 are errors', () => {
+    const file = writeConfig([{
+      name: 'Missing Pair',
+      type: 'erc2362',
+      target: '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE',
+      id: '0x' + 'ab'.repeat(32),
+      decode: { decimals: 8 }
+    }]);
+    const [feed] = loadCustomFeeds(file);
+    fs.unlinkSync(file);
+
+    const data = feed.iface.encodeFunctionResult('valueFor', [0n, 0n, 404n]);
+    expect(() => decodeCustomResult(feed, data)).toThrow('Oracle returned status 404');
+  });
+
+  test('values are checked against the feed kind like Chainlink answers', () => {
+    const file = writeConfig([
+      { name: 'ETH / USD (Generic Oracle)', type: 'erc2362', target: '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE', id: 'eth-usd-18' },
+      { name: 'Vault Price', target: '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE', signature: 'function price() view returns (uint256)' },
+      { name: 'Vault / USDC Exchange Rate', type: 'erc4626', target: '0xA25EaF2906FA1a3a13EdAc9B9657108Af7B703e3' }
+    ]);
+    const [oracle, custom, vault] = loadCustomFeeds(file);
+    fs.unlinkSync(file);
+
+    expect(() => decodeCustomResult(oracle, oracle.iface.encodeFunctionResult('valueFor', [-5n, 1753055940n, 200n])))
+      .toThrow('Negative answer -5 is not valid for a price feed');
+    expect(() => decodeCustomResult(custom, custom.iface.encodeFunctionResult('price', [0n])))
+      .toThrow('Zero answer is not valid for a price feed');
+    // Rates may legitimately be zero
+    expect(decodeCustomResult(vault, vault.iface.encodeFunctionResult('convertToAssets', [0n])).price).toBe(0);
+  });
+
+  test('rejects an unknown kind', () => {
+    const file = writeConfig([{
+      name: 'Vault Price',
+      kind: 'ratio',
+      target: '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE',
+      signature: 'function price() view returns (uint256)'
+    }]);
+    expect(() => loadCustomFeeds(file)).toThrow('ratio');
+    fs.unlinkSync(file);
+  });
+
+  test('erc2362 entries need a usable id', () => {
+    const target = '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE';
+    [
+      [{ name: 'No Id', type: 'erc2362', target }, 'needs an id'],
+      [{ name: 'Bad Id', type: 'erc2362', target, id: 'ETH/USD' }, 'neither bytes32'],
+      [{ name: 'Raw Id', type: 'erc2362', target, id: '0x' + 'ab'.repeat(32) }, 'needs decode.decimals']
+    ].forEach(([entry, message]) => {
+      const file = writeConfig([entry]);
+      expect(() => loadCustomFeeds(file)).toThrow(message);
+      fs.unlinkSync(file);
+    });
+  });
+
+  test('lst entries require a supported rate method', () => {
+    const file = writeConfig([{
+      name: 'Unknown LST',
+      type: 'lst',
+      target: '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE',
+      method: 'sharesToUnderlying'
+    }]);
+    expect(() => loadCustomFeeds(file)).toThrow('unsupported lst method');
+    fs.unlinkSync(file);
+  });
+
+  test('rejects entries without a valid target', () => {
+    const file = writeConfig([{ name: 'Broken', target: '0x123', signature: 'function x() view returns (uint256)' }]);
+    expect(() => loadCustomFeeds(file)).toThrow('Invalid custom feed entry');
+    fs.unlinkSync(file);
+  });
+
+  test('rejects an output index outside the return tuple', () => {
+    const file = writeConfig([{
+      name: 'Out of Range',
+      target: '0x2b2C81e08f1Af8835a78Bb2A90AE924ACE0eA4bE',
+      signature: 'function x() view returns (uint256)',
+      decode: { index: 2 }
+    }]);
+    expect(() => loadCustomFeeds(file)).toThrow('decodes output 2');
+    fs.unlinkSync(file);
+  });
+});
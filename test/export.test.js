@@ -0,0 +1,109 @@
+// Snapshot export tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { exportSnapshots, loadSnapshots, buildHistory, summarize, sheetName, parseTime } = require('../scripts/export');
+
+describe('Snapshot Export', () => {
+  let dir;
+
+  function snapshot(ms, prices) {
+    return {
+      blockNumber: String(ms / 1000),
+      blockTimestamp: new Date(ms).toISOString(),
+      timestamp: new Date(ms).toISOString(),
+      totalFeeds: prices.length,
+      prices
+    };
+  }
+
+  beforeAll(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'export-'));
+    const at = Date.parse('2025-07-21T00:00:00Z');
+    [
+      [at, [{ name: 'BTC / USD', proxy: '0xAAA', price: 100, roundId: '1' }, { name: 'AVAX / USD', proxy: '0xBBB', price: 20 }]],
+      [at + 3600000, [{ name: 'BTC / USD', proxy: '0xAAA', price: 110, roundId: '2' }, { name: 'AVAX / USD', proxy: '0xBBB', error: 'reverted' }]],
+      [at + 7200000, [{ name: 'BTC / USD', proxy: '0xAAA', price: 90, roundId: '3' }]]
+    ].forEach(([ms, prices]) => {
+      fs.writeFileSync(path.join(dir, `avalanche_prices_${ms}.json`), JSON.stringify(snapshot(ms, prices)));
+    });
+    fs.writeFileSync(path.join(dir, 'unrelated.json'), '{}');
+  });
+
+  afterAll(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('loads snapshots in time order and filters by range', () => {
+    expect(loadSnapshots(dir, null, null)).toHaveLength(3);
+
+    const ranged = loadSnapshots(dir, Date.parse('2025-07-21T00:30:00Z'), null);
+    expect(ranged).toHaveLength(2);
+    expect(ranged[0].blockTimestamp).toBe('2025-07-21T01:00:00.000Z');
+  });
+
+  test('builds per-feed history and skips errored readings', () => {
+    const history = buildHistory(loadSnapshots(dir, null, null));
+
+    expect(history.map(feed => feed.name)).toEqual(['AVAX / USD', 'BTC / USD']);
+    expect(history[0].rows).toHaveLength(1);
+    expect(history[1].rows.map(row => row.price)).toEqual([100, 110, 90]);
+  });
+
+  test('summarizes range statistics per feed', () => {
+    const btc = buildHistory(loadSnapshots(dir, null, null)).find(feed => feed.name === 'BTC / USD');
+    const summary = summarize(btc);
+
+    expect(summary.observations).toBe(3);
+    expect(summary.min).toBe(90);
+    expect(summary.max).toBe(110);
+    expect(summary.latest).toBe(90);
+    expect(summary.changePercent).toBeCloseTo(-10);
+  });
+
+  test('sheet names are valid and unique', () => {
+    const used = new Set(['summary']);
+
+    expect(sheetName('BTC / USD', used)).toBe('BTC - USD');
+    expect(sheetName('BTC / USD', used)).toBe('BTC - USD (2)');
+    expect(sheetName('Summary', used)).toBe('Summary (2)');
+    expect(sheetName('A Very Long Feed Name That Exceeds Excel Limits', used)).toHaveLength(31);
+  });
+
+  test('range bounds require an explicit offset', () => {
+    expect(parseTime('2025-07-21', 'from')).toBe(Date.parse('2025-07-21T00:00:00Z'));
+    expect(parseTime('2025-07-21T02:00:00+02:00', 'from')).toBe(Date.parse('2025-07-21T00:00:00Z'));
+    expect(() => parseTime('2025-07-21T00:00:00', 'to')).toThrow('explicit UTC offset');
+  });
+
+  test('CHAIN picks the snapshot directory and names the output after the chain', async () => {
+    const cwd = process.cwd();
+    const env = { CHAIN: process.env.CHAIN, SNAPSHOT_DIR: process.env.SNAPSHOT_DIR };
+    const root = fs.mkdtempSync(path.join(os.tmpdir(), 'export-chain-'));
+    const fuji = path.join(root, 'snapshots', 'fuji');
+    const ms = Date.parse('2025-07-21T00:00:00Z');
+    fs.mkdirSync(fuji, { recursive: true });
+    fs.writeFileSync(path.join(root, `avalanche_prices_${ms}.json`), JSON.stringify(snapshot(ms, [{ name: 'BTC / USD', proxy: '0xAAA', price: 100 }])));
+    fs.writeFileSync(path.join(fuji, `avalanche_prices_${ms}.json`), JSON.stringify({
+      ...snapshot(ms, [{ name: 'AVAX / USD', proxy: '0xBBB', price: 20 }]), chain: 'fuji', chainId: 43113, testnet: true
+    }));
+
+    process.chdir(root);
+    process.env.CHAIN = 'fuji';
+    delete process.env.SNAPSHOT_DIR;
+    try {
+      const outputFile = await exportSnapshots(['--format', 'csv']);
+      expect(path.basename(outputFile)).toMatch(/^fuji_prices_export_\d+\.csv$/);
+
+      const csv = fs.readFileSync(outputFile, 'utf8');
+      expect(csv).toContain('AVAX / USD');
+      expect(csv).not.toContain('BTC / USD');
+    } finally {
+      process.chdir(cwd);
+      for (const [key, value] of Object.entries(env)) {
+        if (value === undefined) delete process.env[key]; else process.env[key] = value;
+      }
+      fs.rmSync(root, { recursive: true, force: true });
+    }
+  });
+});
@@ -0,0 +1,71 @@
+// Feed kind tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { classifyFeed, validateAnswer, formatAnswer, displayValue } = require('../feed_kinds');
+const { loadFeedData } = require('../multicall_price_fetcher');
+
+describe('Feed Kinds', () => {
+  test('classifies feeds by what they report', () => {
+    expect(classifyFeed('BTC / USD')).toBe('price');
+    expect(classifyFeed('USDC.e Proof of Reserves')).toBe('por');
+    expect(classifyFeed('Ion Digital Total Reserve')).toBe('por');
+    expect(classifyFeed('wstETH-stETH Exchange Rate')).toBe('rate');
+    expect(classifyFeed('SUSDE-USDE-Exchange-Rate')).toBe('rate');
+    expect(classifyFeed('PCE Price Index — Percent Change (Annual Rate)')).toBe('rate');
+    expect(classifyFeed('PCE Price Index — Level')).toBe('index');
+    expect(classifyFeed('AAVE Network Emergency Count (Avalanche)')).toBe('index');
+  });
+
+  test('an explicit kind wins over the name', () => {
+    expect(classifyFeed('S&P 500 Index Rate', 'price')).toBe('price');
+    expect(classifyFeed('BTC / USD', '')).toBe('price');
+    expect(() => classifyFeed('BTC / USD', 'yield')).toThrow('Unknown kind "yield" for BTC / USD');
+  });
+
+  test('the feed list\'s kind column overrides classification', async () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'feed-kinds-'));
+    const file = path.join(dir, 'feeds.csv');
+    const header = 'name,contract_address,proxy_address,deviation_threshold,heartbeat,decimals,asset_class,product_name,ens,path,base_asset,quote_asset,kind';
+    fs.writeFileSync(file, [header, 'Reserve Rate Index,0x1,0x2,0.5,86400,8,,,,,,,index', 'BTC / USD,0x3,0x4,0.1,3600,8,,,,,,,'].join('\n'));
+
+    expect((await loadFeedData(file)).map(feed => feed.kind)).toEqual(['index', 'price']);
+    fs.writeFileSync(file, [header, 'BTC / USD,0x3,0x4,0.1,3600,8,,,,,,,yield'].join('\n'));
+    await expect(loadFeedData(file)).rejects.toThrow(`${file}: Unknown kind "yield"`);
+    fs.rmSync(dir, { recursive: true });
+  });
+
+  test('every shipped feed gets a kind', async () => {
+    const feeds = await loadFeedData();
+    const kinds = new Set(feeds.map(feed => feed.kind));
+
+    expect([...kinds].every(kind => ['price', 'rate', 'index', 'por'].includes(kind))).toBe(true);
+    expect(kinds.has('price')).toBe(true);
+    expect(kinds.has('por')).toBe(true);
+  });
+
+  test('only rates accept negative answers', () => {
+    expect(validateAnswer('rate', -125n)).toBeNull();
+    expect(validateAnswer('price', -125n)).toMatch('Negative answer');
+    expect(validateAnswer('index', -1n)).toMatch('Negative answer');
+    expect(validateAnswer('por', -1n)).toMatch('Negative answer');
+  });
+
+  test('zero is invalid only for prices', () => {
+    expect(validateAnswer('price', 0n)).toMatch('Zero answer');
+    expect(validateAnswer('index', 0n)).toBeNull();
+    expect(validateAnswer('por', 0n)).toBeNull();
+  });
+
+  test('formats answers without losing precision', () => {
+    expect(formatAnswer(11740279073738n, 8)).toBe('117402.79073738');
+    expect(formatAnswer(-250n, 2)).toBe('-2.5');
+    expect(formatAnswer(10n ** 30n + 1n, 18)).toBe('1000000000000.000000000000000001');
+  });
+
+  test('display depends on kind', () => {
+    expect(displayValue('price', '117402.79')).toBe('$117402.79');
+    expect(displayValue('rate', '-2.5')).toBe('-2.5');
+    expect(displayValue('por', '1200.5')).toBe('1200.5 reserves');
+  });
+});
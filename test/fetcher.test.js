@@ -0,0 +1,392 @@
+// Fetcher library API tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { ethers } = require('ethers');
+const {
+  createFetcher, fileSink, chunkCalls, validateStateOverride, callOptions, planCatchUp,
+  MULTICALL3_ADDRESS, MULTICALL3_INTERFACE, CHAINLINK_INTERFACE
+} = require('../multicall_price_fetcher');
+const { fixedClock } = require('../clock');
+
+const BTC = '0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743';
+const ETH = '0x976B3D034E162d8bD72D6b9C989d545b839003b0';
+const AVAX = '0x0A77230d17318075983913bC2145DB16C7366156';
+
+const blockHash = number => `0x${number.toString(16).padStart(64, '0')}`;
+
+// Serves aggregate3 batches the way Multicall3 would: latestRoundData per proxy from `answers`
+// (updated a minute before the block unless `updatedAt` says otherwise), an Error(string) revert for proxies in `reverts`, and the block for getBlockNumber/getCurrentBlockTimestamp.
+// Every batch is recorded with the block tag it was read at; getBlock serves a hash derived from the number.
+function fixtureProvider({ block = 1000, timestamp = 1753056000, answers = {}, updatedAt = {}, reverts = [] }) {
+  const batches = [];
+  const selector = name => (MULTICALL3_INTERFACE.getFunction(name) || CHAINLINK_INTERFACE.getFunction(name)).selector;
+  const revert = '0x08c379a0' + ethers.AbiCoder.defaultAbiCoder().encode(['string'], ['No data present']).slice(2);
+
+  return {
+    batches,
+    getBlock: async number => ({ number, hash: blockHash(number), timestamp }),
+    call: async tx => {
+      const [calls] = MULTICALL3_INTERFACE.decodeFunctionData('aggregate3', tx.data);
+      const current = typeof block === 'function' ? block(batches.length) : block;
+      batches.push({ blockTag: tx.blockTag, targets: calls.map(call => call.target) });
+
+      const results = calls.map(({ target, callData }) => {
+        if (callData === selector('getBlockNumber')) {
+          return [true, MULTICALL3_INTERFACE.encodeFunctionResult('getBlockNumber', [tx.blockTag ?? current])];
+        }
+        if (callData === selector('getCurrentBlockTimestamp')) {
+          return [true, MULTICALL3_INTERFACE.encodeFunctionResult('getCurrentBlockTimestamp', [timestamp])];
+        }
+        if (reverts.includes(target)) {
+          return [false, revert];
+        }
+        const answer = answers[target];
+        const updated = updatedAt[target] ?? timestamp - 60;
+        return [true, CHAINLINK_INTERFACE.encodeFunctionResult('latestRoundData', [7, answer, updated, updated, 7])];
+      });
+      return MULTICALL3_INTERFACE.encodeFunctionResult('aggregate3', [results]);
+    }
+  };
+}
+
+describe('Fetcher API', () => {
+  // A runner whose calls always fail, so no test touches the network
+  const offlineProvider = {
+    call: async () => {
+      throw new Error('offline');
+    }
+  };
+
+  let feedsDir;
+  const feedsFile = process.env.FEEDS_FILE;
+
+  beforeAll(() => {
+    feedsDir = fs.mkdtempSync(path.join(os.tmpdir(), 'feeds-'));
+    process.env.FEEDS_FILE = path.join(feedsDir, 'feeds.csv');
+    fs.writeFileSync(process.env.FEEDS_FILE, [
+      'name,contract_address,proxy_address,deviation_threshold,heartbeat,decimals,asset_class,product_name,ens,path,base_asset,quote_asset',
+      `BTC / USD,0x0000000000000000000000000000000000000001,${BTC},0.1,86400,8,Crypto,,,,BTC,USD`,
+      `ETH / USD,0x0000000000000000000000000000000000000002,${ETH},0.1,86400,8,Crypto,,,,ETH,USD`,
+      `AVAX / USD,0x0000000000000000000000000000000000000003,${AVAX},0.1,86400,8,Crypto,,,,AVAX,USD`
+    ].join('\n'));
+  });
+
+  afterAll(() => {
+    if (feedsFile === undefined) delete process.env.FEEDS_FILE;
+    else process.env.FEEDS_FILE = feedsFile;
+    fs.rmSync(feedsDir, { recursive: true, force: true });
+  });
+
+  test('chunkCalls splits a batch and keeps order', () => {
+    const calls = [1, 2, 3, 4, 5];
+    expect(chunkCalls(calls, 2)).toEqual([[1, 2], [3, 4], [5]]);
+    expect(chunkCalls(calls, Infinity)).toEqual([calls]);
+  });
+
+  test('rejects invalid chunk sizes and sinks', () => {
+    expect(() => createFetcher({ provider: offlineProvider, chunkSize: 0 })).toThrow('chunkSize must be a positive integer');
+    expect(() => createFetcher({ provider: offlineProvider, chunkSize: 2.5 })).toThrow('chunkSize must be a positive integer');
+    expect(() => createFetcher({ provider: offlineProvider, sinks: [{}] })).toThrow('write(snapshot)');
+    expect(() => createFetcher({ provider: offlineProvider, shard: { index: 2, count: 2 } })).toThrow('Shard index');
+    expect(() => createFetcher({ provider: offlineProvider, chain: 'sepolia' })).toThrow('Unknown chain "sepolia"');
+  });
+
+  test('exposes fetch and run', () => {
+    const fetcher = createFetcher({ provider: offlineProvider });
+    expect(typeof fetcher.fetch).toBe('function');
+    expect(typeof fetcher.run).toBe('function');
+  });
+
+  test('fetch refuses to start once aborted', async () => {
+    const controller = new AbortController();
+    controller.abort();
+
+    const error = await createFetcher({ provider: offlineProvider }).fetch({ signal: controller.signal }).catch(err => err);
+    expect(error.name).toBe('AbortError');
+  });
+
+  test('run keeps going after failed cycles and stops on abort', async () => {
+    const controller = new AbortController();
+    const fetcher = createFetcher({ provider: offlineProvider });
+
+    setTimeout(() => controller.abort(), 100);
+    const started = Date.now();
+    await fetcher.run({ signal: controller.signal, intervalMs: 60000 });
+
+    expect(Date.now() - started).toBeLessThan(5000);
+  });
+
+  test('a reverting feed fails only its own entry', async () => {
+    const provider = fixtureProvider({ answers: { [BTC]: 11850012345678n, [AVAX]: 2312345678n }, reverts: [ETH] });
+    const snapshot = await createFetcher({ provider, customFeedsFile: './missing.json' }).fetch();
+
+    expect(snapshot.blockNumber).toBe('1000');
+    expect(snapshot.blockHash).toBe(blockHash(1000));
+    expect(snapshot.prices.map(entry => entry.name)).toEqual(['BTC / USD', 'ETH / USD', 'AVAX / USD']);
+    expect(snapshot.prices[0]).toMatchObject({ price: 118500.12345678, roundId: '7', ageAtBlock: 60 });
+    expect(snapshot.prices[1]).toEqual({ name: 'ETH / USD', proxy: ETH, error: 'Call reverted: No data present' });
+    expect(snapshot.prices[2].price).toBe(23.12345678);
+  });
+
+  test('answers are aged against the block timestamp, never below zero', async () => {
+    const timestamp = 1753056000;
+    const provider = fixtureProvider({
+      timestamp,
+      answers: { [BTC]: 11850012345678n, [ETH]: 370012345678n, [AVAX]: 2312345678n },
+      updatedAt: { [BTC]: timestamp - 7200, [ETH]: timestamp + 30 }
+    });
+    // Local time is days later; only the block timestamp counts
+    const snapshot = await createFetcher({ provider, clock: fixedClock('2025-07-25T00:00:00Z'), customFeedsFile: './missing.json' }).fetch();
+
+    expect(snapshot.blockTimestamp).toBe(new Date(timestamp * 1000).toISOString());
+    expect(snapshot.prices.map(entry => entry.ageAtBlock)).toEqual([7200, 0, 60]);
+    expect(snapshot.prices[1].updatedAt).toBe(new Date((timestamp + 30) * 1000).toISOString());
+  });
+
+  test('FIXED_BLOCK reads are aged against that block', async () => {
+    const fixedBlock = process.env.FIXED_BLOCK;
+    process.env.FIXED_BLOCK = '900';
+    try {
+      const timestamp = 1752000000;
+      const provider = fixtureProvider({
+        block: 1000,
+        timestamp,
+        answers: { [BTC]: 11850012345678n, [ETH]: 370012345678n, [AVAX]: 2312345678n },
+        updatedAt: { [BTC]: timestamp - 86400 }
+      });
+      const snapshot = await createFetcher({ provider, customFeedsFile: './missing.json' }).fetch();
+
+      expect(provider.batches.map(batch => batch.blockTag)).toEqual([900]);
+      expect(snapshot.blockNumber).toBe('900');
+      expect(snapshot.blockTimestamp).toBe(new Date(timestamp * 1000).toISOString());
+      expect(snapshot.prices.map(entry => entry.ageAtBlock)).toEqual([86400, 60, 60]);
+    } finally {
+      if (fixedBlock === undefined) delete process.env.FIXED_BLOCK; else process.env.FIXED_BLOCK = fixedBlock;
+    }
+  });
+
+  test('run pins every chunk of a cycle to one block and hands each cycle to the sinks', async () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'fetcher-'));
+    // Each batch would see a newer block unless it is pinned
+    const provider = fixtureProvider({ block: batch => 1000 + batch, answers: { [BTC]: 11850012345678n, [ETH]: 370012345678n, [AVAX]: 2312345678n } });
+    const controller = new AbortController();
+    const written = [];
+    let time = Date.parse('2025-07-21T00:00:00Z');
+
+    const fetcher = createFetcher({
+      provider,
+      chunkSize: 2,
+      clock: { now: () => (time += 1000) },
+      sinks: [fileSink({ dir }), {
+        write: snapshot => {
+          written.push(snapshot);
+          if (written.length === 2) controller.abort();
+        }
+      }],
+      customFeedsFile: './missing.json'
+    });
+    await fetcher.run({ signal: controller.signal, intervalMs: 1 });
+
+    // 3 feeds plus the block timestamp, 2 per chunk: two batches a cycle, the second pinned to the first's block
+    expect(provider.batches.map(batch => batch.blockTag === undefined ? 'latest' : Number(batch.blockTag))).toEqual(['latest', 1000, 'latest', 1002]);
+    expect(provider.batches[0].targets.slice(1)).toEqual([BTC, ETH]);
+    expect(provider.batches[1].targets.slice(1)).toEqual([AVAX, MULTICALL3_ADDRESS]);
+
+    expect(written.map(snapshot => snapshot.blockNumber)).toEqual(['1000', '1002']);
+    written.forEach(snapshot => {
+      expect(snapshot.prices.map(entry => entry.price)).toEqual([118500.12345678, 3700.12345678, 23.12345678]);
+      expect(snapshot.blockTimestamp).toBe('2025-07-21T00:00:00.000Z');
+    });
+    expect(fs.readdirSync(dir).sort()).toEqual(written.map(snapshot => `avalanche_prices_${Date.parse(snapshot.timestamp)}.json`).sort());
+    fs.rmSync(dir, { recursive: true });
+  });
+
+  test('leaves the block hash out when the block changed between reads', async () => {
+    const provider = fixtureProvider({ answers: { [BTC]: 1n, [ETH]: 1n, [AVAX]: 1n } });
+    provider.getBlock = async number => ({ number, hash: blockHash(number), timestamp: 1 });
+    const snapshot = await createFetcher({ provider, customFeedsFile: './missing.json' }).fetch();
+
+    expect(snapshot.blockNumber).toBe('1000');
+    expect(snapshot.blockHash).toBeUndefined();
+  });
+
+  test('adds verifying sinks\' counts to the metrics file after each fetch', async () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'verify-metrics-'));
+    const metrics = path.join(dir, 'verification.prom');
+    const provider = fixtureProvider({ answers: { [BTC]: 1n, [ETH]: 1n, [AVAX]: 1n } });
+    const fetcher = createFetcher({
+      provider,
+      sinks: [fileSink({ dir, verify: true })],
+      verificationMetricsFile: metrics,
+      customFeedsFile: './missing.json'
+    });
+
+    await fetcher.fetch();
+    await fetcher.fetch();
+    expect(fs.readFileSync(metrics, 'utf8')).toContain('chainlink_snapshot_writes_verified_total{sink="file",chain="avalanche"} 2');
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('validates state override sets', () => {
+    const proxy = '0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743';
+    const slot = '0x' + '0'.repeat(63) + '2';
+    const value = '0x' + '0'.repeat(24) + '9450a29ef091b625e976ce68933a5e0f5a0bd2f0';
+
+    expect(validateStateOverride({ [proxy]: { stateDiff: { [slot]: value } } })).toBeDefined();
+    expect(() => validateStateOverride([])).toThrow('object keyed by address');
+    expect(() => validateStateOverride({ nope: {} })).toThrow('is not an address');
+    expect(() => validateStateOverride({ [proxy]: { storage: {} } })).toThrow('unsupported field(s) storage');
+    expect(() => validateStateOverride({ [proxy]: { state: {}, stateDiff: {} } })).toThrow('both state and stateDiff');
+  });
+
+  test('reads gas limit and state override from the environment', () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'override-'));
+    const file = path.join(dir, 'override.json');
+    fs.writeFileSync(file, JSON.stringify({ '0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743': { balance: '0x1' } }));
+
+    expect(callOptions({})).toEqual({});
+    const options = callOptions({ GAS_LIMIT: '50000000', STATE_OVERRIDE: file });
+    expect(options.gasLimit).toBe(50000000n);
+    expect(Object.keys(options.stateOverride)).toEqual(['0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743']);
+    fs.rmSync(dir, { recursive: true });
+
+    ['abc', '0', '-1', '1.5', '0x10', ' 100'].forEach(value => {
+      expect(() => callOptions({ GAS_LIMIT: value })).toThrow(`GAS_LIMIT must be a positive integer, got "${value}"`);
+    });
+  });
+
+  test('state overrides go through a raw eth_call with the gas limit', async () => {
+    const stateOverride = { '0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743': { code: '0x00' } };
+    let captured;
+    const provider = {
+      send: async (method, params) => {
+        captured = { method, params };
+        throw new Error('captured');
+      }
+    };
+
+    const fetcher = createFetcher({ provider, stateOverride, gasLimit: 50000000n, blockTag: 65814031, customFeedsFile: './missing.json' });
+    const error = await fetcher.fetch().catch(err => err);
+
+    expect(error.message).toBe('captured');
+    expect(captured.method).toBe('eth_call');
+    const [tx, block, override] = captured.params;
+    expect(tx.gas).toBe('0x2faf080');
+    expect(block).toBe('0x3ec3e0f');
+    expect(override).toBe(stateOverride);
+  });
+
+  test('file sink names sharded snapshots by shard', () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'fetcher-'));
+    const timestamp = '2025-07-21T00:00:00.000Z';
+
+    fileSink({ dir }).write({ timestamp, shard: { index: 1, count: 4 }, prices: [] });
+
+    expect(fs.existsSync(path.join(dir, `avalanche_prices_${Date.parse(timestamp)}_shard1of4.json`))).toBe(true);
+    fs.rmSync(dir, { recursive: true });
+  });
+
+  test('file sink writes the snapshot named after its timestamp', () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'fetcher-'));
+    const clock = fixedClock('2025-07-21T00:00:00Z');
+
+    fileSink({ dir }).write({ timestamp: new Date(clock.now()).toISOString(), prices: [] });
+
+    const file = path.join(dir, `avalanche_prices_${clock.now()}.json`);
+    expect(JSON.parse(fs.readFileSync(file, 'utf8')).prices).toEqual([]);
+    fs.rmSync(dir, { recursive: true });
+  });
+
+  test('planCatchUp samples the gap at the interval and interpolates blocks', () => {
+    const last = { blockNumber: '1000', blockTimestamp: '2025-07-21T00:00:00.000Z' };
+    const head = { number: 1900, timestamp: Date.parse('2025-07-21T00:15:00Z') / 1000 };
+
+    const { samples, skipped } = planCatchUp(last, head, 5 * 60 * 1000);
+    expect(skipped).toBe(0);
+    expect(samples).toEqual([
+      { at: Date.parse('2025-07-21T00:05:00Z'), blockTag: 1300 },
+      { at: Date.parse('2025-07-21T00:10:00Z'), blockTag: 1600 }
+    ]);
+  });
+
+  test('planCatchUp keeps only the newest samples beyond the limit', () => {
+    const last = { blockNumber: '1000', blockTimestamp: '2025-07-21T00:00:00.000Z' };
+    const head = { number: 2000, timestamp: Date.parse('2025-07-21T01:00:00Z') / 1000 };
+
+    const { samples, skipped } = planCatchUp(last, head, 5 * 60 * 1000, 3);
+    expect(skipped).toBe(8);
+    expect(samples.map(sample => new Date(sample.at).toISOString())).toEqual([
+      '2025-07-21T00:45:00.000Z', '2025-07-21T00:50:00.000Z', '2025-07-21T00:55:00.000Z'
+    ]);
+    expect(planCatchUp(last, { number: 1000, timestamp: head.timestamp }, 300000).samples).toEqual([]);
+  });
+
+  test('rejects intervals that would never advance', async () => {
+    const last = { blockNumber: '1000', blockTimestamp: '2025-07-21T00:00:00.000Z' };
+    const head = { number: 1900, timestamp: Date.parse('2025-07-21T00:15:00Z') / 1000 };
+    [0, -300000, NaN, Infinity, 1.5].forEach(intervalMs => {
+      expect(() => planCatchUp(last, head, intervalMs)).toThrow(`intervalMs must be a positive integer, got ${intervalMs}`);
+    });
+
+    const fetcher = createFetcher({ provider: offlineProvider });
+    await expect(fetcher.run({ intervalMs: 0 })).rejects.toThrow('intervalMs must be a positive integer, got 0');
+    await expect(fetcher.run({ intervalMs: NaN, catchUp: true })).rejects.toThrow('intervalMs must be a positive integer, got NaN');
+    await expect(fetcher.catchUp({ intervalMs: undefined })).rejects.toThrow('intervalMs must be a positive integer, got undefined');
+  });
+
+  test('file sink reports the newest snapshot for its shard', () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'fetcher-'));
+    const sink = fileSink({ dir });
+    sink.write({ timestamp: '2025-07-21T00:00:00.000Z', blockNumber: '1', prices: [] });
+    sink.write({ timestamp: '2025-07-21T00:05:00.000Z', blockNumber: '2', prices: [] });
+    sink.write({ timestamp: '2025-07-21T00:10:00.000Z', blockNumber: '3', shard: { index: 0, count: 2 }, prices: [] });
+
+    expect(sink.last().blockNumber).toBe('2');
+    expect(sink.last({ shard: { index: 0, count: 2 } }).blockNumber).toBe('3');
+    expect(fileSink({ dir: path.join(dir, 'missing') }).last()).toBeNull();
+    fs.rmSync(dir, { recursive: true });
+  });
+
+  test('catch-up reads each missed sample at its estimated block', async () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'fetcher-'));
+    fs.writeFileSync(path.join(dir, `avalanche_prices_${Date.parse('2025-07-21T00:00:00Z')}.json`), JSON.stringify({
+      blockNumber: '1000', blockTimestamp: '2025-07-21T00:00:00.000Z', timestamp: '2025-07-21T00:00:00.000Z', prices: []
+    }));
+
+    const blocks = [];
+    const provider = {
+      getBlock: async () => ({ number: 1900, timestamp: Date.parse('2025-07-21T00:15:00Z') / 1000 }),
+      send: async (method, params) => {
+        blocks.push(params[1]);
+        throw new Error('offline');
+      }
+    };
+
+    const fetcher = createFetcher({ provider, stateOverride: {}, sinks: [fileSink({ dir })], customFeedsFile: './missing.json' });
+    const snapshots = await fetcher.catchUp({ intervalMs: 5 * 60 * 1000 });
+
+    expect(snapshots).toEqual([]);
+    expect(blocks).toEqual(['0x514', '0x640']);
+    fs.rmSync(dir, { recursive: true });
+  });
+
+  test('catch-up never resumes from another chain', async () => {
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'fetcher-'));
+    fs.writeFileSync(path.join(dir, `avalanche_prices_${Date.parse('2025-07-21T00:00:00Z')}.json`), JSON.stringify({
+      blockNumber: '1000', blockTimestamp: '2025-07-21T00:00:00.000Z', timestamp: '2025-07-21T00:00:00.000Z',
+      chain: 'fuji', chainId: 43113, testnet: true, prices: []
+    }));
+
+    const provider = {
+      getBlock: async () => ({ number: 1900, timestamp: Date.parse('2025-07-21T00:15:00Z') / 1000 }),
+      send: async () => {
+        throw new Error('should not read');
+      }
+    };
+
+    const fetcher = createFetcher({ provider, chain: 'avalanche', stateOverride: {}, sinks: [fileSink({ dir })], customFeedsFile: './missing.json' });
+    expect(await fetcher.catchUp({ intervalMs: 5 * 60 * 1000 })).toEqual([]);
+    fs.rmSync(dir, { recursive: true });
+  });
+});
@@ -0,0 +1,112 @@
+// Feed file generation tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { ethers } = require('ethers');
+const { readAddresses, buildRow, toCsv, toYaml, scrapeRegistry, FEED_REGISTRY_INTERFACE } = require('../scripts/generate-feeds');
+const { MULTICALL3_AGGREGATE3_INTERFACE } = require('../scripts/refresh-metadata');
+
+describe('Feed File Generation', () => {
+  const btc = {
+    proxy: '0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743',
+    description: 'BTC / USD',
+    decimals: 8n,
+    aggregator: '0x9450A29eF091B625e976cE68933A5e0F5a0bD2F0'
+  };
+
+  test('script exists and is executable', () => {
+    const stats = fs.statSync('./scripts/generate-feeds.js');
+    expect(stats.mode & parseInt('111', 8)).toBeTruthy();
+  });
+
+  test('reads addresses from arguments and files, dropping duplicates', () => {
+    const file = path.join(fs.mkdtempSync(path.join(os.tmpdir(), 'feeds-')), 'addresses.txt');
+    fs.writeFileSync(file, `# BTC\n${btc.proxy.toLowerCase()}\n\n0x976B3D034E162d8bD72D6b9C989d545b839003b0 # ETH\n`);
+
+    const addresses = readAddresses([btc.proxy], file);
+    expect(addresses).toHaveLength(2);
+    expect(() => readAddresses(['0x1234'])).toThrow('Not an address: 0x1234');
+  });
+
+  test('derives new rows from on-chain data', () => {
+    const row = buildRow(btc, undefined);
+
+    expect(row).toMatchObject({
+      name: 'BTC / USD',
+      contract_address: btc.aggregator,
+      proxy_address: btc.proxy,
+      decimals: '8',
+      path: 'btc-usd',
+      ens: 'btc-usd',
+      base_asset: 'BTC',
+      quote_asset: 'USD',
+      heartbeat: '',
+      deviation_threshold: ''
+    });
+  });
+
+  test('carries off-chain columns over from an existing feed file', () => {
+    const existing = { heartbeat: '3600', deviation_threshold: '0.1', asset_class: 'Crypto', path: 'btc-usd', ens: 'btc-usd' };
+    const row = buildRow({ ...btc, aggregator: null }, { ...existing, contract_address: '0x0000000000000000000000000000000000000001' });
+
+    expect(row.heartbeat).toBe('3600');
+    expect(row.deviation_threshold).toBe('0.1');
+    expect(row.asset_class).toBe('Crypto');
+    expect(row.contract_address).toBe('0x0000000000000000000000000000000000000001');
+  });
+
+  test('keeps kind overrides and only then writes the kind column', () => {
+    const overridden = buildRow(btc, { kind: 'index' });
+    expect(overridden.kind).toBe('index');
+    expect(toCsv([overridden]).split('\n')[0].endsWith(',quote_asset,kind')).toBe(true);
+    expect(toCsv([buildRow(btc, undefined)]).split('\n')[0].endsWith(',quote_asset')).toBe(true);
+  });
+
+  test('writes CSV in the feed file layout and quotes awkward names', () => {
+    const header = fs.readFileSync('./avalanche_chainlink_feeds.csv', 'utf8').split('\n')[0].trim();
+    const output = toCsv([buildRow({ ...btc, description: 'GLV [AVAX, USDC] / USD' }, undefined)]);
+    const [firstLine, secondLine] = output.split('\n');
+
+    expect(firstLine).toBe(header);
+    expect(secondLine.startsWith('"GLV [AVAX, USDC] / USD",')).toBe(true);
+  });
+
+  test('scrapes the registry in block pages and resolves each pair through getFeed', async () => {
+    const registry = '0x47Fb2585D2C56Fe188D0E6ec628a38b74fCeeeDf';
+    const [weth, usd, link] = ['0x0000000000000000000000000000000000000001', '0x0000000000000000000000000000000000000348', '0x0000000000000000000000000000000000000002'];
+    const confirmed = (base, quote, aggregator) => FEED_REGISTRY_INTERFACE.encodeEventLog('FeedConfirmed', [base, quote, aggregator, ethers.ZeroAddress, 1, registry]);
+    const served = { [weth]: btc.proxy };
+    const ranges = [];
+
+    const provider = {
+      getBlockNumber: async () => 25000,
+      getLogs: async ({ fromBlock, toBlock }) => {
+        ranges.push([fromBlock, toBlock]);
+        if (fromBlock === 10000) return [confirmed(weth, usd, '0x0000000000000000000000000000000000000010')];
+        if (fromBlock === 20000) return [confirmed(weth, usd, '0x0000000000000000000000000000000000000011'), confirmed(link, usd, '0x0000000000000000000000000000000000000012')];
+        return [];
+      },
+      call: async tx => {
+        const [calls] = MULTICALL3_AGGREGATE3_INTERFACE.decodeFunctionData('aggregate3', tx.data);
+        return MULTICALL3_AGGREGATE3_INTERFACE.encodeFunctionResult('aggregate3', [calls.map(call => {
+          const [base] = FEED_REGISTRY_INTERFACE.decodeFunctionData('getFeed', call.callData);
+          // A removed feed reverts with "Feed not found"
+          return served[base] ? [true, FEED_REGISTRY_INTERFACE.encodeFunctionResult('getFeed', [served[base]])] : [false, '0x'];
+        })]);
+      }
+    };
+
+    const feeds = await scrapeRegistry(provider, registry, { fromBlock: 5000, pageSize: 5000 });
+
+    expect(ranges).toEqual([[5000, 9999], [10000, 14999], [15000, 19999], [20000, 24999], [25000, 25000]]);
+    expect(feeds).toEqual([{ base: weth, quote: usd, feed: btc.proxy }]);
+    await expect(scrapeRegistry(provider, registry, { pageSize: 0 })).rejects.toThrow('positive number of blocks');
+  });
+
+  test('writes YAML with one mapping per feed', () => {
+    const yaml = toYaml([buildRow(btc, undefined)]);
+
+    expect(yaml).toContain('feeds:\n  - name: "BTC / USD"\n    contract_address:');
+    expect(yaml).toContain('    decimals: "8"');
+  });
+});
@@ -0,0 +1,81 @@
+// Deployment profile tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { profileEnv, loadProfile, applyProfile } = require('../profiles');
+
+describe('Deployment Profiles', () => {
+  let dir;
+  let file;
+
+  beforeAll(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'profiles-'));
+    file = path.join(dir, 'profiles.json');
+    fs.writeFileSync(file, JSON.stringify({
+      profiles: {
+        'prod-avalanche': {
+          chain: 'avalanche',
+          rpcUrl: 'https://rpc.example/avax',
+          feedsFile: './feeds.csv',
+          sinks: { file: { dir: './snapshots', verify: true }, binary: { file: '/var/log/snapshots.pb' } },
+          alerting: { silencesFile: './silences.json', updateTolerance: 2 },
+          env: { GAS_LIMIT: 30000000 }
+        }
+      }
+    }));
+  });
+
+  afterAll(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('maps profile settings to environment variables', () => {
+    expect(profileEnv('p', {
+      chain: 'avalanche',
+      customFeedsFile: 'custom.json',
+      sinks: { file: { verify: true }, binary: { file: 'log.pb', verify: true } },
+      alerting: { updateTolerance: 1.5 }
+    })).toEqual({
+      CHAIN: 'avalanche',
+      CUSTOM_FEEDS: 'custom.json',
+      SNAPSHOT_LOG: 'log.pb',
+      UPDATE_TOLERANCE: '1.5',
+      VERIFY_WRITES: 'file,binary'
+    });
+  });
+
+  test('resolves relative paths against the profiles file', () => {
+    const vars = loadProfile('prod-avalanche', file);
+
+    expect(vars.FEEDS_FILE).toBe(path.join(dir, 'feeds.csv'));
+    expect(vars.SNAPSHOT_DIR).toBe(path.join(dir, 'snapshots'));
+    expect(vars.SNAPSHOT_LOG).toBe('/var/log/snapshots.pb');
+    expect(vars.RPC_URL).toBe('https://rpc.example/avax');
+    expect(vars.GAS_LIMIT).toBe('30000000');
+    expect(vars.VERIFY_WRITES).toBe('file');
+  });
+
+  test('variables already set win over the profile', () => {
+    const env = { PROFILES_FILE: file, RPC_URL: 'http://localhost:9650/ext/bc/C/rpc' };
+    const applied = applyProfile('prod-avalanche', env);
+
+    expect(env.RPC_URL).toBe('http://localhost:9650/ext/bc/C/rpc');
+    expect(applied.RPC_URL).toBeUndefined();
+    expect(env.CHAIN).toBe('avalanche');
+    expect(env.UPDATE_TOLERANCE).toBe('2');
+  });
+
+  test('rejects unknown profiles and settings', () => {
+    expect(() => loadProfile('staging', file)).toThrow('Unknown profile "staging"');
+    expect(() => loadProfile('staging', file)).toThrow('available: prod-avalanche');
+    expect(() => profileEnv('p', { rpc: 'x' })).toThrow('Profile p: unknown setting "rpc"');
+    expect(() => profileEnv('p', { sinks: { mongo: {} } })).toThrow('unknown sink "mongo"');
+    expect(() => profileEnv('p', { alerting: { webhook: 'x' } })).toThrow('unknown alerting setting "webhook"');
+  });
+
+  test('the shipped profiles file is valid', () => {
+    const vars = loadProfile('prod-avalanche', path.join(__dirname, '..', 'profiles.json'));
+    expect(vars.CHAIN).toBe('avalanche');
+    expect(fs.existsSync(vars.FEEDS_FILE)).toBe(true);
+  });
+});
@@ -0,0 +1,121 @@
+// Snapshot query tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { query, parseSince, loadStoredSnapshots, findFeed, latest, history, stats, formatTable } = require('../scripts/query');
+const { buildHistory, loadSnapshots } = require('../scripts/export');
+
+describe('Snapshot Query', () => {
+  let dir;
+  const at = Date.parse('2025-07-21T00:00:00Z');
+
+  function snapshot(ms, prices, suffix = '') {
+    const data = {
+      blockNumber: String(ms / 1000),
+      blockTimestamp: new Date(ms).toISOString(),
+      timestamp: new Date(ms).toISOString(),
+      totalFeeds: prices.length,
+      prices
+    };
+    fs.writeFileSync(path.join(dir, `avalanche_prices_${ms}${suffix}.json`), JSON.stringify(data));
+  }
+
+  beforeAll(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'query-'));
+    snapshot(at, [
+      { name: 'BTC / USD', proxy: '0xAAA', price: 100, roundId: '1', updatedAt: '2025-07-20T23:59:00.000Z', ageAtBlock: 60 },
+      { name: 'AVAX / USD', proxy: '0xBBB', price: 20, roundId: '7', ageAtBlock: 10 }
+    ]);
+    snapshot(at + 3600000, [
+      { name: 'BTC / USD', proxy: '0xAAA', price: 100, roundId: '1', ageAtBlock: 3660 },
+      { name: 'AVAX / USD', proxy: '0xBBB', error: 'reverted' }
+    ]);
+    snapshot(at + 7200000, [{ name: 'BTC / USD', proxy: '0xAAA', price: 130, roundId: '2', ageAtBlock: 30 }], '_shard0of2');
+  });
+
+  afterAll(() => {
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('parses relative and absolute --since values', () => {
+    const now = Date.parse('2025-07-22T00:00:00Z');
+    expect(parseSince('24h', now)).toBe(Date.parse('2025-07-21T00:00:00Z'));
+    expect(parseSince('30m', now)).toBe(now - 30 * 60 * 1000);
+    expect(parseSince('1w', now)).toBe(now - 7 * 24 * 60 * 60 * 1000);
+    expect(parseSince('2025-07-21', now)).toBe(Date.parse('2025-07-21T00:00:00Z'));
+    expect(parseSince(undefined, now)).toBeNull();
+    expect(() => parseSince('2025-07-21T00:00:00', now)).toThrow('explicit UTC offset');
+  });
+
+  test('finds feeds by loose name or proxy', async () => {
+    const feeds = buildHistory(await loadStoredSnapshots({ dir }));
+    expect(findFeed(feeds, 'btc/usd').name).toBe('BTC / USD');
+    expect(findFeed(feeds, '0xbbb').name).toBe('AVAX / USD');
+    expect(() => findFeed(feeds, 'ETH / USD')).toThrow('No stored readings for feed ETH / USD');
+  });
+
+  test('latest returns the newest reading per feed, including sharded files', async () => {
+    const feeds = buildHistory(await loadStoredSnapshots({ dir }));
+    const rows = latest(feeds);
+
+    expect(rows.map(row => [row.feed, row.price])).toEqual([['AVAX / USD', 20], ['BTC / USD', 130]]);
+    expect(latest(feeds, 'AVAXUSD')[0].sampledAt).toBe('2025-07-21T00:00:00.000Z');
+  });
+
+  test('history honours the time range', async () => {
+    const feeds = buildHistory(await loadStoredSnapshots({ dir, from: at + 1800000 }));
+    expect(history(feeds, 'BTC / USD').map(row => row.price)).toEqual([100, 130]);
+  });
+
+  test('stats counts distinct rounds and summarizes freshness', async () => {
+    const feeds = buildHistory(await loadStoredSnapshots({ dir }));
+    const result = stats(feeds, 'BTC / USD');
+
+    expect(result.observations).toBe(3);
+    expect(result.rounds).toBe(2);
+    expect(result.mean).toBe(110);
+    expect(result.min).toBe(100);
+    expect(result.max).toBe(130);
+    expect(result.maxAgeAtBlock).toBe(3660);
+    expect(result.meanAgeAtBlock).toBe(1250);
+  });
+
+  test('formats rows as an aligned table', () => {
+    expect(formatTable([{ feed: 'BTC / USD', price: 1 }, { feed: 'AVAX', price: 20 }]).split('\n')).toEqual([
+      'feed       price',
+      '---------  -----',
+      'BTC / USD  1',
+      'AVAX       20'
+    ]);
+  });
+
+  test('--chain keeps one chain and mixed chains are refused', async () => {
+    const mixed = fs.mkdtempSync(path.join(os.tmpdir(), 'query-'));
+    const write = (ms, extra) => fs.writeFileSync(path.join(mixed, `avalanche_prices_${ms}.json`), JSON.stringify({
+      blockNumber: '1', blockTimestamp: new Date(ms).toISOString(), timestamp: new Date(ms).toISOString(),
+      prices: [{ name: 'AVAX / USD', proxy: '0xBBB', price: 20 }], ...extra
+    }));
+    write(at, {});
+    write(at + 60000, { chain: 'fuji', chainId: 43113, testnet: true });
+
+    expect(() => buildHistory(loadSnapshots(mixed))).toThrow('several chains');
+    expect(await loadStoredSnapshots({ dir: mixed, chain: 'fuji' })).toHaveLength(1);
+    expect(await loadStoredSnapshots({ dir: mixed, chain: 'avalanche' })).toHaveLength(1);
+
+    const chain = process.env.CHAIN;
+    process.env.CHAIN = 'fuji';
+    try {
+      const rows = await query(['latest', '--dir', mixed, '--json']);
+      expect(rows.map(row => row.sampledAt)).toEqual([new Date(at + 60000).toISOString()]);
+    } finally {
+      if (chain === undefined) delete process.env.CHAIN; else process.env.CHAIN = chain;
+    }
+    expect((await query(['latest', '--dir', mixed, '--chain', 'sepolia']).catch(err => err)).message).toContain('Unknown chain "sepolia"');
+    fs.rmSync(mixed, { recursive: true, force: true });
+  });
+
+  test('rejects unknown commands and missing feeds', async () => {
+    expect((await query(['nope', '--dir', dir]).catch(err => err)).message).toContain('Usage: query');
+    expect((await query(['stats', '--dir', dir]).catch(err => err)).message).toContain('stats needs a feed');
+  });
+});
@@ -0,0 +1,133 @@
+// Metadata refresh tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { ethers } = require('ethers');
+const {
+  diffMetadata,
+  findAggregatorMismatches,
+  refreshMetadata,
+  buildProxyCalls,
+  buildBoundsCalls,
+  decodeMetadata,
+  METADATA_INTERFACE,
+  MULTICALL3_AGGREGATE3_INTERFACE
+} = require('../scripts/refresh-metadata');
+
+describe('Metadata Refresh', () => {
+  const entry = {
+    name: 'BTC / USD',
+    decimals: 8,
+    description: 'BTC / USD',
+    aggregator: '0x9450A29eF091B625e976cE68933A5e0F5a0bD2F0',
+    phaseId: 6,
+    minAnswer: '1',
+    maxAnswer: '95780971304118053647396689196894323976171195136475135'
+  };
+
+  test('script exists and is executable', () => {
+    const stats = fs.statSync('./scripts/refresh-metadata.js');
+    expect(stats.mode & parseInt('111', 8)).toBeTruthy();
+    expect(typeof refreshMetadata).toBe('function');
+  });
+
+  test('reports no changes when metadata is unchanged', () => {
+    expect(diffMetadata({ '0xabc': entry }, { '0xabc': { ...entry } })).toEqual([]);
+  });
+
+  test('reports changed fields with before and after values', () => {
+    const current = { '0xabc': { ...entry, phaseId: 7, aggregator: '0x0000000000000000000000000000000000000001' } };
+    const changes = diffMetadata({ '0xabc': entry }, current);
+
+    expect(changes).toHaveLength(2);
+    expect(changes[0]).toMatchObject({ name: 'BTC / USD', type: 'changed', field: 'aggregator' });
+    expect(changes[1]).toMatchObject({ field: 'phaseId', from: 6, to: 7 });
+  });
+
+  test('reports added and removed feeds', () => {
+    const changes = diffMetadata({ '0xold': { ...entry, name: 'OLD / USD' } }, { '0xabc': entry });
+
+    expect(changes).toEqual([
+      { proxy: '0xabc', name: 'BTC / USD', type: 'added' },
+      { proxy: '0xold', name: 'OLD / USD', type: 'removed' }
+    ]);
+  });
+
+  test('flags feeds whose on-chain aggregator differs from the CSV', () => {
+    const feeds = [
+      { name: 'BTC / USD', proxyAddress: '0xABC', contractAddress: entry.aggregator.toLowerCase() },
+      { name: 'ETH / USD', proxyAddress: '0xDEF', contractAddress: '0x0000000000000000000000000000000000000002' }
+    ];
+    const metadata = { '0xabc': entry, '0xdef': { ...entry, name: 'ETH / USD' } };
+
+    expect(findAggregatorMismatches(feeds, metadata).map(feed => feed.name)).toEqual(['ETH / USD']);
+  });
+
+  test('reads bounds from the aggregator the proxy reports, not the CSV', () => {
+    const upgraded = '0x0000000000000000000000000000000000000003';
+    const feeds = [
+      { name: 'BTC / USD', proxyAddress: '0xABC', contractAddress: entry.aggregator },
+      { name: 'ETH / USD', proxyAddress: '0xDEF', contractAddress: '0x0000000000000000000000000000000000000002' }
+    ];
+    const ok = (field, value) => [true, METADATA_INTERFACE.encodeFunctionResult(field, [value])];
+    const proxyResults = [
+      ok('decimals', 8), ok('description', 'BTC / USD'), ok('aggregator', upgraded), ok('phaseId', 7),
+      ok('decimals', 8), ok('description', 'ETH / USD'), [false, '0x'], ok('phaseId', 2)
+    ];
+
+    expect(buildProxyCalls(feeds).map(call => call.target)).toEqual(Array(4).fill('0xABC').concat(Array(4).fill('0xDEF')));
+    expect(buildBoundsCalls(feeds, proxyResults).map(call => call.target))
+      .toEqual([upgraded, upgraded, feeds[1].contractAddress, feeds[1].contractAddress]);
+
+    const boundsResults = [ok('minAnswer', 1n), ok('maxAnswer', 10n ** 12n), [false, '0x'], [false, '0x']];
+    const metadata = decodeMetadata(feeds, proxyResults, boundsResults);
+    expect(metadata['0xabc']).toMatchObject({ aggregator: ethers.getAddress(upgraded), phaseId: 7, minAnswer: '1', maxAnswer: '1000000000000' });
+    expect(metadata['0xdef']).toMatchObject({ aggregator: null, minAnswer: null, maxAnswer: null });
+  });
+
+  test('a Fuji refresh writes its own file and leaves mainnet metadata alone', async () => {
+    const cwd = process.cwd();
+    const env = { CHAIN: process.env.CHAIN, FEEDS_FILE: process.env.FEEDS_FILE, FEED_METADATA: process.env.FEED_METADATA };
+    const dir = fs.mkdtempSync(path.join(os.tmpdir(), 'metadata-'));
+    const proxy = '0x0000000000000000000000000000000000000abc';
+    fs.writeFileSync(path.join(dir, 'fuji_chainlink_feeds.csv'), [
+      'name,contract_address,proxy_address,deviation_threshold,heartbeat,decimals,asset_class,product_name,ens,path,base_asset,quote_asset',
+      `BTC / USD,${entry.aggregator},${proxy},0.1,86400,8,Crypto,,,,BTC,USD`
+    ].join('\n'));
+    const mainnet = JSON.stringify({ refreshedAt: '2025-07-01T00:00:00.000Z', feeds: { '0xabc': entry } });
+    fs.writeFileSync(path.join(dir, 'feed_metadata.json'), mainnet);
+
+    // Every read succeeds with the value for its field
+    const values = { decimals: 8, description: 'BTC / USD', aggregator: entry.aggregator, phaseId: 1, minAnswer: 1n, maxAnswer: 10n };
+    const provider = {
+      getBlockNumber: async () => 1000,
+      call: async tx => {
+        const [calls] = MULTICALL3_AGGREGATE3_INTERFACE.decodeFunctionData('aggregate3', tx.data);
+        const results = calls.map(({ callData }) => {
+          const field = METADATA_INTERFACE.getFunction(callData.slice(0, 10)).name;
+          return [true, METADATA_INTERFACE.encodeFunctionResult(field, [values[field]])];
+        });
+        return MULTICALL3_AGGREGATE3_INTERFACE.encodeFunctionResult('aggregate3', [results]);
+      }
+    };
+
+    process.chdir(dir);
+    process.env.CHAIN = 'fuji';
+    delete process.env.FEEDS_FILE;
+    delete process.env.FEED_METADATA;
+    try {
+      const { changes } = await refreshMetadata(undefined, { provider });
+      expect(changes).toEqual([{ proxy, name: 'BTC / USD', type: 'added' }]);
+
+      const fuji = JSON.parse(fs.readFileSync(path.join(dir, 'fuji_feed_metadata.json'), 'utf8'));
+      expect(fuji.feeds[proxy]).toMatchObject({ description: 'BTC / USD', phaseId: 1, maxAnswer: '10' });
+      expect(fs.readFileSync(path.join(dir, 'feed_metadata.json'), 'utf8')).toBe(mainnet);
+    } finally {
+      process.chdir(cwd);
+      for (const [key, value] of Object.entries(env)) {
+        if (value === undefined) delete process.env[key]; else process.env[key] = value;
+      }
+      fs.rmSync(dir, { recursive: true, force: true });
+    }
+  });
+});
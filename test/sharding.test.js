@@ -0,0 +1,39 @@
+// Feed sharding tests
+const { shardOf, inShard, validateShard, shardOptions } = require('../sharding');
+const { loadFeedData } = require('../multicall_price_fetcher');
+
+describe('Feed Sharding', () => {
+  test('every feed lands in exactly one shard', async () => {
+    const feeds = await loadFeedData();
+    const count = 4;
+
+    const shards = Array.from({ length: count }, (_, index) =>
+      feeds.filter(feed => inShard(feed.proxyAddress, { index, count })));
+
+    expect(shards.reduce((sum, shard) => sum + shard.length, 0)).toBe(feeds.length);
+    expect(new Set(shards.flat().map(feed => feed.proxyAddress)).size).toBe(feeds.length);
+    shards.forEach(shard => expect(shard.length).toBeGreaterThan(0));
+  });
+
+  test('assignment ignores address case', () => {
+    const address = '0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743';
+    expect(shardOf(address, 8)).toBe(shardOf(address.toLowerCase(), 8));
+    expect(shardOf(address, 1)).toBe(0);
+  });
+
+  test('no shard means every feed', () => {
+    expect(inShard('0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743', undefined)).toBe(true);
+  });
+
+  test('rejects out-of-range shards', () => {
+    expect(() => validateShard({ index: 0, count: 0 })).toThrow('Shard count must be a positive integer');
+    expect(() => validateShard({ index: 4, count: 4 })).toThrow('from 0 to 3');
+    expect(() => validateShard({ index: -1, count: 4 })).toThrow('from 0 to 3');
+  });
+
+  test('reads SHARD_INDEX and SHARD_COUNT', () => {
+    expect(shardOptions({})).toEqual({});
+    expect(shardOptions({ SHARD_INDEX: '1', SHARD_COUNT: '3' })).toEqual({ shard: { index: 1, count: 3 } });
+    expect(() => shardOptions({ SHARD_COUNT: '3' })).toThrow('both SHARD_INDEX and SHARD_COUNT');
+  });
+});
@@ -0,0 +1,129 @@
+// Binary snapshot log tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const { binarySink, readSnapshotLog } = require('../snapshot_log');
+
+describe('Binary Snapshot Log', () => {
+  let dir;
+
+  const snapshot = (blockNumber, blockTimestamp, answer) => ({
+    blockNumber: String(blockNumber),
+    blockTimestamp,
+    timestamp: '2025-07-21T00:00:05.000Z',
+    totalFeeds: 3,
+    prices: [
+      {
+        name: 'BTC / USD',
+        proxy: '0x2779D32d5166BAaa2B2b658333bA7e6Ec0C65743',
+        source: 'chainlink',
+        kind: 'price',
+        price: Number(answer) / 1e8,
+        exactPrice: '',
+        decimals: 8,
+        roundId: '18446744073709572000',
+        updatedAt: '2025-07-20T23:59:00.000Z',
+        ageAtBlock: 60,
+        raw: { answer, startedAt: '1753055940', updatedAt: '1753055940', answeredInRound: '18446744073709572000' }
+      },
+      { name: 'ETH / USD', proxy: '0x976B3D034E162d8bD72D6b9C989d545b839003b0', error: 'Return data too short' },
+      {
+        name: 'ggAVAX',
+        proxy: '0xA25EaF2906FA1a3a13EdAc9B9657108Af7B703e3',
+        source: 'erc4626',
+        kind: 'rate',
+        method: 'convertToAssets(uint256)',
+        price: 1.05,
+        decimals: 18,
+        raw: { value: '1050000000000000000' }
+      }
+    ]
+  });
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'snapshot-log-'));
+  });
+
+  afterEach(() => {
+    fs.rmSync(dir, { recursive: true });
+  });
+
+  const readAll = async (file, options) => {
+    const snapshots = [];
+    for await (const entry of readSnapshotLog(file, options)) {
+      snapshots.push(entry);
+    }
+    return snapshots;
+  };
+
+  test('round-trips every cycle in order', async () => {
+    const file = path.join(dir, 'log.pb');
+    const sink = binarySink({ file });
+    sink.write(snapshot(65814031, '2025-07-21T00:00:00.000Z', '11850012345678'));
+    sink.write(snapshot(65814131, '2025-07-21T00:05:00.000Z', '11860000000000'));
+
+    const snapshots = await readAll(file);
+    expect(snapshots.map(s => s.blockNumber)).toEqual(['65814031', '65814131']);
+
+    const [btc, eth, ggavax] = snapshots[0].prices;
+    expect(btc.exactPrice).toBe('118500.12345678');
+    expect(btc.updatedAt).toBe('2025-07-20T23:59:00.000Z');
+    expect(btc.ageAtBlock).toBe(60);
+    expect(btc.roundId).toBe('18446744073709572000');
+    expect(eth.error).toBe('Return data too short');
+    expect(ggavax.raw.value).toBe('1050000000000000000');
+    expect(ggavax.method).toBe('convertToAssets(uint256)');
+  });
+
+  test('keeps the chain tag, block hash and RPC origin and leaves untagged records untagged', async () => {
+    const file = path.join(dir, 'log.pb');
+    const sink = binarySink({ file });
+    const tag = { chain: 'fuji', chainId: 43113, testnet: true, blockHash: `0x${'ab'.repeat(32)}`, rpcUrl: 'https://api.avax-test.network' };
+    sink.write({ ...snapshot(1, '2025-07-21T00:00:00.000Z', '1'), ...tag });
+    sink.write(snapshot(2, '2025-07-21T00:05:00.000Z', '1'));
+
+    const [tagged, untagged] = await readAll(file);
+    expect(tagged).toMatchObject(tag);
+    expect(untagged.chain).toBeUndefined();
+    expect(untagged.blockHash).toBeUndefined();
+    expect(untagged.rpcUrl).toBeUndefined();
+  });
+
+  test('keeps catch-up and shard markers, and reports the newest snapshot per shard', async () => {
+    const file = path.join(dir, 'log.pb');
+    const sink = binarySink({ file });
+    sink.write({ ...snapshot(1, '2025-07-21T00:00:00.000Z', '1'), shard: { index: 0, count: 2 }, backfill: true });
+    sink.write({ ...snapshot(2, '2025-07-21T00:00:00.000Z', '1'), shard: { index: 1, count: 2 } });
+    sink.write(snapshot(3, '2025-07-21T00:05:00.000Z', '1'));
+
+    const [backfilled, sharded, whole] = await readAll(file);
+    expect(backfilled.shard).toEqual({ index: 0, count: 2 });
+    expect(backfilled.backfill).toBe(true);
+    expect(sharded.backfill).toBeUndefined();
+    expect(whole.shard).toBeUndefined();
+
+    expect((await sink.last({ shard: { index: 0, count: 2 } })).blockNumber).toBe('1');
+    expect((await sink.last({ shard: { index: 1, count: 2 } })).blockNumber).toBe('2');
+    expect((await sink.last()).blockNumber).toBe('3');
+    expect(await sink.last({ shard: { index: 0, count: 4 } })).toBeNull();
+  });
+
+  test('filters by block time', async () => {
+    const file = path.join(dir, 'log.pb');
+    const sink = binarySink({ file });
+    sink.write(snapshot(1, '2025-07-21T00:00:00.000Z', '1'));
+    sink.write(snapshot(2, '2025-07-22T00:00:00.000Z', '2'));
+
+    const snapshots = await readAll(file, { from: Date.parse('2025-07-21T12:00:00Z') });
+    expect(snapshots.map(s => s.blockNumber)).toEqual(['2']);
+  });
+
+  test('reports a truncated trailing record', async () => {
+    const file = path.join(dir, 'log.pb');
+    binarySink({ file }).write(snapshot(1, '2025-07-21T00:00:00.000Z', '1'));
+    fs.appendFileSync(file, Buffer.from([0x20, 0x01]));
+
+    const error = await readAll(file).catch(err => err);
+    expect(error.message).toContain('truncated record');
+  });
+});
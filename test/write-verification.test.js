@@ -0,0 +1,160 @@
+// Write verification tests
+const fs = require('fs');
+const os = require('os');
+const path = require('path');
+const {
+  checksum,
+  verifyWrite,
+  createVerificationStats,
+  verifyOptions,
+  readVerificationMetrics,
+  exportVerificationMetrics,
+  WriteVerificationError
+} = require('../write_verification');
+const { fileSink } = require('../multicall_price_fetcher');
+const { binarySink, readSnapshotLog } = require('../snapshot_log');
+
+describe('Write Verification', () => {
+  let dir;
+  const writeFileSync = fs.writeFileSync;
+  const appendFileSync = fs.appendFileSync;
+
+  const snapshot = {
+    blockNumber: '65814031',
+    blockTimestamp: '2025-07-21T00:00:00.000Z',
+    timestamp: '2025-07-21T00:00:05.000Z',
+    totalFeeds: 1,
+    prices: [{ name: 'ETH / USD', proxy: '0x976B3D034E162d8bD72D6b9C989d545b839003b0', error: 'Return data too short' }]
+  };
+
+  // Simulates storage that acknowledges a write but keeps only part of it
+  const truncating = write => (file, data, ...rest) => write(file, data.subarray(0, data.length - 3), ...rest);
+
+  beforeEach(() => {
+    dir = fs.mkdtempSync(path.join(os.tmpdir(), 'verify-'));
+  });
+
+  afterEach(() => {
+    fs.writeFileSync = writeFileSync;
+    fs.appendFileSync = appendFileSync;
+    fs.rmSync(dir, { recursive: true, force: true });
+  });
+
+  test('verifyWrite counts matching read-backs', () => {
+    const stats = createVerificationStats();
+    verifyWrite('memory', Buffer.from('abc'), () => Buffer.from('abc'), stats);
+
+    expect(stats).toEqual({ verified: 1, failures: 0, lastFailure: null });
+    expect(checksum(Buffer.from('abc'))).toBe('ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad');
+  });
+
+  test('verifyWrite treats a failed read-back as a mismatch', () => {
+    const stats = createVerificationStats();
+    const error = (() => {
+      try {
+        verifyWrite('gone.json', Buffer.from('abc'), () => { throw new Error('ENOENT'); }, stats);
+      } catch (err) {
+        return err;
+      }
+    })();
+
+    expect(error).toBeInstanceOf(WriteVerificationError);
+    expect(error.actual).toBeNull();
+    expect(stats.failures).toBe(1);
+    expect(stats.lastFailure.target).toBe('gone.json');
+  });
+
+  test('file sink verifies a clean write', () => {
+    const sink = fileSink({ dir, verify: true });
+    sink.write(snapshot);
+
+    expect(sink.verification.verified).toBe(1);
+    expect(sink.verification.failures).toBe(0);
+  });
+
+  test('file sink rejects a truncated write', () => {
+    fs.writeFileSync = truncating(writeFileSync);
+    const sink = fileSink({ dir, verify: true });
+
+    expect(() => sink.write(snapshot)).toThrow(WriteVerificationError);
+    expect(sink.verification.failures).toBe(1);
+  });
+
+  test('file sink skips read-back unless verify is set', () => {
+    fs.writeFileSync = truncating(writeFileSync);
+    const sink = fileSink({ dir });
+    sink.write(snapshot);
+
+    expect(sink.verification).toEqual({ verified: 0, failures: 0, lastFailure: null });
+  });
+
+  test('binary sink verifies only the record it appended', async () => {
+    const file = path.join(dir, 'snapshots.pb');
+    const sink = binarySink({ file, verify: true });
+    sink.write(snapshot);
+    sink.write({ ...snapshot, blockNumber: '65814032' });
+
+    expect(sink.verification.verified).toBe(2);
+    const blocks = [];
+    for await (const entry of readSnapshotLog(file)) {
+      blocks.push(entry.blockNumber);
+    }
+    expect(blocks).toEqual(['65814031', '65814032']);
+  });
+
+  test('binary sink rejects a truncated append', () => {
+    const file = path.join(dir, 'snapshots.pb');
+    const sink = binarySink({ file, verify: true });
+    sink.write(snapshot);
+
+    const size = fs.statSync(file).size;
+
+    fs.appendFileSync = truncating(appendFileSync);
+    expect(() => sink.write(snapshot)).toThrow(WriteVerificationError);
+    expect(sink.verification).toMatchObject({ verified: 1, failures: 1 });
+    expect(fs.statSync(file).size).toBe(size);
+  });
+
+  test('binary sink truncates a bad append so the log stays readable', async () => {
+    const file = path.join(dir, 'snapshots.pb');
+    const sink = binarySink({ file, verify: true });
+    sink.write(snapshot);
+
+    fs.appendFileSync = truncating(appendFileSync);
+    expect(() => sink.write({ ...snapshot, blockNumber: '65814032' })).toThrow(WriteVerificationError);
+    fs.appendFileSync = appendFileSync;
+    sink.write({ ...snapshot, blockNumber: '65814033' });
+
+    const blocks = [];
+    for await (const entry of readSnapshotLog(file)) {
+      blocks.push(entry.blockNumber);
+    }
+    expect(blocks).toEqual(['65814031', '65814033']);
+  });
+
+  test('verification counts accumulate in a Prometheus textfile', () => {
+    const file = path.join(dir, 'verification.prom');
+    exportVerificationMetrics(file, 'avalanche', { file: { verified: 2, failures: 0 }, binary: { verified: 1, failures: 1 } });
+    exportVerificationMetrics(file, 'avalanche', { file: { verified: 3, failures: 1 } });
+
+    const text = fs.readFileSync(file, 'utf8');
+    expect(text).toContain('# TYPE chainlink_snapshot_write_verification_failures_total counter');
+    expect(text).toContain('chainlink_snapshot_writes_verified_total{sink="file",chain="avalanche"} 5');
+    expect(readVerificationMetrics(file).get('chainlink_snapshot_write_verification_failures_total binary avalanche')).toBe(1);
+    expect(readVerificationMetrics(file).get('chainlink_snapshot_write_verification_failures_total file avalanche')).toBe(1);
+    expect(fs.readdirSync(dir)).toEqual(['verification.prom']);
+  });
+
+  test('VERIFY_WRITES enables verification', () => {
+    expect(verifyOptions({ VERIFY_WRITES: '1' })).toEqual({ verify: true });
+    expect(verifyOptions({ VERIFY_WRITES: 'true' })).toEqual({ verify: true });
+    expect(verifyOptions({})).toEqual({ verify: false });
+  });
+
+  test('VERIFY_WRITES can name individual sinks', () => {
+    expect(verifyOptions({ VERIFY_WRITES: 'file' }, 'file')).toEqual({ verify: true });
+    expect(verifyOptions({ VERIFY_WRITES: 'file' }, 'binary')).toEqual({ verify: false });
+    expect(verifyOptions({ VERIFY_WRITES: 'file, binary' }, 'binary')).toEqual({ verify: true });
+    expect(verifyOptions({ VERIFY_WRITES: '1' }, 'binary')).toEqual({ verify: true });
+  });
+});
@@ -44,6 +44,7 @@ const healthSchema = Joi.object({
 const feedMetadataSchema = Joi.object({
   name: Joi.string().required(),
   symbol: Joi.string().required(),
+  kind: Joi.string().valid('price', 'rate', 'index', 'por').required(),
   contractAddress: Joi.string().pattern(/^0x[a-fA-F0-9]{40}$/).required(),
   proxyAddress: Joi.string().pattern(/^0x[a-fA-F0-9]{40}$/).required(),
   decimals: Joi.number().integer().min(0).max(18).required(),
@@ -340,6 +341,27 @@ describe('Avalanche Chainlink API Comparison Tests', () => {
         expect(tsPrice.decimals).toBe(pyPrice.decimals);
       }
     }, 75000);
+
+    // Symbols are derived from feed names separately in each API; these cover every suffix rule
+    test.each(['BTCUSD', 'SAVAXAVAX_RATE', 'GGAVAXAVAX_RATE'])('GET /prices/%s resolves to the same feed on both APIs', async symbol => {
+      const tsResult = await makeRequest(TYPESCRIPT_API, `/prices/${symbol}`);
+      const pyResult = await makeRequest(PYTHON_API, `/prices/${symbol}`);
+
+      expect(tsResult.success).toBe(true);
+      expect(pyResult.success).toBe(true);
+      expect(tsResult.response.data.data.symbol).toBe(symbol);
+      expect(pyResult.response.data.data.symbol).toBe(symbol);
+    }, 45000);
+
+    test('GET /prices should list the same symbols on both APIs', async () => {
+      const tsResult = await makeRequest(TYPESCRIPT_API, '/prices');
+      const pyResult = await makeRequest(PYTHON_API, '/prices');
+
+      expect(tsResult.success).toBe(true);
+      expect(pyResult.success).toBe(true);
+      const symbols = result => result.response.data.data.map(price => price.symbol).sort();
+      expect(symbols(pyResult)).toEqual(symbols(tsResult));
+    }, 45000);
   });
 
   describe('Advanced Endpoint Tests', () => {
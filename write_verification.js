@@ -0,0 +1,133 @@
+// Read-your-writes verification for snapshot sinks
+// After each write the stored bytes are read back and their SHA-256 compared
+// with what was written, so silent truncation or corruption fails the cycle
+// instead of surfacing weeks later during a replay
+
+const fs = require('fs');
+const crypto = require('crypto');
+
+// Prometheus counters for the textfile collector, per sink and chain
+const VERIFICATION_METRICS = {
+  verified: {
+    name: 'chainlink_snapshot_writes_verified_total',
+    help: 'Snapshot writes whose read-back checksum matched'
+  },
+  failures: {
+    name: 'chainlink_snapshot_write_verification_failures_total',
+    help: 'Snapshot writes whose read-back checksum did not match'
+  }
+};
+const METRIC_LINE = /^(\w+)\{sink="([^"]*)",chain="([^"]*)"\} (\S+)$/;
+
+class WriteVerificationError extends Error {
+  constructor(target, expected, actual) {
+    super(`Write verification failed for ${target}: expected sha256 ${expected}, read back ${actual ?? 'nothing'}`);
+    this.name = 'WriteVerificationError';
+    this.target = target;
+    this.expected = expected;
+    this.actual = actual;
+  }
+}
+
+function checksum(bytes) {
+  return crypto.createHash('sha256').update(bytes).digest('hex');
+}
+
+// Per-sink counters; lastFailure holds the target and time of the latest mismatch
+function createVerificationStats() {
+  return { verified: 0, failures: 0, lastFailure: null };
+}
+
+// readBack returns the stored bytes; a read that throws counts as a failure
+function verifyWrite(target, written, readBack, stats = createVerificationStats()) {
+  const expected = checksum(written);
+  let actual = null;
+  try {
+    actual = checksum(readBack());
+  } catch {
+    actual = null;
+  }
+
+  if (actual !== expected) {
+    stats.failures++;
+    stats.lastFailure = { target, at: new Date().toISOString() };
+    throw new WriteVerificationError(target, expected, actual);
+  }
+  stats.verified++;
+}
+
+// Writes through a descriptor and fsyncs it before returning, so the read-back checks
+// what reached the disk rather than only the page cache; flags 'a' appends
+function writeSynced(file, bytes, flags = 'w') {
+  const fd = fs.openSync(file, flags);
+  try {
+    if (flags === 'a') {
+      fs.appendFileSync(fd, bytes);
+    } else {
+      fs.writeFileSync(fd, bytes);
+    }
+    fs.fsyncSync(fd);
+  } finally {
+    fs.closeSync(fd);
+  }
+}
+
+// Totals in a textfile written by exportVerificationMetrics, keyed by "metric sink chain"
+function readVerificationMetrics(file) {
+  const totals = new Map();
+  if (!fs.existsSync(file)) return totals;
+
+  fs.readFileSync(file, 'utf8').split('\n').forEach(line => {
+    const match = METRIC_LINE.exec(line);
+    if (match) {
+      totals.set(`${match[1]} ${match[2]} ${match[3]}`, Number(match[4]));
+    }
+  });
+  return totals;
+}
+
+// Adds counts since the previous export to the totals already in a Prometheus textfile
+// (node_exporter's textfile collector), so they outlive the one-shot CLI. counts maps
+// sink name to { verified, failures }. The file is replaced by rename, never half-written.
+function exportVerificationMetrics(file, chain, counts) {
+  const totals = readVerificationMetrics(file);
+  Object.entries(counts).forEach(([sink, count]) => {
+    Object.entries(VERIFICATION_METRICS).forEach(([field, metric]) => {
+      const key = `${metric.name} ${sink} ${chain}`;
+      totals.set(key, (totals.get(key) ?? 0) + count[field]);
+    });
+  });
+
+  const lines = Object.values(VERIFICATION_METRICS).flatMap(metric => [
+    `# HELP ${metric.name} ${metric.help}`,
+    `# TYPE ${metric.name} counter`,
+    ...[...totals.entries()]
+      .map(([key, value]) => [key.split(' '), value])
+      .filter(([[name]]) => name === metric.name)
+      .map(([[name, sink, labelChain], value]) => `${name}{sink="${sink}",chain="${labelChain}"} ${value}`)
+  ]);
+
+  const temporary = `${file}.${process.pid}.tmp`;
+  fs.writeFileSync(temporary, `${lines.join('\n')}\n`);
+  fs.renameSync(temporary, file);
+}
+
+// VERIFY_WRITES=1 turns verification on for all of the CLI's sinks; a list such
+// as VERIFY_WRITES=file,binary turns it on for the named sinks only
+function verifyOptions(env = process.env, sink) {
+  const setting = env.VERIFY_WRITES ?? '';
+  if (setting === '1' || setting === 'true') return { verify: true };
+  return { verify: sink !== undefined && setting.split(',').map(name => name.trim()).includes(sink) };
+}
+
+module.exports = {
+  WriteVerificationError,
+  checksum,
+  createVerificationStats,
+  verifyWrite,
+  verifyOptions,
+  writeSynced,
+  readVerificationMetrics,
+  exportVerificationMetrics,
+  VERIFICATION_METRICS
+};